@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
+	"github.com/infoHiroki/KoeMoji-Go/internal/processor"
+	"github.com/infoHiroki/KoeMoji-Go/internal/transcript"
+)
+
+// runExport handles "koemoji-go export --format vtt <job>": it renders the
+// stored segments of a transcribed file in another format without
+// transcribing it again. It returns the exit code.
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	configPath := flags.String("config", config.GetConfigFilePath(), "Path to config file")
+	format := flags.String("format", "txt", "Output format ("+strings.Join(transcript.Formats, ", ")+")")
+	output := flags.String("output", "", "Output file (default: next to the other outputs of the job)")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: koemoji-go export [--format vtt] [--output file] <job>")
+		fmt.Fprintln(os.Stderr, "\n<job> is the input file path, its file name or the start of its content hash.")
+		fmt.Fprintln(os.Stderr, "\nOptions:")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(*configPath, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+	jobStore, err := jobs.Open(config.GetJobStorePath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load job store: %v\n", err)
+		return 1
+	}
+
+	path, err := processor.Export(cfg, jobStore, flags.Arg(0), *format, *output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return 1
+	}
+	fmt.Println(path)
+	return 0
+}
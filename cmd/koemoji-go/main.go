@@ -14,6 +14,7 @@ import (
 	"github.com/infoHiroki/KoeMoji-Go/internal/config"
 	"github.com/infoHiroki/KoeMoji-Go/internal/diagnostics"
 	"github.com/infoHiroki/KoeMoji-Go/internal/gui"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
 	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
 	"github.com/infoHiroki/KoeMoji-Go/internal/processor"
 	"github.com/infoHiroki/KoeMoji-Go/internal/recorder"
@@ -40,7 +41,7 @@ type App struct {
 	logger         *log.Logger
 	debugMode      bool
 	wg             sync.WaitGroup
-	processedFiles map[string]bool
+	jobStore       *jobs.Store
 	mu             sync.Mutex
 
 	// UI related fields
@@ -52,10 +53,11 @@ type App struct {
 	outputCount  int
 	archiveCount int
 
-	// Queue management for sequential processing
-	queuedFiles    []string // 処理待ちファイルキュー
-	processingFile string   // 現在処理中のファイル名（表示用）
-	isProcessing   bool     // 処理中フラグ
+	// Queue management for the worker pool
+	queuedFiles     []string                // 処理待ちファイルキュー
+	processingFiles []string                // 処理中のファイル（ワーカーごと）
+	isProcessing    bool                    // 処理中フラグ
+	schedule        processor.ScheduleState // 処理時間帯と「今すぐ処理」の状態
 
 	// Recording related fields
 	recorder           recorder.AudioRecorder
@@ -64,6 +66,11 @@ type App struct {
 }
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		os.Exit(runExport(os.Args[2:]))
+	}
+
 	configPath, debugMode, showVersion, showHelp, configMode, tuiMode, doctorMode := parseFlags()
 
 	if showVersion {
@@ -97,7 +104,6 @@ func runTUIMode(configPath string, debugMode bool, configMode bool) {
 	app := &App{
 		configPath:     configPath,
 		debugMode:      debugMode,
-		processedFiles: make(map[string]bool),
 		startTime:      time.Now(),
 		logBuffer:      make([]logger.LogEntry, 0, 12),
 		queuedFiles:    make([]string, 0),
@@ -118,13 +124,25 @@ func runTUIMode(configPath string, debugMode bool, configMode bool) {
 		return
 	}
 
+	// ジョブDBの読み込み（処理済み・中断ファイルの記録）
+	app.jobStore, err = jobs.Open(config.GetJobStorePath())
+	if err != nil {
+		logger.LogError(app.logger, &app.logBuffer, &app.logMutex, "Failed to load job store: %v", err)
+	}
+
 	if err := processor.EnsureDirectories(app.Config, app.logger); err != nil {
 		logger.LogError(app.logger, &app.logBuffer, &app.logMutex, "Failed to create directories: %v", err)
 		fmt.Fprintf(os.Stderr, "Warning: Failed to create directories: %v\n", err)
 		fmt.Fprintf(os.Stderr, "The application will continue with limited functionality.\n")
 	}
 
-	if err := whisper.EnsureDependencies(app.Config, app.logger, &app.logBuffer, &app.logMutex, app.debugMode); err != nil {
+	backend := app.Config.TranscriptionBackend
+	if err := whisper.EnsureDependencies(app.Config, app.logger, &app.logBuffer, &app.logMutex, app.debugMode); err != nil && backend != "" && backend != config.BackendWhisperCTranslate2 {
+		logger.LogError(app.logger, &app.logBuffer, &app.logMutex, "Transcription backend check failed: %v", err)
+		fmt.Fprintf(os.Stderr, "Warning: transcription backend %s is not available: %v\n", backend, err)
+		fmt.Fprintf(os.Stderr, "\nRun with --doctor for details, or change transcription_backend with --configure.\n")
+		fmt.Fprintf(os.Stderr, "\nThe application will continue with limited functionality.\n")
+	} else if err != nil {
 		logger.LogError(app.logger, &app.logBuffer, &app.logMutex, "FasterWhisper dependency check failed: %v", err)
 		fmt.Fprintf(os.Stderr, "Warning: FasterWhisper automatic installation failed: %v\n", err)
 		fmt.Fprintf(os.Stderr, "\nPossible causes:\n")
@@ -157,8 +175,12 @@ func showHelpText() {
 	fmt.Println("KoeMoji-Go - Audio/Video Transcription Tool")
 	fmt.Printf("Version: %s\n\n", version)
 	fmt.Println("Usage: koemoji-go [options]")
+	fmt.Println("       koemoji-go export [--format vtt] [--output file] <job>")
 	fmt.Println("\nOptions:")
 	flag.PrintDefaults()
+	fmt.Println("\nCommands:")
+	fmt.Println("  export       - Write a transcribed file in another format (txt, vtt, srt, tsv, json, md)")
+	fmt.Println("                 from its stored segments; <job> is the file path, name or content hash")
 	fmt.Println("\nModes:")
 	fmt.Println("  Default      - Graphical User Interface (GUI) mode")
 	fmt.Println("  --tui        - Terminal UI (TUI) mode")
@@ -167,6 +189,8 @@ func showHelpText() {
 	fmt.Println("  F2/l - Display all logs")
 	fmt.Println("  F3/s - Scan now")
 	fmt.Println("  F4/r - Start/stop recording")
+	fmt.Println("  x    - Stop a running transcription")
+	fmt.Println("  d    - Remove a file from the queue")
 	fmt.Println("  i    - Open input directory")
 	fmt.Println("  o    - Open output directory")
 	fmt.Println("  q    - Quit")
@@ -264,12 +288,13 @@ func (app *App) startRecording() {
 	app.isRecording = true
 	app.recordingStartTime = time.Now()
 	logger.LogInfo(app.logger, &app.logBuffer, &app.logMutex, "録音を開始しました")
+	processor.PauseForRecording(app.Config, app.logger, &app.logBuffer, &app.logMutex, app.isRecording)
 
 	// Refresh display to show recording status
 	ui.RefreshDisplay(app.Config, app.startTime, app.lastScanTime, &app.logBuffer,
 		&app.logMutex, app.inputCount, app.outputCount, app.archiveCount,
-		&app.queuedFiles, app.processingFile, app.isProcessing, &app.mu,
-		app.isRecording, app.recordingStartTime)
+		&app.queuedFiles, &app.processingFiles, app.jobStore.Waiting(), app.isProcessing, &app.mu,
+		app.isRecording, app.recordingStartTime, app.queueETA(), app.nextWindow)
 }
 
 func (app *App) stopRecording() {
@@ -300,12 +325,13 @@ func (app *App) stopRecording() {
 	app.isRecording = false
 	duration := time.Since(app.recordingStartTime)
 	logger.LogInfo(app.logger, &app.logBuffer, &app.logMutex, "録音を停止しました: %s (時間: %s)", filename, duration.Round(time.Second))
+	processor.PauseForRecording(app.Config, app.logger, &app.logBuffer, &app.logMutex, app.isRecording)
 
 	// Refresh display to remove recording status
 	ui.RefreshDisplay(app.Config, app.startTime, app.lastScanTime, &app.logBuffer,
 		&app.logMutex, app.inputCount, app.outputCount, app.archiveCount,
-		&app.queuedFiles, app.processingFile, app.isProcessing, &app.mu,
-		app.isRecording, app.recordingStartTime)
+		&app.queuedFiles, &app.processingFiles, app.jobStore.Waiting(), app.isProcessing, &app.mu,
+		app.isRecording, app.recordingStartTime, app.queueETA(), app.nextWindow)
 }
 
 func (app *App) runTUI() {
@@ -315,6 +341,11 @@ func (app *App) runTUI() {
 	isScanningFlag := false
 	scanStartTime := time.Time{}
 
+	// Background processing context; cancelling it stops whisper and
+	// requeues the interrupted files for the next start
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	// Create callbacks for TUI
 	callbacks := &ui.TUICallbacks{
 		OnRecordingToggle: func() error {
@@ -326,9 +357,9 @@ func (app *App) runTUI() {
 			// Set scanning flag (Phase 13)
 			isScanningFlag = true
 			scanStartTime = time.Now()
-			go processor.ScanAndProcess(app.Config, app.logger, &app.logBuffer, &app.logMutex,
-				&app.lastScanTime, &app.queuedFiles, &app.processingFile, &app.isProcessing,
-				&app.processedFiles, &app.mu, &app.wg, app.debugMode)
+			go processor.ScanAndProcess(ctx, app.Config, app.logger, &app.logBuffer, &app.logMutex,
+				&app.lastScanTime, &app.queuedFiles, &app.processingFiles, &app.isProcessing,
+				&app.schedule, app.jobStore, &app.mu, &app.wg, app.debugMode)
 			return nil
 		},
 		OnOpenLogFile: func() error {
@@ -341,6 +372,39 @@ func (app *App) runTUI() {
 			app.updateFileCounts()
 			return nil
 		},
+		OnCancelProcessing: func(path string) error {
+			if !processor.CancelFile(app.jobStore, path) {
+				return fmt.Errorf("%sは既に処理中ではありません", filepath.Base(path))
+			}
+			return nil
+		},
+		OnRemoveFromQueue: func(path string) error {
+			if !processor.RemoveFromQueue(app.Config, app.logger, &app.logBuffer, &app.logMutex,
+				&app.queuedFiles, app.jobStore, &app.mu, path) {
+				return fmt.Errorf("%sは既にキューにありません", filepath.Base(path))
+			}
+			return nil
+		},
+		ProfileOf: app.jobStore.Profile,
+		OnProcessNow: func() error {
+			go processor.ProcessNow(ctx, app.Config, app.logger, &app.logBuffer, &app.logMutex,
+				&app.queuedFiles, &app.processingFiles, &app.isProcessing,
+				&app.schedule, app.jobStore, &app.mu, &app.wg, app.debugMode)
+			return nil
+		},
+		NextWindow: app.nextWindow,
+		QueueETA: app.queueETA,
+		Progress: func(path string) (float64, string, bool) {
+			p, ok := whisper.CurrentProgress(path)
+			if !ok {
+				return 0, "", false
+			}
+			percent, known := p.Percent()
+			if !known {
+				percent = -1
+			}
+			return percent, p.LastText, true
+		},
 	}
 
 	// Create TUI with callbacks
@@ -349,13 +413,10 @@ func (app *App) runTUI() {
 	logger.LogInfo(app.logger, &app.logBuffer, &app.logMutex, "KoeMoji-Go TUI 起動中...")
 
 	// Start background processing (Phase 11)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
 	// Start file processing goroutine
 	go processor.StartProcessing(ctx, app.Config, app.logger, &app.logBuffer, &app.logMutex,
-		&app.lastScanTime, &app.queuedFiles, &app.processingFile, &app.isProcessing,
-		&app.processedFiles, &app.mu, &app.wg, app.debugMode)
+		&app.lastScanTime, &app.queuedFiles, &app.processingFiles, &app.isProcessing,
+		&app.schedule, app.jobStore, &app.mu, &app.wg, app.debugMode)
 
 	// Start periodic status updates
 	go func() {
@@ -373,12 +434,19 @@ func (app *App) runTUI() {
 				app.updateFileCounts()
 
 				// Update status
+				app.mu.Lock()
+				processingFiles := append([]string(nil), app.processingFiles...)
+				queuedFiles := append([]string(nil), app.queuedFiles...)
+				isProcessing := app.isProcessing
+				app.mu.Unlock()
 				tui.UpdateStatus(
 					app.inputCount,
 					app.outputCount,
 					app.archiveCount,
-					app.processingFile,
-					app.isProcessing,
+					processingFiles,
+					queuedFiles,
+					app.jobStore.Waiting(),
+					isProcessing,
 					app.isRecording,
 					app.recordingStartTime,
 				)
@@ -452,3 +520,18 @@ func (app *App) updateFileCounts() {
 	}
 }
 
+// nextWindow returns when processing resumes while processing_schedule holds
+// the queue back (false while files may be processed, e.g. after "process now")
+func (app *App) nextWindow() (time.Time, bool) {
+	return processor.NextWindow(app.Config, &app.schedule, time.Now())
+}
+
+// queueETA estimates the remaining transcription time of the running and queued files
+func (app *App) queueETA() jobs.ETA {
+	app.mu.Lock()
+	processing := append([]string(nil), app.processingFiles...)
+	queued := append([]string(nil), app.queuedFiles...)
+	app.mu.Unlock()
+
+	return processor.EstimateETA(app.Config, app.jobStore, processing, queued)
+}
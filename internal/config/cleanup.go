@@ -0,0 +1,34 @@
+package config
+
+// Which transcript the summary is made from (summary_source)
+const (
+	SummarySourceClean    = "clean"    // The cleaned transcript, when clean_transcript is on
+	SummarySourceVerbatim = "verbatim" // The transcript as whisper wrote it
+)
+
+// SummarySources lists the values accepted for summary_source
+var SummarySources = []string{SummarySourceClean, SummarySourceVerbatim}
+
+// DefaultFillerWords are the fillers removed from the clean transcript.
+// "あの" and "まあ" are only removed on their own ("あの、"), not in "あの人".
+var DefaultFillerWords = []string{
+	"えー",
+	"えーと",
+	"えーっと",
+	"えっと",
+	"ええと",
+	"あー",
+	"あのー",
+	"あの",
+	"そのー",
+	"まあ",
+	"まぁ",
+	"うーん",
+	"んー",
+}
+
+// SummaryFromClean reports whether the summary is made from the clean
+// transcript instead of the verbatim one
+func (c *Config) SummaryFromClean() bool {
+	return c.CleanTranscript && c.SummarySource != SummarySourceVerbatim
+}
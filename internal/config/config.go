@@ -14,18 +14,117 @@ import (
 	"github.com/gordonklaus/portaudio"
 )
 
+// Watch modes for the input directory
+const (
+	WatchModeWatch = "watch" // React to file system events, periodic scan as a safety net
+	WatchModePoll  = "poll"  // Periodic scan only (for network shares where events are unreliable)
+)
+
+// Actions for input files whose content was already transcribed under another name
+const (
+	DuplicateActionReuse   = "reuse"   // Copy the earlier outputs under the new file name
+	DuplicateActionSkip    = "skip"    // Move the file to archive without creating outputs
+	DuplicateActionProcess = "process" // Transcribe it again
+)
+
+// What the hallucination filter does with segments whisper most likely made
+// up on silence or noise (hallucination_filter)
+const (
+	HallucinationFilterDrop = "drop" // Remove them from the outputs and the summary
+	HallucinationFilterFlag = "flag" // Keep them marked with "[?]" for review; the summary leaves them out
+	HallucinationFilterOff  = "off"  // Keep everything whisper returned
+)
+
+// DefaultHallucinationPhrases are phrases whisper is known to produce on
+// silence, learned from video subtitles
+var DefaultHallucinationPhrases = []string{
+	"ご視聴ありがとうございました",
+	"最後までご視聴いただきありがとうございました",
+	"チャンネル登録よろしくお願いします",
+	"チャンネル登録お願いします",
+	"字幕は視聴者によって作成されました",
+	"Thank you for watching",
+	"Thanks for watching",
+	"Please subscribe to my channel",
+	"Subtitles by the Amara.org community",
+}
+
+// Transcription backends (transcription_backend)
+const (
+	BackendWhisperCTranslate2 = "whisper-ctranslate2" // The whisper-ctranslate2 command line tool (faster-whisper)
+	BackendWhisperCpp         = "whisper.cpp"         // A whisper.cpp binary (whisper-cli/main) with GGML models, no Python needed
+	BackendOpenAI             = "openai"              // An OpenAI-compatible /v1/audio/transcriptions endpoint (audio is uploaded)
+	BackendMock               = "mock"                // Deterministic in-process backend for tests and demos
+)
+
+// TranscriptionBackends lists the values accepted for transcription_backend
+var TranscriptionBackends = []string{BackendWhisperCTranslate2, BackendWhisperCpp, BackendOpenAI, BackendMock}
+
 type Config struct {
 	WhisperModel        string `json:"whisper_model"`
 	Language            string `json:"language"`
 	UILanguage          string `json:"ui_language"`
 	ScanIntervalMinutes int    `json:"scan_interval_minutes"`
 	MaxCpuPercent       int    `json:"max_cpu_percent"`
+	MaxConcurrentJobs   int    `json:"max_concurrent_jobs"`   // Number of files transcribed in parallel
+	WatchMode           string `json:"watch_mode"`            // "watch" (file system events) or "poll" (periodic scan only)
+	FileSettleSeconds   int    `json:"file_settle_seconds"`   // New files must keep size/mtime this long before queuing
+	RecursiveScan       bool   `json:"recursive_scan"`        // Also process subfolders of input_dir; outputs mirror the subfolder path
+	DuplicateAction     string `json:"duplicate_action"`      // "reuse", "skip" or "process" for files whose content was already transcribed
+	PauseWhileRecording bool   `json:"pause_while_recording"` // Suspend transcription while a recording is running
 	ComputeType         string `json:"compute_type"`
 	UseColors           bool   `json:"use_colors"`
 	OutputFormat        string `json:"output_format"`
 	InputDir            string `json:"input_dir"`
 	OutputDir           string `json:"output_dir"`
 	ArchiveDir          string `json:"archive_dir"`
+	FailedDir           string `json:"failed_dir"` // Files that failed after all retries are moved here
+	// Output formats written for every file, e.g. ["txt", "srt"] or ["all"]
+	// (see formats.go). Empty = output_format, the single format of older versions.
+	OutputFormats []string `json:"output_formats,omitempty"`
+	// Transcription backend: "whisper-ctranslate2" (default), "whisper.cpp", "openai" or "mock" (see TranscriptionBackends)
+	TranscriptionBackend string `json:"transcription_backend"`
+	WhisperCppPath       string `json:"whisper_cpp_path"`      // whisper.cpp binary (empty = whisper-cli on the PATH or next to KoeMoji-Go)
+	WhisperCppModelDir   string `json:"whisper_cpp_model_dir"` // Folder with the ggml-<model>.bin files
+	// OpenAI-compatible transcription API ("openai" backend): OpenAI or a
+	// faster-whisper-server / LocalAI on the LAN
+	RemoteBaseURL     string `json:"remote_base_url"`      // e.g. https://api.openai.com/v1 or http://192.168.1.10:8000/v1
+	RemoteModel       string `json:"remote_model"`         // e.g. whisper-1, or the model name of the server
+	RemoteAPIKey      string `json:"remote_api_key"`       // Sent as a Bearer token (empty = no Authorization header)
+	RemoteMaxUploadMB int    `json:"remote_max_upload_mb"` // Larger files are split before uploading
+	// Long recordings: WAV/FLAC inputs longer than this are transcribed in
+	// chunks that survive a crash or restart (0 = off)
+	ChunkMinutes int `json:"chunk_minutes"`
+	// Word-level timing: whisper returns the start, end and probability of
+	// every word; VTT highlights the words and JSON keeps them
+	WordTimestamps bool `json:"word_timestamps"`
+	// Words recognized with a lower probability are marked in txt and md for
+	// review, e.g. 0.5 (0 = off; the word timing is requested for it)
+	LowConfidenceThreshold float64 `json:"low_confidence_threshold"`
+	// Hallucination filter: "drop", "flag" or "off" (see HallucinationFilterDrop),
+	// and the phrases that are always treated as made up
+	HallucinationFilter  string   `json:"hallucination_filter"`
+	HallucinationPhrases []string `json:"hallucination_phrases"`
+	// Custom vocabulary: product names, people's names and terms whisper
+	// should spell this way, given as the initial prompt or as hotwords (see
+	// vocabulary.go). Profiles add their own words.
+	Vocabulary     []string `json:"vocabulary,omitempty"`
+	VocabularyMode string   `json:"vocabulary_mode"`
+	// Replacement rules applied to every transcript before the summary, e.g.
+	// "コエモジ" -> "KoeMoji" (JSON array of rules; empty = none)
+	ReplacementRulesFile string `json:"replacement_rules_file"`
+	// Clean transcript: a copy without fillers, with "、。" and normalized
+	// width, written next to the verbatim one as <name>_clean.<format>
+	// (see cleanup.go). The summary uses it unless summary_source is "verbatim".
+	CleanTranscript bool     `json:"clean_transcript"`
+	FillerWords     []string `json:"filler_words"`
+	SummarySource   string   `json:"summary_source"`
+	// Retry settings
+	MaxRetries          int `json:"max_retries"`           // Retries after the first failed attempt (0 = no retry)
+	RetryBackoffSeconds int `json:"retry_backoff_seconds"` // Wait before the first retry, doubled on each further retry
+	// Processing schedule: queued files are transcribed only inside these windows,
+	// e.g. "mon-fri 18:00-08:00" (see schedule.go). Empty = any time.
+	ProcessingSchedule []string `json:"processing_schedule,omitempty"`
 	// LLM Summary settings
 	LLMSummaryEnabled     bool   `json:"llm_summary_enabled"`
 	LLMAPIProvider        string `json:"llm_api_provider"`
@@ -34,6 +133,8 @@ type Config struct {
 	LLMMaxTokens          int    `json:"llm_max_tokens"`
 	SummaryPromptTemplate string `json:"summary_prompt_template"`
 	SummaryLanguage       string `json:"summary_language"`
+	// Processing profiles keyed by glob relative to input_dir (see profile.go)
+	Profiles map[string]Profile `json:"profiles,omitempty"`
 	// Recording settings
 	RecordingDeviceName string `json:"recording_device_name"` // Device name for recording (empty = default device)
 	// Phase 1: Memory-efficient recording limits
@@ -53,12 +154,46 @@ func GetDefaultConfig() *Config {
 		UILanguage:          "ja",
 		ScanIntervalMinutes: 1,
 		MaxCpuPercent:       95,
+		MaxConcurrentJobs:   1,
+		WatchMode:           WatchModeWatch,
+		FileSettleSeconds:   5,
+		RecursiveScan:       false,
+		DuplicateAction:     DuplicateActionReuse,
+		PauseWhileRecording: true,
 		ComputeType:         "int8",
 		UseColors:           true,
 		OutputFormat:        "txt",
 		InputDir:            "./input",
 		OutputDir:           "./output",
 		ArchiveDir:          "./archive",
+		FailedDir:           "./failed",
+		MaxRetries:          2,
+		RetryBackoffSeconds: 30,
+		// Transcription backend
+		TranscriptionBackend: BackendWhisperCTranslate2,
+		WhisperCppPath:       "",
+		WhisperCppModelDir:   "./models",
+		// Remote transcription defaults
+		RemoteBaseURL:     "https://api.openai.com/v1",
+		RemoteModel:       "whisper-1",
+		RemoteAPIKey:      "",
+		RemoteMaxUploadMB: 25, // OpenAI's upload limit
+		ChunkMinutes:      0,
+		// Word timing and confidence marks
+		WordTimestamps:         false,
+		LowConfidenceThreshold: 0,
+		// Hallucination filter
+		HallucinationFilter:  HallucinationFilterFlag,
+		HallucinationPhrases: append([]string(nil), DefaultHallucinationPhrases...),
+		// Custom vocabulary
+		Vocabulary:     nil,
+		VocabularyMode: VocabularyModePrompt,
+		// Replacement rules
+		ReplacementRulesFile: "./replacements.json",
+		// Clean transcript
+		CleanTranscript: false,
+		FillerWords:     append([]string(nil), DefaultFillerWords...),
+		SummarySource:   SummarySourceClean,
 		// LLM Summary defaults
 		LLMSummaryEnabled:     false,
 		LLMAPIProvider:        "openai",
@@ -165,7 +300,7 @@ func ConfigureSettings(config *Config, configPath string, logger *log.Logger) {
 		fmt.Printf("5. %s: %d%%\n", msg.MaxCPUPercent, config.MaxCpuPercent)
 		fmt.Printf("6. %s: %s\n", msg.ComputeType, config.ComputeType)
 		fmt.Printf("7. %s: %t\n", msg.UseColors, config.UseColors)
-		fmt.Printf("8. %s: %s\n", msg.OutputFormat, config.OutputFormat)
+		fmt.Printf("8. %s: %s\n", msg.OutputFormat, config.FormatsDisplay())
 		fmt.Printf("9. %s: %s\n", msg.InputDirectory, config.InputDir)
 		fmt.Printf("10. %s: %s\n", msg.OutputDirectory, config.OutputDir)
 		fmt.Printf("11. %s: %s\n", msg.ArchiveDirectory, config.ArchiveDir)
@@ -176,10 +311,28 @@ func ConfigureSettings(config *Config, configPath string, logger *log.Logger) {
 		fmt.Printf("16. %s: %d\n", msg.LLMMaxTokens, config.LLMMaxTokens)
 		fmt.Printf("17. %s: [%s]\n", msg.SummaryPrompt, msg.EditablePrompt)
 		fmt.Printf("18. %s: %s\n", msg.RecordingDeviceName, config.RecordingDeviceName)
+		fmt.Printf("19. %s: %d\n", msg.MaxConcurrentJobs, config.MaxConcurrentJobs)
+		fmt.Printf("20. %s: %s\n", msg.WatchMode, config.WatchMode)
+		fmt.Printf("21. %s: %d %s\n", msg.FileSettle, config.FileSettleSeconds, msg.Seconds)
+		fmt.Printf("22. %s: %s\n", msg.FailedDirectory, config.FailedDir)
+		fmt.Printf("23. %s: %d\n", msg.MaxRetries, config.MaxRetries)
+		fmt.Printf("24. %s: %t\n", msg.RecursiveScan, config.RecursiveScan)
+		fmt.Printf("25. %s: %s\n", msg.DuplicateAction, config.DuplicateAction)
+		fmt.Printf("26. %s: %t\n", msg.PauseRecording, config.PauseWhileRecording)
+		fmt.Printf("27. %s: %s\n", msg.Schedule, scheduleDisplay(config))
+		fmt.Printf("28. %s: %s\n", msg.Backend, config.TranscriptionBackend)
+		fmt.Printf("29. %s: %s\n", msg.ChunkMinutes, chunkMinutesDisplay(config))
+		fmt.Printf("30. %s: %t\n", msg.WordTimestamps, config.WordTimestamps)
+		fmt.Printf("31. %s: %s\n", msg.LowConfidence, lowConfidenceDisplay(config))
+		fmt.Printf("32. %s: %s\n", msg.Hallucinations, config.HallucinationFilter)
+		fmt.Printf("33. %s: %s\n", msg.Vocabulary, vocabularyDisplay(config))
+		fmt.Printf("34. %s: %s\n", msg.VocabularyMode, config.VocabularyMode)
+		fmt.Printf("35. %s: %t\n", msg.CleanTranscript, config.CleanTranscript)
+		fmt.Printf("36. %s: %s\n", msg.SummarySource, config.SummarySource)
 		fmt.Printf("r. %s\n", msg.ResetDefaults)
 		fmt.Printf("s. %s\n", msg.SaveAndExit)
 		fmt.Printf("q. %s\n", msg.QuitWithoutSave)
-		fmt.Printf("\n%s (1-18, r, s, q): ", msg.SelectOption)
+		fmt.Printf("\n%s (1-36, r, s, q): ", msg.SelectOption)
 
 		input, _ := reader.ReadString('\n')
 		choice := strings.TrimSpace(input)
@@ -257,6 +410,78 @@ func ConfigureSettings(config *Config, configPath string, logger *log.Logger) {
 			if configureRecordingDevice(config, reader) {
 				modified = true
 			}
+		case "19":
+			if configureMaxConcurrentJobs(config, reader) {
+				modified = true
+			}
+		case "20":
+			if configureWatchMode(config, reader) {
+				modified = true
+			}
+		case "21":
+			if configureFileSettle(config, reader) {
+				modified = true
+			}
+		case "22":
+			if configureFailedDir(config, reader) {
+				modified = true
+			}
+		case "23":
+			if configureMaxRetries(config, reader) {
+				modified = true
+			}
+		case "24":
+			if configureRecursiveScan(config, reader) {
+				modified = true
+			}
+		case "25":
+			if configureDuplicateAction(config, reader) {
+				modified = true
+			}
+		case "26":
+			if configurePauseWhileRecording(config, reader) {
+				modified = true
+			}
+		case "27":
+			if configureSchedule(config, reader) {
+				modified = true
+			}
+		case "28":
+			if configureBackend(config, reader) {
+				modified = true
+			}
+		case "29":
+			if configureChunkMinutes(config, reader) {
+				modified = true
+			}
+		case "30":
+			if configureWordTimestamps(config, reader) {
+				modified = true
+			}
+		case "31":
+			if configureLowConfidence(config, reader) {
+				modified = true
+			}
+		case "32":
+			if configureHallucinationFilter(config, reader) {
+				modified = true
+			}
+		case "33":
+			if configureVocabulary(config, reader) {
+				modified = true
+			}
+		case "34":
+			if configureVocabularyMode(config, reader) {
+				modified = true
+			}
+		case "35":
+			if configureCleanTranscript(config, reader) {
+				modified = true
+			}
+		case "36":
+			if configureSummarySource(config, reader) {
+				modified = true
+			}
 		case "r":
 			if resetToDefaults(config, reader) {
 				modified = true
@@ -387,6 +612,82 @@ func configureMaxCpuPercent(config *Config, reader *bufio.Reader) bool {
 	return false
 }
 
+func configureMaxConcurrentJobs(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	fmt.Printf("%s %s: %d\n", msg.Current, msg.MaxConcurrentJobs, config.MaxConcurrentJobs)
+	fmt.Printf("%s ", msg.EnterConcurrentJobs)
+
+	input, _ := reader.ReadString('\n')
+	newJobs := strings.TrimSpace(input)
+
+	if newJobs == "" {
+		return false
+	}
+
+	if jobs, err := strconv.Atoi(newJobs); err == nil && jobs >= 1 && jobs <= runtime.NumCPU() {
+		config.MaxConcurrentJobs = jobs
+		fmt.Printf(msg.ConcurrentJobsSet+"\n", config.MaxConcurrentJobs)
+		return true
+	}
+
+	fmt.Println(msg.InvalidInput)
+	return false
+}
+
+func configureWatchMode(config *Config, reader *bufio.Reader) bool {
+	modes := []string{WatchModeWatch, WatchModePoll}
+	msg := getMessages(config)
+	modeDescriptions := []string{msg.WatchModeWatchDesc, msg.WatchModePollDesc}
+
+	fmt.Println("\nAvailable watch modes:")
+	for i, mode := range modes {
+		fmt.Printf("%d. %s - %s", i+1, mode, modeDescriptions[i])
+		if mode == config.WatchMode {
+			fmt.Printf(" (%s)", msg.Current)
+		}
+		fmt.Println()
+	}
+	fmt.Printf(msg.SelectWatchMode+" ", len(modes))
+
+	input, _ := reader.ReadString('\n')
+	choice := strings.TrimSpace(input)
+
+	if choice == "" {
+		return false
+	}
+
+	if idx, err := strconv.Atoi(choice); err == nil && idx >= 1 && idx <= len(modes) {
+		config.WatchMode = modes[idx-1]
+		fmt.Printf(msg.WatchModeSet+"\n", config.WatchMode)
+		return true
+	}
+
+	fmt.Println(msg.InvalidOption)
+	return false
+}
+
+func configureFileSettle(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	fmt.Printf("%s %s: %d %s\n", msg.Current, msg.FileSettle, config.FileSettleSeconds, msg.Seconds)
+	fmt.Printf("%s ", msg.EnterFileSettle)
+
+	input, _ := reader.ReadString('\n')
+	newSeconds := strings.TrimSpace(input)
+
+	if newSeconds == "" {
+		return false
+	}
+
+	if seconds, err := strconv.Atoi(newSeconds); err == nil && seconds >= 0 && seconds <= 600 {
+		config.FileSettleSeconds = seconds
+		fmt.Printf(msg.FileSettleSet+"\n", config.FileSettleSeconds)
+		return true
+	}
+
+	fmt.Println(msg.InvalidInput)
+	return false
+}
+
 func configureComputeType(config *Config, reader *bufio.Reader) bool {
 	types := []string{"int8", "int8_float16", "int16", "float16", "float32"}
 	msg := getMessages(config)
@@ -477,14 +778,18 @@ func configureUseColors(config *Config, reader *bufio.Reader) bool {
 }
 
 func configureOutputFormat(config *Config, reader *bufio.Reader) bool {
-	formats := []string{"txt", "vtt", "srt", "tsv", "json"}
+	// 複数選択: "1,3" のように番号をカンマ区切りで入力
+	formats := append(append([]string{}, OutputFormatChoices...), OutputFormatAll)
 	msg := getMessages(config)
+	current := config.Formats()
 
 	fmt.Println("\nAvailable output formats:")
 	for i, format := range formats {
 		fmt.Printf("%d. %s", i+1, format)
-		if format == config.OutputFormat {
-			fmt.Printf(" (%s)", msg.Current)
+		for _, f := range current {
+			if format == f {
+				fmt.Printf(" (%s)", msg.Current)
+			}
 		}
 		fmt.Println()
 	}
@@ -497,14 +802,19 @@ func configureOutputFormat(config *Config, reader *bufio.Reader) bool {
 		return false
 	}
 
-	if idx, err := strconv.Atoi(choice); err == nil && idx >= 1 && idx <= len(formats) {
-		config.OutputFormat = formats[idx-1]
-		fmt.Printf(msg.FormatSet+"\n", config.OutputFormat)
-		return true
+	var selected []string
+	for _, field := range strings.Split(choice, ",") {
+		idx, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || idx < 1 || idx > len(formats) {
+			fmt.Println(msg.InvalidOption)
+			return false
+		}
+		selected = append(selected, formats[idx-1])
 	}
 
-	fmt.Println(msg.InvalidOption)
-	return false
+	config.SetFormats(selected)
+	fmt.Printf(msg.FormatSet+"\n", config.FormatsDisplay())
+	return true
 }
 
 func configureInputDir(config *Config, reader *bufio.Reader) bool {
@@ -576,6 +886,464 @@ func configureArchiveDir(config *Config, reader *bufio.Reader) bool {
 	return true
 }
 
+func configureFailedDir(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	fmt.Printf("%s %s: %s\n", msg.Current, msg.FailedDirectory, config.FailedDir)
+	fmt.Printf("%s ", msg.SelectFolder)
+
+	input, _ := reader.ReadString('\n')
+	newDir := strings.TrimSpace(input)
+
+	if newDir == "" {
+		// Use folder selection dialog
+		selectedDir, err := selectFolder("Select Failed Directory")
+		if err != nil {
+			fmt.Printf(msg.FolderSelectFail+"\n", err)
+			return false
+		}
+		newDir = selectedDir
+	}
+
+	config.FailedDir = newDir
+	fmt.Printf(msg.FailedDirSet+"\n", config.FailedDir)
+	return true
+}
+
+// chunkMinutesDisplay returns chunk_minutes for the settings menu
+func chunkMinutesDisplay(c *Config) string {
+	msg := getMessages(c)
+	if c.ChunkMinutes <= 0 {
+		return msg.ChunkOff
+	}
+	return fmt.Sprintf("%d %s", c.ChunkMinutes, msg.Minutes)
+}
+
+func configureChunkMinutes(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	fmt.Printf("%s %s: %s\n", msg.Current, msg.ChunkMinutes, chunkMinutesDisplay(config))
+	fmt.Printf("%s ", msg.EnterChunkMinutes)
+
+	input, _ := reader.ReadString('\n')
+	newMinutes := strings.TrimSpace(input)
+
+	if newMinutes == "" {
+		return false
+	}
+
+	if minutes, err := strconv.Atoi(newMinutes); err == nil && minutes >= 0 && minutes <= 240 {
+		config.ChunkMinutes = minutes
+		fmt.Printf(msg.ChunkMinutesSet+"\n", chunkMinutesDisplay(config))
+		return true
+	}
+
+	fmt.Println(msg.InvalidInput)
+	return false
+}
+
+func configureMaxRetries(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	fmt.Printf("%s %s: %d\n", msg.Current, msg.MaxRetries, config.MaxRetries)
+	fmt.Printf("%s ", msg.EnterMaxRetries)
+
+	input, _ := reader.ReadString('\n')
+	newRetries := strings.TrimSpace(input)
+
+	if newRetries == "" {
+		return false
+	}
+
+	if retries, err := strconv.Atoi(newRetries); err == nil && retries >= 0 && retries <= 10 {
+		config.MaxRetries = retries
+		fmt.Printf(msg.MaxRetriesSet+"\n", config.MaxRetries)
+		return true
+	}
+
+	fmt.Println(msg.InvalidInput)
+	return false
+}
+
+func configureWordTimestamps(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	fmt.Printf("%s %s: %t\n", msg.Current, msg.WordTimestamps, config.WordTimestamps)
+	fmt.Printf("%s ", msg.EnableWordTiming)
+
+	input, _ := reader.ReadString('\n')
+	choice := strings.ToLower(strings.TrimSpace(input))
+
+	if choice == "" {
+		return false
+	}
+
+	if choice == "y" || choice == "yes" {
+		config.WordTimestamps = true
+	} else if choice == "n" || choice == "no" {
+		config.WordTimestamps = false
+	} else {
+		fmt.Println(msg.InvalidInput)
+		return false
+	}
+
+	fmt.Printf(msg.WordTimestampsSet+"\n", config.WordTimestamps)
+	return true
+}
+
+// lowConfidenceDisplay returns low_confidence_threshold for the settings menu
+func lowConfidenceDisplay(c *Config) string {
+	msg := getMessages(c)
+	if c.LowConfidenceThreshold <= 0 {
+		return msg.ChunkOff
+	}
+	return strconv.FormatFloat(c.LowConfidenceThreshold, 'f', -1, 64)
+}
+
+func configureLowConfidence(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	fmt.Printf("%s %s: %s\n", msg.Current, msg.LowConfidence, lowConfidenceDisplay(config))
+	fmt.Printf("%s ", msg.EnterLowConfidence)
+
+	input, _ := reader.ReadString('\n')
+	newThreshold := strings.TrimSpace(input)
+
+	if newThreshold == "" {
+		return false
+	}
+
+	if threshold, err := strconv.ParseFloat(newThreshold, 64); err == nil && threshold >= 0 && threshold < 1 {
+		config.LowConfidenceThreshold = threshold
+		fmt.Printf(msg.LowConfidenceSet+"\n", lowConfidenceDisplay(config))
+		return true
+	}
+
+	fmt.Println(msg.InvalidInput)
+	return false
+}
+
+func configureRecursiveScan(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	fmt.Printf("%s %s: %t\n", msg.Current, msg.RecursiveScan, config.RecursiveScan)
+	fmt.Printf("%s ", msg.EnableRecursiveScan)
+
+	input, _ := reader.ReadString('\n')
+	choice := strings.ToLower(strings.TrimSpace(input))
+
+	if choice == "" {
+		return false
+	}
+
+	if choice == "y" || choice == "yes" {
+		config.RecursiveScan = true
+	} else if choice == "n" || choice == "no" {
+		config.RecursiveScan = false
+	} else {
+		fmt.Println(msg.InvalidInput)
+		return false
+	}
+
+	fmt.Printf(msg.RecursiveScanSet+"\n", config.RecursiveScan)
+	return true
+}
+
+func configurePauseWhileRecording(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	fmt.Printf("%s %s: %t\n", msg.Current, msg.PauseRecording, config.PauseWhileRecording)
+	fmt.Printf("%s ", msg.EnablePause)
+
+	input, _ := reader.ReadString('\n')
+	choice := strings.ToLower(strings.TrimSpace(input))
+
+	if choice == "" {
+		return false
+	}
+
+	if choice == "y" || choice == "yes" {
+		config.PauseWhileRecording = true
+	} else if choice == "n" || choice == "no" {
+		config.PauseWhileRecording = false
+	} else {
+		fmt.Println(msg.InvalidInput)
+		return false
+	}
+
+	fmt.Printf(msg.PauseRecordingSet+"\n", config.PauseWhileRecording)
+	return true
+}
+
+// scheduleDisplay returns processing_schedule as entered in the menu
+func scheduleDisplay(c *Config) string {
+	if len(c.ProcessingSchedule) == 0 {
+		return getMessages(c).ScheduleAnyTime
+	}
+	return strings.Join(c.ProcessingSchedule, "; ")
+}
+
+func configureSchedule(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	fmt.Printf("%s %s: %s\n", msg.Current, msg.Schedule, scheduleDisplay(config))
+	fmt.Printf("%s ", msg.EnterSchedule)
+
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+
+	if input == "" {
+		return false
+	}
+
+	var schedule []string
+	if input != "-" {
+		var err error
+		if schedule, err = ParseScheduleList(input); err != nil {
+			fmt.Printf("%s: %v\n", msg.InvalidInput, err)
+			return false
+		}
+	}
+
+	config.ProcessingSchedule = schedule
+	fmt.Printf(msg.ScheduleSet+"\n", scheduleDisplay(config))
+	return true
+}
+
+func configureDuplicateAction(config *Config, reader *bufio.Reader) bool {
+	actions := []string{DuplicateActionReuse, DuplicateActionSkip, DuplicateActionProcess}
+	msg := getMessages(config)
+	actionDescriptions := []string{msg.DuplicateReuse, msg.DuplicateSkip, msg.DuplicateProcess}
+
+	fmt.Println()
+	for i, action := range actions {
+		fmt.Printf("%d. %s - %s", i+1, action, actionDescriptions[i])
+		if action == config.DuplicateAction {
+			fmt.Printf(" (%s)", msg.Current)
+		}
+		fmt.Println()
+	}
+	fmt.Printf(msg.SelectDuplicate+" ", len(actions))
+
+	input, _ := reader.ReadString('\n')
+	choice := strings.TrimSpace(input)
+
+	if choice == "" {
+		return false
+	}
+
+	if idx, err := strconv.Atoi(choice); err == nil && idx >= 1 && idx <= len(actions) {
+		config.DuplicateAction = actions[idx-1]
+		fmt.Printf(msg.DuplicateSet+"\n", config.DuplicateAction)
+		return true
+	}
+
+	fmt.Println(msg.InvalidOption)
+	return false
+}
+
+func configureHallucinationFilter(config *Config, reader *bufio.Reader) bool {
+	modes := []string{HallucinationFilterDrop, HallucinationFilterFlag, HallucinationFilterOff}
+	msg := getMessages(config)
+	modeDescriptions := []string{msg.HallucinationDrop, msg.HallucinationFlag, msg.HallucinationOff}
+
+	fmt.Println()
+	for i, mode := range modes {
+		fmt.Printf("%d. %s - %s", i+1, mode, modeDescriptions[i])
+		if mode == config.HallucinationFilter {
+			fmt.Printf(" (%s)", msg.Current)
+		}
+		fmt.Println()
+	}
+	fmt.Printf(msg.SelectHallucination+" ", len(modes))
+
+	input, _ := reader.ReadString('\n')
+	choice := strings.TrimSpace(input)
+
+	if choice == "" {
+		return false
+	}
+
+	if idx, err := strconv.Atoi(choice); err == nil && idx >= 1 && idx <= len(modes) {
+		config.HallucinationFilter = modes[idx-1]
+		fmt.Printf(msg.HallucinationSet+"\n", config.HallucinationFilter)
+		return true
+	}
+
+	fmt.Println(msg.InvalidOption)
+	return false
+}
+
+// vocabularyDisplay returns the vocabulary for the settings menu
+func vocabularyDisplay(c *Config) string {
+	if len(c.Vocabulary) == 0 {
+		return getMessages(c).VocabularyNone
+	}
+	return c.VocabularyDisplay()
+}
+
+func configureVocabulary(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	fmt.Printf("%s %s: %s\n", msg.Current, msg.Vocabulary, vocabularyDisplay(config))
+	fmt.Printf("%s ", msg.EnterVocabulary)
+
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+
+	if input == "" {
+		return false
+	}
+
+	var vocabulary []string
+	if input != "-" {
+		vocabulary = ParseVocabulary(input)
+	}
+
+	config.Vocabulary = vocabulary
+	fmt.Printf(msg.VocabularySet+"\n", vocabularyDisplay(config))
+	return true
+}
+
+func configureVocabularyMode(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	descriptions := []string{msg.VocabPromptDesc, msg.VocabHotwordsDesc}
+
+	fmt.Println()
+	for i, mode := range VocabularyModes {
+		fmt.Printf("%d. %s - %s", i+1, mode, descriptions[i])
+		if mode == config.VocabularyMode {
+			fmt.Printf(" (%s)", msg.Current)
+		}
+		fmt.Println()
+	}
+	fmt.Printf(msg.SelectVocabMode+" ", len(VocabularyModes))
+
+	input, _ := reader.ReadString('\n')
+	choice := strings.TrimSpace(input)
+
+	if choice == "" {
+		return false
+	}
+
+	if idx, err := strconv.Atoi(choice); err == nil && idx >= 1 && idx <= len(VocabularyModes) {
+		config.VocabularyMode = VocabularyModes[idx-1]
+		fmt.Printf(msg.VocabModeSet+"\n", config.VocabularyMode)
+		return true
+	}
+
+	fmt.Println(msg.InvalidOption)
+	return false
+}
+
+func configureCleanTranscript(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	fmt.Printf("%s %s: %t\n", msg.Current, msg.CleanTranscript, config.CleanTranscript)
+	fmt.Printf("%s ", msg.EnableCleanup)
+
+	input, _ := reader.ReadString('\n')
+	choice := strings.ToLower(strings.TrimSpace(input))
+
+	if choice == "" {
+		return false
+	}
+
+	if choice == "y" || choice == "yes" {
+		config.CleanTranscript = true
+	} else if choice == "n" || choice == "no" {
+		config.CleanTranscript = false
+	} else {
+		fmt.Println(msg.InvalidInput)
+		return false
+	}
+
+	fmt.Printf(msg.CleanupSet+"\n", config.CleanTranscript)
+	return true
+}
+
+func configureSummarySource(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	descriptions := []string{msg.SummaryCleanDesc, msg.SummaryVerbatimDesc}
+
+	fmt.Println()
+	for i, source := range SummarySources {
+		fmt.Printf("%d. %s - %s", i+1, source, descriptions[i])
+		if source == config.SummarySource {
+			fmt.Printf(" (%s)", msg.Current)
+		}
+		fmt.Println()
+	}
+	fmt.Printf(msg.SelectSummarySrc+" ", len(SummarySources))
+
+	input, _ := reader.ReadString('\n')
+	choice := strings.TrimSpace(input)
+
+	if choice == "" {
+		return false
+	}
+
+	if idx, err := strconv.Atoi(choice); err == nil && idx >= 1 && idx <= len(SummarySources) {
+		config.SummarySource = SummarySources[idx-1]
+		fmt.Printf(msg.SummarySourceSet+"\n", config.SummarySource)
+		return true
+	}
+
+	fmt.Println(msg.InvalidOption)
+	return false
+}
+
+func configureBackend(config *Config, reader *bufio.Reader) bool {
+	msg := getMessages(config)
+	descriptions := []string{msg.BackendCLIDesc, msg.BackendCppDesc, msg.BackendRemoteDesc, msg.BackendMockDesc}
+
+	fmt.Println()
+	for i, backend := range TranscriptionBackends {
+		fmt.Printf("%d. %s - %s", i+1, backend, descriptions[i])
+		if backend == config.TranscriptionBackend {
+			fmt.Printf(" (%s)", msg.Current)
+		}
+		fmt.Println()
+	}
+	fmt.Printf(msg.SelectBackend+" ", len(TranscriptionBackends))
+
+	input, _ := reader.ReadString('\n')
+	choice := strings.TrimSpace(input)
+
+	if choice == "" {
+		return false
+	}
+
+	if idx, err := strconv.Atoi(choice); err == nil && idx >= 1 && idx <= len(TranscriptionBackends) {
+		config.TranscriptionBackend = TranscriptionBackends[idx-1]
+		fmt.Printf(msg.BackendSet+"\n", config.TranscriptionBackend)
+
+		// whisper.cpp needs the folder of its GGML models
+		if config.TranscriptionBackend == BackendWhisperCpp {
+			fmt.Printf(msg.EnterCppModelDir+" ", config.WhisperCppModelDir)
+			input, _ := reader.ReadString('\n')
+			if dir := strings.TrimSpace(input); dir != "" {
+				config.WhisperCppModelDir = dir
+				fmt.Printf(msg.CppModelDirSet+"\n", dir)
+			}
+		}
+
+		// The remote backend needs the endpoint, model and API key
+		if config.TranscriptionBackend == BackendOpenAI {
+			fmt.Printf(msg.EnterRemoteURL+" ", config.RemoteBaseURL)
+			input, _ := reader.ReadString('\n')
+			if url := strings.TrimRight(strings.TrimSpace(input), "/"); url != "" {
+				config.RemoteBaseURL = url
+			}
+			fmt.Printf(msg.EnterRemoteModel+" ", config.RemoteModel)
+			input, _ = reader.ReadString('\n')
+			if model := strings.TrimSpace(input); model != "" {
+				config.RemoteModel = model
+			}
+			fmt.Printf(msg.EnterRemoteAPIKey+" ", getAPIKeyDisplay(config.RemoteAPIKey))
+			input, _ = reader.ReadString('\n')
+			if key := strings.TrimSpace(input); key != "" {
+				config.RemoteAPIKey = key
+			}
+			fmt.Printf(msg.RemoteSet+"\n", config.RemoteBaseURL, config.RemoteModel)
+		}
+		return true
+	}
+
+	fmt.Println(msg.InvalidOption)
+	return false
+}
+
 func resetToDefaults(config *Config, reader *bufio.Reader) bool {
 	msg := getMessages(config)
 	fmt.Printf("%s ", msg.ResetConfirm)
@@ -627,19 +1395,40 @@ func selectFolder(title string) (string, error) {
 // Messages contains all UI text strings
 type Messages struct {
 	// Config menu
-	ConfigTitle      string
-	WhisperModel     string
-	Language         string
-	UILanguage       string
-	ScanInterval     string
-	MaxCPUPercent    string
-	ComputeType      string
-	UseColors        string
-	UIMode           string
-	OutputFormat     string
-	InputDirectory   string
-	OutputDirectory  string
-	ArchiveDirectory string
+	ConfigTitle       string
+	WhisperModel      string
+	Language          string
+	UILanguage        string
+	ScanInterval      string
+	MaxCPUPercent     string
+	MaxConcurrentJobs string
+	WatchMode         string
+	FileSettle        string
+	ComputeType       string
+	UseColors         string
+	UIMode            string
+	OutputFormat      string
+	InputDirectory    string
+	OutputDirectory   string
+	ArchiveDirectory  string
+	FailedDirectory   string
+	MaxRetries        string
+	RecursiveScan     string
+	DuplicateAction   string
+	PauseRecording    string
+	Schedule          string
+	ScheduleAnyTime   string
+	Backend           string
+	ChunkMinutes      string
+	ChunkOff          string
+	WordTimestamps    string
+	LowConfidence     string
+	Hallucinations    string
+	Vocabulary        string
+	VocabularyNone    string
+	VocabularyMode    string
+	CleanTranscript   string
+	SummarySource     string
 	// LLM Settings
 	LLMSummaryEnabled string
 	LLMAPIProvider    string
@@ -658,21 +1447,59 @@ type Messages struct {
 	QuitWithoutSave     string
 	SelectOption        string
 	Minutes             string
+	Seconds             string
 	Current             string
 
 	// Config prompts
-	SelectModel    string
-	EnterLanguage  string
-	SelectUILang   string
-	EnterInterval  string
-	EnterCPU       string
-	SelectCompute  string
-	EnableColors   string
-	SelectUIMode   string
-	SelectFormat   string
-	SelectFolder   string
-	ResetConfirm   string
-	UnsavedChanges string
+	SelectModel         string
+	EnterLanguage       string
+	SelectUILang        string
+	EnterInterval       string
+	EnterCPU            string
+	EnterConcurrentJobs string
+	SelectCompute       string
+	SelectWatchMode     string
+	EnterFileSettle     string
+	EnterMaxRetries     string
+	WatchModeWatchDesc  string
+	WatchModePollDesc   string
+	EnableColors        string
+	EnableRecursiveScan string
+	EnablePause         string
+	EnterSchedule       string
+	SelectDuplicate     string
+	DuplicateReuse      string
+	DuplicateSkip       string
+	DuplicateProcess    string
+	SelectBackend       string
+	BackendCLIDesc      string
+	BackendCppDesc      string
+	BackendRemoteDesc   string
+	BackendMockDesc     string
+	EnterCppModelDir    string
+	EnterRemoteURL      string
+	EnterRemoteModel    string
+	EnterRemoteAPIKey   string
+	EnterChunkMinutes   string
+	EnableWordTiming    string
+	EnterLowConfidence  string
+	SelectHallucination string
+	HallucinationDrop   string
+	HallucinationFlag   string
+	HallucinationOff    string
+	EnterVocabulary     string
+	SelectVocabMode     string
+	VocabPromptDesc     string
+	VocabHotwordsDesc   string
+	EnableCleanup       string
+	SelectSummarySrc    string
+	SummaryCleanDesc    string
+	SummaryVerbatimDesc string
+	SelectUIMode        string
+	SelectFormat        string
+	SelectFolder        string
+	ResetConfirm        string
+	UnsavedChanges      string
 	// LLM prompts
 	EnableLLMSummary   string
 	SelectLLMProvider  string
@@ -684,19 +1511,39 @@ type Messages struct {
 	EnterNewPrompt     string
 
 	// Config messages
-	ModelSet       string
-	LanguageSet    string
-	UILanguageSet  string
-	IntervalSet    string
-	CPUSet         string
-	ComputeSet     string
-	ColorsEnabled  string
-	ColorsDisabled string
-	UIModeSet      string
-	FormatSet      string
-	InputDirSet    string
-	OutputDirSet   string
-	ArchiveDirSet  string
+	ModelSet          string
+	LanguageSet       string
+	UILanguageSet     string
+	IntervalSet       string
+	CPUSet            string
+	ConcurrentJobsSet string
+	ComputeSet        string
+	WatchModeSet      string
+	FileSettleSet     string
+	ColorsEnabled     string
+	ColorsDisabled    string
+	UIModeSet         string
+	FormatSet         string
+	InputDirSet       string
+	OutputDirSet      string
+	ArchiveDirSet     string
+	FailedDirSet      string
+	MaxRetriesSet     string
+	RecursiveScanSet  string
+	DuplicateSet      string
+	PauseRecordingSet string
+	ScheduleSet       string
+	BackendSet        string
+	CppModelDirSet    string
+	RemoteSet         string
+	ChunkMinutesSet   string
+	WordTimestampsSet string
+	LowConfidenceSet  string
+	HallucinationSet  string
+	VocabularySet     string
+	VocabModeSet      string
+	CleanupSet        string
+	SummarySourceSet  string
 	// LLM messages
 	LLMSummaryEnabledMsg  string
 	LLMSummaryDisabledMsg string
@@ -722,19 +1569,40 @@ type Messages struct {
 
 var messagesEN = Messages{
 	// Config menu
-	ConfigTitle:      "KoeMoji-Go Configuration",
-	WhisperModel:     "Whisper Model",
-	Language:         "Language",
-	UILanguage:       "UI Language",
-	ScanInterval:     "Scan Interval",
-	MaxCPUPercent:    "Max CPU Percent",
-	ComputeType:      "Compute Type",
-	UseColors:        "Use Colors",
-	UIMode:           "UI Mode",
-	OutputFormat:     "Output Format",
-	InputDirectory:   "Input Directory",
-	OutputDirectory:  "Output Directory",
-	ArchiveDirectory: "Archive Directory",
+	ConfigTitle:       "KoeMoji-Go Configuration",
+	WhisperModel:      "Whisper Model",
+	Language:          "Language",
+	UILanguage:        "UI Language",
+	ScanInterval:      "Scan Interval",
+	MaxCPUPercent:     "Max CPU Percent",
+	MaxConcurrentJobs: "Max Concurrent Jobs",
+	WatchMode:         "Watch Mode",
+	FileSettle:        "File Settle Time",
+	ComputeType:       "Compute Type",
+	UseColors:         "Use Colors",
+	UIMode:            "UI Mode",
+	OutputFormat:      "Output Format",
+	InputDirectory:    "Input Directory",
+	OutputDirectory:   "Output Directory",
+	ArchiveDirectory:  "Archive Directory",
+	FailedDirectory:   "Failed Directory",
+	MaxRetries:        "Max Retries",
+	RecursiveScan:     "Scan Subfolders",
+	DuplicateAction:   "Duplicate Files",
+	PauseRecording:    "Pause While Recording",
+	Schedule:          "Processing Schedule",
+	ScheduleAnyTime:   "any time",
+	Backend:           "Transcription Backend",
+	ChunkMinutes:      "Split Long Audio",
+	ChunkOff:          "off",
+	WordTimestamps:    "Word Timestamps",
+	LowConfidence:     "Mark Uncertain Words",
+	Hallucinations:    "Hallucination Filter",
+	Vocabulary:        "Custom Vocabulary",
+	VocabularyNone:    "none",
+	VocabularyMode:    "Vocabulary Given As",
+	CleanTranscript:   "Clean Transcript",
+	SummarySource:     "Summary From",
 	// LLM Settings
 	LLMSummaryEnabled: "LLM Summary",
 	LLMAPIProvider:    "LLM API Provider",
@@ -753,21 +1621,59 @@ var messagesEN = Messages{
 	QuitWithoutSave:     "Quit without saving",
 	SelectOption:        "Select option",
 	Minutes:             "minutes",
+	Seconds:             "seconds",
 	Current:             "current",
 
 	// Config prompts
-	SelectModel:    "Select model (1-%d) or press Enter to keep current:",
-	EnterLanguage:  "Enter new language code (e.g., ja, en, zh) or press Enter to keep current:",
-	SelectUILang:   "Select UI language (1-2) or press Enter to keep current:",
-	EnterInterval:  "Enter new scan interval (minutes) or press Enter to keep current:",
-	EnterCPU:       "Enter new max CPU percent (1-100) or press Enter to keep current:",
-	SelectCompute:  "Select compute type (1-%d) or press Enter to keep current:",
-	EnableColors:   "Enable colors? (y/n) or press Enter to keep current:",
-	SelectUIMode:   "Select UI mode (1-2) or press Enter to keep current:",
-	SelectFormat:   "Select output format (1-%d) or press Enter to keep current:",
-	SelectFolder:   "Press Enter to select folder with dialog, or type path manually:",
-	ResetConfirm:   "Are you sure you want to reset all settings to defaults? (y/N):",
-	UnsavedChanges: "You have unsaved changes. Are you sure you want to quit? (y/N):",
+	SelectModel:         "Select model (1-%d) or press Enter to keep current:",
+	EnterLanguage:       "Enter new language code (e.g., ja, en, zh) or press Enter to keep current:",
+	SelectUILang:        "Select UI language (1-2) or press Enter to keep current:",
+	EnterInterval:       "Enter new scan interval (minutes) or press Enter to keep current:",
+	EnterCPU:            "Enter new max CPU percent (1-100) or press Enter to keep current:",
+	EnterConcurrentJobs: "Enter number of files to transcribe in parallel (1-CPU cores) or press Enter to keep current:",
+	SelectCompute:       "Select compute type (1-%d) or press Enter to keep current:",
+	SelectWatchMode:     "Select watch mode (1-%d) or press Enter to keep current:",
+	EnterMaxRetries:     "Enter number of retries after a failed transcription (0-10) or press Enter to keep current:",
+	EnterFileSettle:     "Enter how long a new file must stay unchanged before processing (0-600 seconds) or press Enter to keep current:",
+	WatchModeWatchDesc:  "detect new files immediately",
+	WatchModePollDesc:   "periodic scan only (network shares)",
+	EnableColors:        "Enable colors? (y/n) or press Enter to keep current:",
+	EnableRecursiveScan: "Process audio files in subfolders of the input folder? (y/n) or press Enter to keep current:",
+	EnablePause:         "Pause transcription while recording? (y/n) or press Enter to keep current:",
+	EnterSchedule:       "Enter windows separated by ';' (e.g. mon-fri 18:00-08:00; weekends), '-' for any time, or press Enter to keep current:",
+	SelectDuplicate:     "Select what to do with already transcribed files (1-%d) or press Enter to keep current:",
+	DuplicateReuse:      "copy the earlier transcript and summary under the new name",
+	DuplicateSkip:       "move to archive without transcribing",
+	DuplicateProcess:    "transcribe again",
+	SelectBackend:       "Select transcription backend (1-%d) or press Enter to keep current:",
+	BackendCLIDesc:      "faster-whisper via the whisper-ctranslate2 command (default)",
+	BackendCppDesc:      "whisper.cpp binary with GGML models (no Python needed)",
+	BackendRemoteDesc:   "OpenAI-compatible transcription API (audio is uploaded)",
+	BackendMockDesc:     "fixed test transcripts without a model (tests and demos)",
+	EnterCppModelDir:    "Folder with ggml-<model>.bin files (Enter to keep %s):",
+	EnterRemoteURL:      "API base URL (Enter to keep %s):",
+	EnterRemoteModel:    "Model (Enter to keep %s):",
+	EnterRemoteAPIKey:   "API key (Enter to keep %s):",
+	EnterChunkMinutes:   "Enter chunk length in minutes for long WAV/FLAC files (0 = off, max 240) or press Enter to keep current:",
+	EnableWordTiming:    "Keep the timing and probability of every word (word-highlighted VTT)? (y/n) or press Enter to keep current:",
+	EnterLowConfidence:  "Enter the word probability below which words are marked in txt/md (0-1, e.g. 0.5, 0 = off) or press Enter to keep current:",
+	SelectHallucination: "Select what to do with segments whisper likely made up (1-%d) or press Enter to keep current:",
+	HallucinationDrop:   "remove them from the outputs and the summary",
+	HallucinationFlag:   "keep them marked with [?], leave them out of the summary",
+	HallucinationOff:    "keep everything whisper returned",
+	EnterVocabulary:     "Enter names and terms whisper should recognize, separated by commas, '-' for none, or press Enter to keep current:",
+	SelectVocabMode:     "Select how the vocabulary is given to whisper (1-%d) or press Enter to keep current:",
+	VocabPromptDesc:     "initial prompt (all backends)",
+	VocabHotwordsDesc:   "hotwords (whisper-ctranslate2 only, others use the prompt)",
+	EnableCleanup:       "Also write a clean transcript without fillers, with punctuation and normalized width? (y/n) or press Enter to keep current:",
+	SelectSummarySrc:    "Select the transcript the summary is made from (1-%d) or press Enter to keep current:",
+	SummaryCleanDesc:    "the clean transcript (when it is written)",
+	SummaryVerbatimDesc: "the transcript as whisper wrote it",
+	SelectUIMode:        "Select UI mode (1-2) or press Enter to keep current:",
+	SelectFormat:        "Select output formats (1-%d, several separated by commas, e.g. 1,3) or press Enter to keep current:",
+	SelectFolder:        "Press Enter to select folder with dialog, or type path manually:",
+	ResetConfirm:        "Are you sure you want to reset all settings to defaults? (y/N):",
+	UnsavedChanges:      "You have unsaved changes. Are you sure you want to quit? (y/N):",
 	// LLM prompts
 	EnableLLMSummary:   "Enable LLM summary? (y/n) or press Enter to keep current:",
 	SelectLLMProvider:  "Select LLM provider (1-1) or press Enter to keep current:",
@@ -779,19 +1685,39 @@ var messagesEN = Messages{
 	EnterNewPrompt:     "Enter new prompt or press Enter to keep current:",
 
 	// Config messages
-	ModelSet:       "Whisper model set to: %s",
-	LanguageSet:    "Language set to: %s",
-	UILanguageSet:  "UI language set to: %s",
-	IntervalSet:    "Scan interval set to: %d minutes",
-	CPUSet:         "Max CPU percent set to: %d%%",
-	ComputeSet:     "Compute type set to: %s",
-	ColorsEnabled:  "Colors enabled",
-	ColorsDisabled: "Colors disabled",
-	UIModeSet:      "UI mode set to: %s",
-	FormatSet:      "Output format set to: %s",
-	InputDirSet:    "Input directory set to: %s",
-	OutputDirSet:   "Output directory set to: %s",
-	ArchiveDirSet:  "Archive directory set to: %s",
+	ModelSet:          "Whisper model set to: %s",
+	LanguageSet:       "Language set to: %s",
+	UILanguageSet:     "UI language set to: %s",
+	IntervalSet:       "Scan interval set to: %d minutes",
+	CPUSet:            "Max CPU percent set to: %d%%",
+	ConcurrentJobsSet: "Max concurrent jobs set to: %d",
+	ComputeSet:        "Compute type set to: %s",
+	WatchModeSet:      "Watch mode set to: %s",
+	FileSettleSet:     "File settle time set to: %d seconds",
+	ColorsEnabled:     "Colors enabled",
+	ColorsDisabled:    "Colors disabled",
+	UIModeSet:         "UI mode set to: %s",
+	FormatSet:         "Output format set to: %s",
+	InputDirSet:       "Input directory set to: %s",
+	OutputDirSet:      "Output directory set to: %s",
+	ArchiveDirSet:     "Archive directory set to: %s",
+	FailedDirSet:      "Failed directory set to: %s",
+	MaxRetriesSet:     "Max retries set to: %d",
+	RecursiveScanSet:  "Scan subfolders set to: %t",
+	DuplicateSet:      "Duplicate files set to: %s",
+	PauseRecordingSet: "Pause while recording set to: %t",
+	ScheduleSet:       "Processing schedule set to: %s",
+	BackendSet:        "Transcription backend set to: %s",
+	CppModelDirSet:    "GGML model folder set to: %s",
+	RemoteSet:         "Transcription API set to: %s (model %s)",
+	ChunkMinutesSet:   "Split long audio set to: %s",
+	WordTimestampsSet: "Word timestamps set to: %t",
+	LowConfidenceSet:  "Mark uncertain words set to: %s",
+	HallucinationSet:  "Hallucination filter set to: %s",
+	VocabularySet:     "Custom vocabulary set to: %s",
+	VocabModeSet:      "Vocabulary given as: %s",
+	CleanupSet:        "Clean transcript set to: %t",
+	SummarySourceSet:  "Summary made from: %s",
 	// LLM messages
 	LLMSummaryEnabledMsg:  "LLM summary enabled",
 	LLMSummaryDisabledMsg: "LLM summary disabled",
@@ -817,19 +1743,40 @@ var messagesEN = Messages{
 
 var messagesJA = Messages{
 	// Config menu
-	ConfigTitle:      "KoeMoji-Go 設定",
-	WhisperModel:     "Whisperモデル",
-	Language:         "認識言語",
-	UILanguage:       "UI言語",
-	ScanInterval:     "スキャン間隔",
-	MaxCPUPercent:    "最大CPU使用率",
-	ComputeType:      "計算タイプ",
-	UseColors:        "色を使用",
-	UIMode:           "UIモード",
-	OutputFormat:     "出力フォーマット",
-	InputDirectory:   "入力ディレクトリ",
-	OutputDirectory:  "出力ディレクトリ",
-	ArchiveDirectory: "アーカイブディレクトリ",
+	ConfigTitle:       "KoeMoji-Go 設定",
+	WhisperModel:      "Whisperモデル",
+	Language:          "認識言語",
+	UILanguage:        "UI言語",
+	ScanInterval:      "スキャン間隔",
+	MaxCPUPercent:     "最大CPU使用率",
+	MaxConcurrentJobs: "同時処理数",
+	WatchMode:         "監視モード",
+	FileSettle:        "ファイル安定待ち時間",
+	ComputeType:       "計算タイプ",
+	UseColors:         "色を使用",
+	UIMode:            "UIモード",
+	OutputFormat:      "出力フォーマット",
+	InputDirectory:    "入力ディレクトリ",
+	OutputDirectory:   "出力ディレクトリ",
+	ArchiveDirectory:  "アーカイブディレクトリ",
+	FailedDirectory:   "失敗ファイル保存ディレクトリ",
+	MaxRetries:        "最大リトライ回数",
+	RecursiveScan:     "サブフォルダもスキャン",
+	DuplicateAction:   "重複ファイル",
+	PauseRecording:    "録音中は処理を一時停止",
+	Schedule:          "処理時間帯",
+	ScheduleAnyTime:   "常時",
+	Backend:           "文字起こしエンジン",
+	ChunkMinutes:      "長時間音声の分割",
+	ChunkOff:          "オフ",
+	WordTimestamps:    "単語ごとのタイムスタンプ",
+	LowConfidence:     "信頼度の低い単語をマーク",
+	Hallucinations:    "幻覚フィルタ",
+	Vocabulary:        "カスタム語彙",
+	VocabularyNone:    "なし",
+	VocabularyMode:    "語彙の渡し方",
+	CleanTranscript:   "整文版の出力",
+	SummarySource:     "要約の元",
 	// LLM Settings
 	LLMSummaryEnabled: "LLM要約機能",
 	LLMAPIProvider:    "LLM APIプロバイダー",
@@ -848,21 +1795,59 @@ var messagesJA = Messages{
 	QuitWithoutSave:     "保存せずに終了",
 	SelectOption:        "オプションを選択",
 	Minutes:             "分",
+	Seconds:             "秒",
 	Current:             "現在",
 
 	// Config prompts
-	SelectModel:    "モデルを選択 (1-%d) またはEnterで現在の設定を維持:",
-	EnterLanguage:  "新しい言語コード (例: ja, en, zh) を入力またはEnterで現在の設定を維持:",
-	SelectUILang:   "UI言語を選択 (1-2) またはEnterで現在の設定を維持:",
-	EnterInterval:  "新しいスキャン間隔（分）を入力またはEnterで現在の設定を維持:",
-	EnterCPU:       "新しい最大CPU使用率 (1-100) を入力またはEnterで現在の設定を維持:",
-	SelectCompute:  "計算タイプを選択 (1-%d) またはEnterで現在の設定を維持:",
-	EnableColors:   "色を有効にしますか？ (y/n) またはEnterで現在の設定を維持:",
-	SelectUIMode:   "UIモードを選択 (1-2) またはEnterで現在の設定を維持:",
-	SelectFormat:   "出力フォーマットを選択 (1-%d) またはEnterで現在の設定を維持:",
-	SelectFolder:   "Enterでフォルダ選択ダイアログを開く、または手動でパスを入力:",
-	ResetConfirm:   "本当にすべての設定をデフォルトに戻しますか？ (y/N):",
-	UnsavedChanges: "未保存の変更があります。本当に終了しますか？ (y/N):",
+	SelectModel:         "モデルを選択 (1-%d) またはEnterで現在の設定を維持:",
+	EnterLanguage:       "新しい言語コード (例: ja, en, zh) を入力またはEnterで現在の設定を維持:",
+	SelectUILang:        "UI言語を選択 (1-2) またはEnterで現在の設定を維持:",
+	EnterInterval:       "新しいスキャン間隔（分）を入力またはEnterで現在の設定を維持:",
+	EnterCPU:            "新しい最大CPU使用率 (1-100) を入力またはEnterで現在の設定を維持:",
+	EnterConcurrentJobs: "同時に文字起こしするファイル数 (1-CPUコア数) を入力またはEnterで現在の設定を維持:",
+	SelectCompute:       "計算タイプを選択 (1-%d) またはEnterで現在の設定を維持:",
+	SelectWatchMode:     "監視モードを選択 (1-%d) またはEnterで現在の設定を維持:",
+	EnterMaxRetries:     "文字起こし失敗時のリトライ回数 (0-10) を入力またはEnterで現在の設定を維持:",
+	EnterFileSettle:     "新しいファイルが変化しなくなってから処理を始めるまでの秒数 (0-600) を入力またはEnterで現在の設定を維持:",
+	WatchModeWatchDesc:  "新しいファイルを即座に検出",
+	WatchModePollDesc:   "定期スキャンのみ（ネットワークフォルダ向け）",
+	EnableColors:        "色を有効にしますか？ (y/n) またはEnterで現在の設定を維持:",
+	EnableRecursiveScan: "入力フォルダのサブフォルダ内の音声ファイルも処理しますか？ (y/n) またはEnterで現在の設定を維持:",
+	EnablePause:         "録音中は文字起こしを一時停止しますか？ (y/n) またはEnterで現在の設定を維持:",
+	EnterSchedule:       "処理時間帯を「;」区切りで入力 (例: mon-fri 18:00-08:00; weekends)、「-」で常時、Enterで現在の設定を維持:",
+	SelectDuplicate:     "処理済みと同じ内容のファイルの扱いを選択 (1-%d) またはEnterで現在の設定を維持:",
+	DuplicateReuse:      "以前の文字起こし・要約を新しい名前でコピー",
+	DuplicateSkip:       "文字起こしせずアーカイブへ移動",
+	DuplicateProcess:    "もう一度文字起こし",
+	SelectBackend:       "文字起こしエンジンを選択 (1-%d) またはEnterで現在の設定を維持:",
+	BackendCLIDesc:      "whisper-ctranslate2コマンドでfaster-whisperを実行（既定）",
+	BackendCppDesc:      "whisper.cppの実行ファイルとGGMLモデルで実行（Python不要）",
+	BackendRemoteDesc:   "OpenAI互換の文字起こしAPI（音声をアップロード）",
+	BackendMockDesc:     "モデルを使わず固定のテスト結果を出力（テスト・デモ用）",
+	EnterCppModelDir:    "ggml-<モデル名>.binを置いたフォルダ（Enterで%sのまま）:",
+	EnterRemoteURL:      "APIのベースURL（Enterで%sのまま）:",
+	EnterRemoteModel:    "モデル（Enterで%sのまま）:",
+	EnterRemoteAPIKey:   "APIキー（Enterで%sのまま）:",
+	EnterChunkMinutes:   "長いWAV/FLACを分割する長さ（分）を入力 (0=オフ、最大240) またはEnterで現在の設定を維持:",
+	EnableWordTiming:    "単語ごとの時刻と確率を記録しますか？（単語がハイライトされるVTT） (y/n) またはEnterで現在の設定を維持:",
+	EnterLowConfidence:  "txt/mdでマークする単語の確率のしきい値を入力 (0-1、例: 0.5、0=オフ) またはEnterで現在の設定を維持:",
+	SelectHallucination: "誤認識（幻覚）と思われるセグメントの扱いを選択 (1-%d) またはEnterで現在の設定を維持:",
+	HallucinationDrop:   "出力と要約から削除",
+	HallucinationFlag:   "[?]を付けて残し、要約には含めない",
+	HallucinationOff:    "すべて残す",
+	EnterVocabulary:     "正しく認識させたい名前・用語をカンマ区切りで入力、「-」でなし、Enterで現在の設定を維持:",
+	SelectVocabMode:     "語彙をWhisperに渡す方法を選択 (1-%d) またはEnterで現在の設定を維持:",
+	VocabPromptDesc:     "初期プロンプト（すべてのエンジン）",
+	VocabHotwordsDesc:   "ホットワード（whisper-ctranslate2のみ、他はプロンプト）",
+	EnableCleanup:       "フィラーを除き句読点・全角半角を整えた整文版も出力しますか？ (y/n) またはEnterで現在の設定を維持:",
+	SelectSummarySrc:    "要約に使う文字起こしを選択 (1-%d) またはEnterで現在の設定を維持:",
+	SummaryCleanDesc:    "整文版（出力している場合）",
+	SummaryVerbatimDesc: "Whisperの出力そのまま",
+	SelectUIMode:        "UIモードを選択 (1-2) またはEnterで現在の設定を維持:",
+	SelectFormat:        "出力フォーマットを選択 (1-%d、複数はカンマ区切り 例: 1,3) またはEnterで現在の設定を維持:",
+	SelectFolder:        "Enterでフォルダ選択ダイアログを開く、または手動でパスを入力:",
+	ResetConfirm:        "本当にすべての設定をデフォルトに戻しますか？ (y/N):",
+	UnsavedChanges:      "未保存の変更があります。本当に終了しますか？ (y/N):",
 	// LLM prompts
 	EnableLLMSummary:   "LLM要約を有効にしますか？ (y/n) またはEnterで現在の設定を維持:",
 	SelectLLMProvider:  "LLM APIプロバイダーを選択 (1-1) またはEnterで現在の設定を維持:",
@@ -874,19 +1859,39 @@ var messagesJA = Messages{
 	EnterNewPrompt:     "新しいプロンプトを入力またはEnterで現在の設定を維持:",
 
 	// Config messages
-	ModelSet:       "Whisperモデルを設定: %s",
-	LanguageSet:    "言語を設定: %s",
-	UILanguageSet:  "UI言語を設定: %s",
-	IntervalSet:    "スキャン間隔を設定: %d分",
-	CPUSet:         "最大CPU使用率を設定: %d%%",
-	ComputeSet:     "計算タイプを設定: %s",
-	ColorsEnabled:  "色を有効にしました",
-	ColorsDisabled: "色を無効にしました",
-	UIModeSet:      "UIモードを設定: %s",
-	FormatSet:      "出力フォーマットを設定: %s",
-	InputDirSet:    "入力ディレクトリを設定: %s",
-	OutputDirSet:   "出力ディレクトリを設定: %s",
-	ArchiveDirSet:  "アーカイブディレクトリを設定: %s",
+	ModelSet:          "Whisperモデルを設定: %s",
+	LanguageSet:       "言語を設定: %s",
+	UILanguageSet:     "UI言語を設定: %s",
+	IntervalSet:       "スキャン間隔を設定: %d分",
+	CPUSet:            "最大CPU使用率を設定: %d%%",
+	ConcurrentJobsSet: "同時処理数を設定: %d",
+	ComputeSet:        "計算タイプを設定: %s",
+	WatchModeSet:      "監視モードを設定: %s",
+	FileSettleSet:     "ファイル安定待ち時間を設定: %d秒",
+	ColorsEnabled:     "色を有効にしました",
+	ColorsDisabled:    "色を無効にしました",
+	UIModeSet:         "UIモードを設定: %s",
+	FormatSet:         "出力フォーマットを設定: %s",
+	InputDirSet:       "入力ディレクトリを設定: %s",
+	OutputDirSet:      "出力ディレクトリを設定: %s",
+	ArchiveDirSet:     "アーカイブディレクトリを設定: %s",
+	FailedDirSet:      "失敗ファイル保存ディレクトリを設定: %s",
+	MaxRetriesSet:     "最大リトライ回数を設定: %d",
+	RecursiveScanSet:  "サブフォルダのスキャンを設定: %t",
+	DuplicateSet:      "重複ファイルの扱いを設定: %s",
+	PauseRecordingSet: "録音中の一時停止を設定: %t",
+	ScheduleSet:       "処理時間帯を設定: %s",
+	BackendSet:        "文字起こしエンジンを設定: %s",
+	CppModelDirSet:    "GGMLモデルフォルダを設定: %s",
+	RemoteSet:         "文字起こしAPIを設定: %s（モデル %s）",
+	ChunkMinutesSet:   "長時間音声の分割を設定: %s",
+	WordTimestampsSet: "単語ごとのタイムスタンプを設定: %t",
+	LowConfidenceSet:  "信頼度の低い単語のマークを設定: %s",
+	HallucinationSet:  "幻覚フィルタを設定: %s",
+	VocabularySet:     "カスタム語彙を設定: %s",
+	VocabModeSet:      "語彙の渡し方を設定: %s",
+	CleanupSet:        "整文版の出力を設定: %t",
+	SummarySourceSet:  "要約の元を設定: %s",
 	// LLM messages
 	LLMSummaryEnabledMsg:  "LLM要約機能を有効にしました",
 	LLMSummaryDisabledMsg: "LLM要約機能を無効にしました",
@@ -21,12 +21,36 @@ func TestGetDefaultConfig(t *testing.T) {
 	assert.Equal(t, "ja", config.UILanguage)
 	assert.Equal(t, 1, config.ScanIntervalMinutes)
 	assert.Equal(t, 95, config.MaxCpuPercent)
+	assert.Equal(t, 1, config.MaxConcurrentJobs)
+	assert.Equal(t, WatchModeWatch, config.WatchMode)
+	assert.Equal(t, 5, config.FileSettleSeconds)
+	assert.False(t, config.RecursiveScan)
+	assert.Equal(t, DuplicateActionReuse, config.DuplicateAction)
+	assert.True(t, config.PauseWhileRecording)
 	assert.Equal(t, "int8", config.ComputeType)
 	assert.True(t, config.UseColors)
 	assert.Equal(t, "txt", config.OutputFormat)
 	assert.Equal(t, "./input", config.InputDir)
 	assert.Equal(t, "./output", config.OutputDir)
 	assert.Equal(t, "./archive", config.ArchiveDir)
+	assert.Equal(t, "./failed", config.FailedDir)
+	assert.Equal(t, 2, config.MaxRetries)
+	assert.Equal(t, 30, config.RetryBackoffSeconds)
+	assert.Equal(t, BackendWhisperCTranslate2, config.TranscriptionBackend)
+	assert.Equal(t, "https://api.openai.com/v1", config.RemoteBaseURL)
+	assert.Equal(t, "whisper-1", config.RemoteModel)
+	assert.Equal(t, 25, config.RemoteMaxUploadMB)
+	assert.Equal(t, 0, config.ChunkMinutes)
+	assert.False(t, config.WordTimestamps)
+	assert.Equal(t, 0.0, config.LowConfidenceThreshold)
+	assert.Equal(t, HallucinationFilterFlag, config.HallucinationFilter)
+	assert.Equal(t, DefaultHallucinationPhrases, config.HallucinationPhrases)
+	assert.Empty(t, config.Vocabulary)
+	assert.Equal(t, VocabularyModePrompt, config.VocabularyMode)
+	assert.Equal(t, "./replacements.json", config.ReplacementRulesFile)
+	assert.False(t, config.CleanTranscript)
+	assert.Equal(t, DefaultFillerWords, config.FillerWords)
+	assert.Equal(t, SummarySourceClean, config.SummarySource)
 	assert.False(t, config.LLMSummaryEnabled)
 	assert.Equal(t, "openai", config.LLMAPIProvider)
 	assert.Equal(t, "gpt-4o", config.LLMModel)
@@ -714,3 +738,37 @@ func TestConfigMigration(t *testing.T) {
 		// Unknown fields should be ignored without error
 	})
 }
+
+func TestMirrorDir(t *testing.T) {
+	root := t.TempDir()
+	config := GetDefaultConfig()
+	config.InputDir = filepath.Join(root, "input")
+	output := filepath.Join(root, "output")
+
+	tests := []struct {
+		name      string
+		inputFile string
+		expected  string
+	}{
+		{"Top level file", filepath.Join(config.InputDir, "a.wav"), output},
+		{"Subfolder", filepath.Join(config.InputDir, "clientA", "a.wav"), filepath.Join(output, "clientA")},
+		{"Nested subfolder", filepath.Join(config.InputDir, "clientA", "2026", "a.wav"), filepath.Join(output, "clientA", "2026")},
+		{"Outside input folder", filepath.Join(root, "elsewhere", "a.wav"), output},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, config.MirrorDir(output, tt.inputFile))
+		})
+	}
+}
+
+func TestResolveConfigPaths_EmptyFailedDirStaysEmpty(t *testing.T) {
+	config := GetDefaultConfig()
+	config.FailedDir = ""
+
+	ResolveConfigPaths(config)
+
+	assert.Empty(t, config.FailedDir)
+	assert.True(t, filepath.IsAbs(config.ArchiveDir))
+}
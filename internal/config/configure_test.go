@@ -1,6 +1,8 @@
 package config
 
 import (
+	"runtime"
+	"strconv"
 	"testing"
 
 	"github.com/infoHiroki/KoeMoji-Go/internal/config/testdata"
@@ -167,6 +169,407 @@ func TestConfigureMaxCpuPercent(t *testing.T) {
 	}
 }
 
+func TestConfigureMaxConcurrentJobs(t *testing.T) {
+	tooMany := strconv.Itoa(runtime.NumCPU() + 1)
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+		changed  bool
+	}{
+		{"Set to 1", "1", 1, true},
+		{"Set to CPU count", strconv.Itoa(runtime.NumCPU()), runtime.NumCPU(), true},
+		{"Keep current (empty)", "", 1, false},
+		{"Invalid (zero)", "0", 1, false},
+		{"Invalid (more than CPU cores)", tooMany, 1, false},
+		{"Invalid (text)", "two", 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			reader := testdata.CreateMockReader(tt.input)
+
+			changed := configureMaxConcurrentJobs(config, reader)
+
+			assert.Equal(t, tt.expected, config.MaxConcurrentJobs)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestConfigureWatchMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		changed  bool
+	}{
+		{"Select watch", "1", WatchModeWatch, true},
+		{"Select poll", "2", WatchModePoll, true},
+		{"Keep current (empty)", "", WatchModeWatch, false},
+		{"Invalid input", "3", WatchModeWatch, false},
+		{"Invalid input (text)", "poll", WatchModeWatch, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			reader := testdata.CreateMockReader(tt.input)
+
+			changed := configureWatchMode(config, reader)
+
+			assert.Equal(t, tt.expected, config.WatchMode)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestConfigureRecursiveScan(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+		changed  bool
+	}{
+		{"Enable", "y", true, true},
+		{"Disable", "n", false, true},
+		{"Keep current (empty)", "", false, false},
+		{"Invalid input", "maybe", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			reader := testdata.CreateMockReader(tt.input)
+
+			changed := configureRecursiveScan(config, reader)
+
+			assert.Equal(t, tt.expected, config.RecursiveScan)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestConfigureDuplicateAction(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		changed  bool
+	}{
+		{"Select reuse", "1", DuplicateActionReuse, true},
+		{"Select skip", "2", DuplicateActionSkip, true},
+		{"Select process", "3", DuplicateActionProcess, true},
+		{"Keep current (empty)", "", DuplicateActionReuse, false},
+		{"Invalid input", "4", DuplicateActionReuse, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			reader := testdata.CreateMockReader(tt.input)
+
+			changed := configureDuplicateAction(config, reader)
+
+			assert.Equal(t, tt.expected, config.DuplicateAction)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestConfigureHallucinationFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		changed  bool
+	}{
+		{"Select drop", "1", HallucinationFilterDrop, true},
+		{"Select flag", "2", HallucinationFilterFlag, true},
+		{"Select off", "3", HallucinationFilterOff, true},
+		{"Keep current (empty)", "", HallucinationFilterFlag, false},
+		{"Invalid input", "4", HallucinationFilterFlag, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			reader := testdata.CreateMockReader(tt.input)
+
+			changed := configureHallucinationFilter(config, reader)
+
+			assert.Equal(t, tt.expected, config.HallucinationFilter)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestConfigureVocabulary(t *testing.T) {
+	config := GetDefaultConfig()
+	assert.False(t, configureVocabulary(config, testdata.CreateMockReader("")))
+	assert.Empty(t, config.Vocabulary)
+
+	assert.True(t, configureVocabulary(config, testdata.CreateMockReader("KoeMoji, 山田太郎、KoeMoji")))
+	assert.Equal(t, []string{"KoeMoji", "山田太郎"}, config.Vocabulary)
+
+	assert.True(t, configureVocabulary(config, testdata.CreateMockReader("-")))
+	assert.Empty(t, config.Vocabulary)
+}
+
+func TestConfigureVocabularyMode(t *testing.T) {
+	config := GetDefaultConfig()
+	assert.False(t, configureVocabularyMode(config, testdata.CreateMockReader("3")))
+	assert.True(t, configureVocabularyMode(config, testdata.CreateMockReader("2")))
+	assert.Equal(t, VocabularyModeHotwords, config.VocabularyMode)
+}
+
+func TestConfigureCleanTranscript(t *testing.T) {
+	config := GetDefaultConfig()
+	assert.False(t, configureCleanTranscript(config, testdata.CreateMockReader("")))
+	assert.False(t, configureCleanTranscript(config, testdata.CreateMockReader("maybe")))
+	assert.True(t, configureCleanTranscript(config, testdata.CreateMockReader("y")))
+	assert.True(t, config.CleanTranscript)
+
+	assert.False(t, configureSummarySource(config, testdata.CreateMockReader("3")))
+	assert.True(t, configureSummarySource(config, testdata.CreateMockReader("2")))
+	assert.Equal(t, SummarySourceVerbatim, config.SummarySource)
+}
+
+func TestConfigurePauseWhileRecording(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+		changed  bool
+	}{
+		{"Enable", "y", true, true},
+		{"Disable", "n", false, true},
+		{"Keep current (empty)", "", true, false},
+		{"Invalid input", "maybe", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			reader := testdata.CreateMockReader(tt.input)
+
+			changed := configurePauseWhileRecording(config, reader)
+
+			assert.Equal(t, tt.expected, config.PauseWhileRecording)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestConfigureSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+		changed  bool
+	}{
+		{"Set windows", "mon-fri 18:00-08:00; weekends", []string{"mon-fri 18:00-08:00", "weekends"}, true},
+		{"Any time", "-", nil, true},
+		{"Keep current (empty)", "", []string{"weekends"}, false},
+		{"Invalid input", "sometimes", []string{"weekends"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.ProcessingSchedule = []string{"weekends"}
+			reader := testdata.CreateMockReader(tt.input)
+
+			changed := configureSchedule(config, reader)
+
+			assert.Equal(t, tt.expected, config.ProcessingSchedule)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestConfigureBackend(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		changed  bool
+	}{
+		{"Select whisper-ctranslate2", "1", BackendWhisperCTranslate2, true},
+		{"Select whisper.cpp", "2", BackendWhisperCpp, true},
+		{"Select OpenAI-compatible API", "3", BackendOpenAI, true},
+		{"Select mock", "4", BackendMock, true},
+		{"Keep current (empty)", "", BackendWhisperCTranslate2, false},
+		{"Invalid input", "9", BackendWhisperCTranslate2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			reader := testdata.CreateMockReader(tt.input)
+
+			changed := configureBackend(config, reader)
+
+			assert.Equal(t, tt.expected, config.TranscriptionBackend)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestConfigureBackend_WhisperCppModelDir(t *testing.T) {
+	config := GetDefaultConfig()
+	reader := testdata.CreateMockReader("2", "/opt/ggml")
+
+	assert.True(t, configureBackend(config, reader))
+	assert.Equal(t, BackendWhisperCpp, config.TranscriptionBackend)
+	assert.Equal(t, "/opt/ggml", config.WhisperCppModelDir)
+}
+
+func TestConfigureBackend_RemoteSettings(t *testing.T) {
+	config := GetDefaultConfig()
+	reader := testdata.CreateMockReader("3", "http://192.168.1.10:8000/v1/", "Systran/faster-whisper-large-v3", "")
+
+	assert.True(t, configureBackend(config, reader))
+	assert.Equal(t, BackendOpenAI, config.TranscriptionBackend)
+	assert.Equal(t, "http://192.168.1.10:8000/v1", config.RemoteBaseURL)
+	assert.Equal(t, "Systran/faster-whisper-large-v3", config.RemoteModel)
+	assert.Equal(t, "", config.RemoteAPIKey, "empty input keeps the key")
+}
+
+func TestConfigureChunkMinutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+		changed  bool
+	}{
+		{"Set to 20", "20", 20, true},
+		{"Disable (zero)", "0", 0, true},
+		{"Keep current (empty)", "", 0, false},
+		{"Invalid (negative)", "-5", 0, false},
+		{"Invalid (too long)", "241", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			reader := testdata.CreateMockReader(tt.input)
+
+			changed := configureChunkMinutes(config, reader)
+
+			assert.Equal(t, tt.expected, config.ChunkMinutes)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestConfigureWordTimestamps(t *testing.T) {
+	config := GetDefaultConfig()
+	assert.False(t, configureWordTimestamps(config, testdata.CreateMockReader("")))
+	assert.False(t, configureWordTimestamps(config, testdata.CreateMockReader("maybe")))
+	assert.False(t, config.WordTimestamps)
+
+	assert.True(t, configureWordTimestamps(config, testdata.CreateMockReader("y")))
+	assert.True(t, config.WordTimestamps)
+	assert.True(t, config.WordTimestampsNeeded())
+}
+
+func TestConfigureLowConfidence(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected float64
+		changed  bool
+	}{
+		{"Set to 0.5", "0.5", 0.5, true},
+		{"Disable (zero)", "0", 0, true},
+		{"Keep current (empty)", "", 0.3, false},
+		{"Invalid (negative)", "-0.1", 0.3, false},
+		{"Invalid (one or more)", "1", 0.3, false},
+		{"Invalid (text)", "low", 0.3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			config.LowConfidenceThreshold = 0.3
+			reader := testdata.CreateMockReader(tt.input)
+
+			changed := configureLowConfidence(config, reader)
+
+			assert.Equal(t, tt.expected, config.LowConfidenceThreshold)
+			assert.Equal(t, tt.changed, changed)
+			assert.Equal(t, tt.expected > 0, config.WordTimestampsNeeded(), "marking needs the word timing")
+		})
+	}
+}
+
+func TestConfigureFileSettle(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+		changed  bool
+	}{
+		{"Set to 30", "30", 30, true},
+		{"Disable (zero)", "0", 0, true},
+		{"Maximum", "600", 600, true},
+		{"Keep current (empty)", "", 5, false},
+		{"Invalid (negative)", "-1", 5, false},
+		{"Invalid (too long)", "601", 5, false},
+		{"Invalid (text)", "soon", 5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			reader := testdata.CreateMockReader(tt.input)
+
+			changed := configureFileSettle(config, reader)
+
+			assert.Equal(t, tt.expected, config.FileSettleSeconds)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestConfigureMaxRetries(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected int
+		changed  bool
+	}{
+		{"Set to 5", "5", 5, true},
+		{"Disable retries", "0", 0, true},
+		{"Keep current (empty)", "", 2, false},
+		{"Invalid (negative)", "-1", 2, false},
+		{"Invalid (too many)", "11", 2, false},
+		{"Invalid (text)", "many", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := GetDefaultConfig()
+			reader := testdata.CreateMockReader(tt.input)
+
+			changed := configureMaxRetries(config, reader)
+
+			assert.Equal(t, tt.expected, config.MaxRetries)
+			assert.Equal(t, tt.changed, changed)
+		})
+	}
+}
+
+func TestConfigureFailedDir_TypedPath(t *testing.T) {
+	config := GetDefaultConfig()
+	reader := testdata.CreateMockReader("./quarantine")
+
+	assert.True(t, configureFailedDir(config, reader))
+	assert.Equal(t, "./quarantine", config.FailedDir)
+}
+
 func TestConfigureComputeType(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -239,8 +642,9 @@ func TestConfigureOutputFormat(t *testing.T) {
 		{"Select srt", "3", "srt", true},
 		{"Select tsv", "4", "tsv", true},
 		{"Select json", "5", "json", true},
+		{"Select md", "6", "md", true},
 		{"Keep current (empty)", "", "txt", false},
-		{"Invalid input", "6", "txt", false},
+		{"Invalid input", "8", "txt", false},
 		{"Invalid input (text)", "xml", "txt", false},
 	}
 
@@ -258,6 +662,20 @@ func TestConfigureOutputFormat(t *testing.T) {
 	}
 }
 
+func TestConfigureOutputFormat_Several(t *testing.T) {
+	config := GetDefaultConfig()
+	assert.True(t, configureOutputFormat(config, testdata.CreateMockReader("3, 1")))
+	assert.Equal(t, []string{"srt", "txt"}, config.Formats())
+	assert.Equal(t, "srt", config.OutputFormat, "output_format follows the first format")
+
+	assert.True(t, configureOutputFormat(config, testdata.CreateMockReader("7")))
+	assert.Equal(t, []string{"all"}, config.OutputFormats)
+	assert.Equal(t, OutputFormatChoices, config.Formats())
+
+	assert.False(t, configureOutputFormat(config, testdata.CreateMockReader("1,9")))
+	assert.Equal(t, []string{"all"}, config.OutputFormats, "invalid input keeps the formats")
+}
+
 func TestConfigureDirectories(t *testing.T) {
 	t.Run("ConfigureInputDir", func(t *testing.T) {
 		config := GetDefaultConfig()
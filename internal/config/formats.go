@@ -0,0 +1,69 @@
+package config
+
+import "strings"
+
+// OutputFormatAll selects every format of OutputFormatChoices, like
+// whisper's --output_format all
+const OutputFormatAll = "all"
+
+// OutputFormatChoices are the transcript formats that can be written
+var OutputFormatChoices = []string{"txt", "vtt", "srt", "tsv", "json", "md"}
+
+// Formats returns the transcript formats to write for each file:
+// output_formats with "all" expanded and duplicates removed, or
+// output_format when output_formats is empty. The default is txt.
+func (c *Config) Formats() []string {
+	formats := c.OutputFormats
+	if len(formats) == 0 {
+		formats = []string{c.OutputFormat}
+	}
+
+	var result []string
+	add := func(format string) {
+		for _, f := range result {
+			if f == format {
+				return
+			}
+		}
+		result = append(result, format)
+	}
+	for _, format := range formats {
+		format = strings.ToLower(strings.TrimSpace(format))
+		switch format {
+		case "":
+		case OutputFormatAll:
+			for _, f := range OutputFormatChoices {
+				add(f)
+			}
+		default:
+			add(format)
+		}
+	}
+	if len(result) == 0 {
+		return []string{"txt"}
+	}
+	return result
+}
+
+// SetFormats sets output_formats and keeps output_format on the first
+// format, so that older versions reading the config still write something
+func (c *Config) SetFormats(formats []string) {
+	c.OutputFormats = formats
+	if expanded := c.Formats(); len(expanded) > 0 {
+		c.OutputFormat = expanded[0]
+	}
+}
+
+// FormatsDisplay returns the output formats for the settings screens
+func (c *Config) FormatsDisplay() string {
+	if len(c.OutputFormats) == 1 && strings.EqualFold(c.OutputFormats[0], OutputFormatAll) {
+		return OutputFormatAll
+	}
+	return strings.Join(c.Formats(), ", ")
+}
+
+// WordTimestampsNeeded reports whether whisper has to return the timing of
+// every word: for word_timestamps itself or to mark uncertain words
+func (c *Config) WordTimestampsNeeded() bool {
+	return c.WordTimestamps || c.LowConfidenceThreshold > 0
+}
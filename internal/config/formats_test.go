@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		formats  []string
+		expected []string
+	}{
+		{"Legacy output_format", "srt", nil, []string{"srt"}},
+		{"output_formats wins", "srt", []string{"txt", "vtt"}, []string{"txt", "vtt"}},
+		{"All", "txt", []string{"all"}, []string{"txt", "vtt", "srt", "tsv", "json", "md"}},
+		{"Duplicates removed", "", []string{"srt", "SRT", "all"}, []string{"srt", "txt", "vtt", "tsv", "json", "md"}},
+		{"Nothing set", "", nil, []string{"txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{OutputFormat: tt.format, OutputFormats: tt.formats}
+			assert.Equal(t, tt.expected, c.Formats())
+		})
+	}
+}
+
+func TestSetFormats(t *testing.T) {
+	c := GetDefaultConfig()
+	c.SetFormats([]string{"all"})
+	assert.Equal(t, "txt", c.OutputFormat)
+	assert.Equal(t, "all", c.FormatsDisplay())
+
+	c.SetFormats([]string{"vtt", "txt"})
+	assert.Equal(t, "vtt", c.OutputFormat)
+	assert.Equal(t, "vtt, txt", c.FormatsDisplay())
+}
@@ -38,6 +38,43 @@ func ResolveConfigPaths(config *Config) {
 	config.InputDir = ResolvePath(config.InputDir)
 	config.OutputDir = ResolvePath(config.OutputDir)
 	config.ArchiveDir = ResolvePath(config.ArchiveDir)
+	// An empty failed_dir leaves failed files in place
+	if config.FailedDir != "" {
+		config.FailedDir = ResolvePath(config.FailedDir)
+	}
+	if config.ReplacementRulesFile != "" {
+		config.ReplacementRulesFile = ResolvePath(config.ReplacementRulesFile)
+	}
+}
+
+// MirrorDir returns the folder under baseDir that corresponds to the subfolder
+// of inputFile below InputDir, e.g. input/clientA/a.wav maps to output/clientA.
+// Files directly in the input folder (or outside it) map to baseDir itself.
+func (c *Config) MirrorDir(baseDir, inputFile string) string {
+	rel, ok := c.inputSubdir(inputFile)
+	if !ok || rel == "." {
+		return baseDir
+	}
+	return filepath.Join(baseDir, rel)
+}
+
+// inputSubdir returns the folder of inputFile relative to InputDir ("." for
+// files directly in it). ok is false when the file is not below InputDir.
+func (c *Config) inputSubdir(inputFile string) (string, bool) {
+	inputDir, err := filepath.Abs(c.InputDir)
+	if err != nil {
+		return "", false
+	}
+	fileDir, err := filepath.Abs(filepath.Dir(inputFile))
+	if err != nil {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(inputDir, fileDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
 }
 
 // GetRelativePath converts absolute path to relative path from executable directory
@@ -105,3 +142,9 @@ func GetConfigFilePath() string {
 	baseDir := GetAppBaseDir()
 	return filepath.Join(baseDir, "config.json")
 }
+
+// GetJobStorePath returns the path of the persistent job database
+func GetJobStorePath() string {
+	baseDir := GetAppBaseDir()
+	return filepath.Join(baseDir, "jobs.json")
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ProfileFileName is the per-folder profile placed inside an input subfolder
+const ProfileFileName = ".koemoji.json"
+
+// Profile overrides whisper and LLM settings for a group of input files.
+// Empty fields keep the value of the main config.
+type Profile struct {
+	Name                  string `json:"name,omitempty"` // Shown in the UI and job history (defaults to the pattern or folder)
+	TranscriptionBackend  string `json:"transcription_backend,omitempty"`
+	WhisperModel          string `json:"whisper_model,omitempty"`
+	Language              string `json:"language,omitempty"`
+	ComputeType           string `json:"compute_type,omitempty"`
+	LLMSummaryEnabled     *bool  `json:"llm_summary_enabled,omitempty"`
+	LLMModel              string `json:"llm_model,omitempty"`
+	LLMMaxTokens          int    `json:"llm_max_tokens,omitempty"`
+	SummaryPromptTemplate string `json:"summary_prompt_template,omitempty"`
+	SummaryLanguage       string `json:"summary_language,omitempty"`
+	// Words added to the vocabulary of the main config, e.g. the client's names
+	Vocabulary     []string `json:"vocabulary,omitempty"`
+	VocabularyMode string   `json:"vocabulary_mode,omitempty"`
+	// e.g. the clean transcript only for the meetings folder
+	CleanTranscript *bool `json:"clean_transcript,omitempty"`
+}
+
+// apply copies the fields set in p over c
+func (p Profile) apply(c *Config) {
+	if p.TranscriptionBackend != "" {
+		c.TranscriptionBackend = p.TranscriptionBackend
+	}
+	if p.WhisperModel != "" {
+		c.WhisperModel = p.WhisperModel
+	}
+	if p.Language != "" {
+		c.Language = p.Language
+	}
+	if p.ComputeType != "" {
+		c.ComputeType = p.ComputeType
+	}
+	if p.LLMSummaryEnabled != nil {
+		c.LLMSummaryEnabled = *p.LLMSummaryEnabled
+	}
+	if p.LLMModel != "" {
+		c.LLMModel = p.LLMModel
+	}
+	if p.LLMMaxTokens > 0 {
+		c.LLMMaxTokens = p.LLMMaxTokens
+	}
+	if p.SummaryPromptTemplate != "" {
+		c.SummaryPromptTemplate = p.SummaryPromptTemplate
+	}
+	if p.SummaryLanguage != "" {
+		c.SummaryLanguage = p.SummaryLanguage
+	}
+	if len(p.Vocabulary) > 0 {
+		c.Vocabulary = mergeVocabulary(c.Vocabulary, p.Vocabulary)
+	}
+	if p.VocabularyMode != "" {
+		c.VocabularyMode = p.VocabularyMode
+	}
+	if p.CleanTranscript != nil {
+		c.CleanTranscript = *p.CleanTranscript
+	}
+}
+
+// ResolveProfile returns the settings to use for inputFile and the name of
+// the profiles applied to it (empty when none apply).
+//
+// Matching entries of Profiles are applied first, in alphabetical order of
+// their patterns. Then every .koemoji.json from InputDir down to the file's
+// folder is applied, so the file closest to the audio file wins.
+// The returned config is a copy; c is not modified.
+func (c *Config) ResolveProfile(inputFile string) (*Config, string, error) {
+	effective := *c
+	var names []string
+
+	rel, ok := c.inputSubdir(inputFile)
+	if !ok {
+		return &effective, "", nil
+	}
+	relFile := path.Join(filepath.ToSlash(rel), filepath.Base(inputFile))
+
+	patterns := make([]string, 0, len(c.Profiles))
+	for pattern := range c.Profiles {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if !matchProfilePattern(pattern, relFile) {
+			continue
+		}
+		profile := c.Profiles[pattern]
+		profile.apply(&effective)
+		names = append(names, profileName(profile, pattern))
+	}
+
+	relDir := "."
+	for _, part := range profileDirs(rel) {
+		relDir = path.Join(relDir, part)
+		profilePath := filepath.Join(c.InputDir, filepath.FromSlash(relDir), ProfileFileName)
+		profile, found, err := loadProfileFile(profilePath)
+		if err != nil {
+			return &effective, strings.Join(names, "+"), err
+		}
+		if found {
+			// Unnamed folder profiles are shown by their folder path
+			profile.apply(&effective)
+			names = append(names, profileName(profile, relDir))
+		}
+	}
+
+	return &effective, strings.Join(names, "+"), nil
+}
+
+// matchProfilePattern reports whether pattern matches relFile (a slash
+// separated path relative to the input folder). A pattern matching one of
+// the parent folders applies to everything below it, and a pattern without
+// "/" is also matched against the file name alone.
+func matchProfilePattern(pattern, relFile string) bool {
+	pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+	if !strings.Contains(pattern, "/") {
+		if ok, _ := path.Match(pattern, path.Base(relFile)); ok {
+			return true
+		}
+	}
+	for p := relFile; p != "." && p != "/" && p != ""; p = path.Dir(p) {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// profileDirs returns the folder names to walk from the input folder ("")
+// down to rel
+func profileDirs(rel string) []string {
+	dirs := []string{""}
+	if rel == "." {
+		return dirs
+	}
+	return append(dirs, strings.Split(filepath.ToSlash(rel), "/")...)
+}
+
+func profileName(profile Profile, fallback string) string {
+	if profile.Name != "" {
+		return profile.Name
+	}
+	return fallback
+}
+
+// loadProfileFile reads a .koemoji.json. found is false when the file does not exist.
+func loadProfileFile(filePath string) (Profile, bool, error) {
+	var profile Profile
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return profile, false, nil
+	}
+	if err != nil {
+		return profile, false, fmt.Errorf("failed to read profile %s: %w", filePath, err)
+	}
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return profile, false, fmt.Errorf("failed to parse profile %s: %w", filePath, err)
+	}
+	return profile, true, nil
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProfileFile(t *testing.T, dir, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ProfileFileName), []byte(content), 0644))
+}
+
+func TestResolveProfile_NoProfiles(t *testing.T) {
+	config := GetDefaultConfig()
+	config.InputDir = t.TempDir()
+
+	effective, name, err := config.ResolveProfile(filepath.Join(config.InputDir, "a.wav"))
+	require.NoError(t, err)
+	assert.Empty(t, name)
+	assert.Equal(t, config.WhisperModel, effective.WhisperModel)
+	assert.Equal(t, config.Language, effective.Language)
+}
+
+func TestResolveProfile_GlobFromConfig(t *testing.T) {
+	config := GetDefaultConfig()
+	config.InputDir = t.TempDir()
+	summary := true
+	config.Profiles = map[string]Profile{
+		"english":  {Language: "en", WhisperModel: "medium", LLMSummaryEnabled: &summary},
+		"*_en.m4a": {Name: "english-file", Language: "en"},
+	}
+
+	// A folder pattern applies to everything below the folder
+	effective, name, err := config.ResolveProfile(filepath.Join(config.InputDir, "english", "2026", "call.m4a"))
+	require.NoError(t, err)
+	assert.Equal(t, "english", name)
+	assert.Equal(t, "en", effective.Language)
+	assert.Equal(t, "medium", effective.WhisperModel)
+	assert.True(t, effective.LLMSummaryEnabled)
+	assert.Equal(t, config.ComputeType, effective.ComputeType, "unset fields keep the main config value")
+
+	// A pattern without "/" also matches the file name in any folder
+	effective, name, err = config.ResolveProfile(filepath.Join(config.InputDir, "misc", "call_en.m4a"))
+	require.NoError(t, err)
+	assert.Equal(t, "english-file", name)
+	assert.Equal(t, "en", effective.Language)
+	assert.Equal(t, config.WhisperModel, effective.WhisperModel)
+
+	// The original config is not modified
+	assert.Equal(t, "ja", config.Language)
+	assert.False(t, config.LLMSummaryEnabled)
+}
+
+func TestResolveProfile_FolderFileOverridesConfig(t *testing.T) {
+	config := GetDefaultConfig()
+	config.InputDir = t.TempDir()
+	config.Profiles = map[string]Profile{
+		"calls": {Name: "calls", Language: "en", WhisperModel: "medium"},
+	}
+	writeProfileFile(t, filepath.Join(config.InputDir, "calls", "internal"),
+		`{"language": "ja", "summary_prompt_template": "社内会議の要約"}`)
+
+	effective, name, err := config.ResolveProfile(filepath.Join(config.InputDir, "calls", "internal", "a.wav"))
+	require.NoError(t, err)
+	assert.Equal(t, "calls+calls/internal", name)
+	assert.Equal(t, "ja", effective.Language, "the closest profile wins")
+	assert.Equal(t, "medium", effective.WhisperModel)
+	assert.Equal(t, "社内会議の要約", effective.SummaryPromptTemplate)
+
+	// Files in the parent folder are not affected by the subfolder profile
+	effective, _, err = config.ResolveProfile(filepath.Join(config.InputDir, "calls", "b.wav"))
+	require.NoError(t, err)
+	assert.Equal(t, "en", effective.Language)
+}
+
+func TestResolveProfile_Vocabulary(t *testing.T) {
+	config := GetDefaultConfig()
+	config.InputDir = t.TempDir()
+	config.Vocabulary = make([]string, 1, 4)
+	config.Vocabulary[0] = "KoeMoji"
+	config.Profiles = map[string]Profile{
+		"clientA": {Name: "clientA", Vocabulary: []string{"山田", "KoeMoji"}},
+	}
+	writeProfileFile(t, filepath.Join(config.InputDir, "clientA", "sales"),
+		`{"vocabulary": ["佐藤"], "vocabulary_mode": "hotwords"}`)
+
+	effective, _, err := config.ResolveProfile(filepath.Join(config.InputDir, "clientA", "sales", "a.wav"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"KoeMoji", "山田", "佐藤"}, effective.Vocabulary, "profiles add words")
+	assert.Equal(t, VocabularyModeHotwords, effective.VocabularyMode)
+	assert.Equal(t, []string{"KoeMoji"}, config.Vocabulary, "the main config is not modified")
+	assert.Equal(t, map[string][]string{"clientA": {"山田", "KoeMoji"}, "clientA/sales": {"佐藤"}}, config.ProfileVocabularies())
+}
+
+func TestVocabularyText(t *testing.T) {
+	config := GetDefaultConfig()
+	config.Vocabulary = ParseVocabulary("KoeMoji, 山田太郎\n Kubernetes ,,")
+	assert.Equal(t, []string{"KoeMoji", "山田太郎", "Kubernetes"}, config.Vocabulary)
+	assert.Equal(t, "KoeMoji、山田太郎、Kubernetes", config.VocabularyText())
+	config.Language = "en"
+	assert.Equal(t, "KoeMoji, 山田太郎, Kubernetes", config.VocabularyText())
+	assert.False(t, config.VocabularyHotwords())
+}
+
+func TestResolveProfile_InvalidFolderFile(t *testing.T) {
+	config := GetDefaultConfig()
+	config.InputDir = t.TempDir()
+	writeProfileFile(t, filepath.Join(config.InputDir, "broken"), `{"language": `)
+
+	_, _, err := config.ResolveProfile(filepath.Join(config.InputDir, "broken", "a.wav"))
+	assert.Error(t, err)
+}
+
+func TestMatchProfilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		relFile string
+		want    bool
+	}{
+		{"english", "english/a.wav", true},
+		{"english/", "english/sub/a.wav", true},
+		{"english", "japanese/a.wav", false},
+		{"clients/*", "clients/acme/a.wav", true},
+		{"*.mp3", "a.mp3", true},
+		{"*.mp3", "sub/a.mp3", true},
+		{"sub/*.mp3", "sub/a.mp3", true},
+		{"sub/*.mp3", "other/a.mp3", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.relFile, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchProfilePattern(tt.pattern, tt.relFile))
+		})
+	}
+}
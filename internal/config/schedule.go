@@ -0,0 +1,201 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScheduleWindow is one entry of processing_schedule: the weekdays it starts
+// on and a daily time range in minutes after midnight. A range whose end is
+// not after its start runs past midnight into the next day.
+type ScheduleWindow struct {
+	Days  [7]bool // Indexed by time.Weekday
+	Start int
+	End   int
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseScheduleWindow parses "[days] [HH:MM-HH:MM]", for example
+// "mon-fri 18:00-08:00", "sat,sun" or "22:00-06:00".
+// Days are "*", "daily", "weekdays", "weekends", names (mon, tue, ...),
+// comma separated lists and ranges. Missing days mean every day, a missing
+// time range means the whole day.
+func ParseScheduleWindow(s string) (ScheduleWindow, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 || len(fields) > 2 {
+		return ScheduleWindow{}, fmt.Errorf("invalid schedule %q: expected \"[days] [HH:MM-HH:MM]\"", s)
+	}
+
+	w := ScheduleWindow{Start: 0, End: 24 * 60}
+	daysField, timeField := fields[0], ""
+	if len(fields) == 2 {
+		timeField = fields[1]
+	} else if strings.Contains(daysField, ":") {
+		daysField, timeField = "*", daysField
+	}
+
+	if err := parseScheduleDays(daysField, &w.Days); err != nil {
+		return ScheduleWindow{}, fmt.Errorf("invalid schedule %q: %w", s, err)
+	}
+	if timeField != "" {
+		start, end, ok := strings.Cut(timeField, "-")
+		if !ok {
+			return ScheduleWindow{}, fmt.Errorf("invalid schedule %q: time range must be HH:MM-HH:MM", s)
+		}
+		var err error
+		if w.Start, err = parseClock(start); err != nil {
+			return ScheduleWindow{}, fmt.Errorf("invalid schedule %q: %w", s, err)
+		}
+		if w.End, err = parseClock(end); err != nil {
+			return ScheduleWindow{}, fmt.Errorf("invalid schedule %q: %w", s, err)
+		}
+		if w.Start == w.End {
+			return ScheduleWindow{}, fmt.Errorf("invalid schedule %q: empty time range", s)
+		}
+	}
+	return w, nil
+}
+
+// ParseScheduleList splits windows entered as one ";" separated line (menus
+// and settings dialogs) and checks each of them
+func ParseScheduleList(text string) ([]string, error) {
+	var schedule []string
+	for _, entry := range strings.Split(text, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, err := ParseScheduleWindow(entry); err != nil {
+			return nil, err
+		}
+		schedule = append(schedule, entry)
+	}
+	return schedule, nil
+}
+
+// parseScheduleDays marks the weekdays listed in s
+func parseScheduleDays(s string, days *[7]bool) error {
+	switch s {
+	case "*", "daily":
+		for d := range days {
+			days[d] = true
+		}
+		return nil
+	case "weekdays":
+		s = "mon-fri"
+	case "weekends":
+		s = "sat,sun"
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := weekdayNames[from]
+		if !ok {
+			return fmt.Errorf("unknown day %q", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdayNames[to]; !ok {
+				return fmt.Errorf("unknown day %q", to)
+			}
+		}
+		// Ranges may wrap around the week (fri-mon)
+		for d := first; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseClock parses HH:MM (00:00-24:00) into minutes after midnight
+func parseClock(s string) (int, error) {
+	hh, mm, ok := strings.Cut(s, ":")
+	h, errH := strconv.Atoi(hh)
+	m, errM := strconv.Atoi(mm)
+	if !ok || errH != nil || errM != nil || h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return h*60 + m, nil
+}
+
+// Contains reports whether t falls inside the window
+func (w ScheduleWindow) Contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+	if w.Start < w.End {
+		return w.Days[day] && minute >= w.Start && minute < w.End
+	}
+	// Runs past midnight: the evening part of today or the morning part
+	// of a window that started yesterday
+	yesterday := (day + 6) % 7
+	return (w.Days[day] && minute >= w.Start) || (w.Days[yesterday] && minute < w.End)
+}
+
+// nextStart returns the first start of the window after t
+func (w ScheduleWindow) nextStart(t time.Time) time.Time {
+	for i := 0; i <= 7; i++ {
+		day := time.Date(t.Year(), t.Month(), t.Day()+i, 0, 0, 0, 0, t.Location())
+		if !w.Days[day.Weekday()] {
+			continue
+		}
+		start := day.Add(time.Duration(w.Start) * time.Minute)
+		if start.After(t) {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+// ProcessingWindows parses processing_schedule. An empty list means no
+// restriction.
+func (c *Config) ProcessingWindows() ([]ScheduleWindow, error) {
+	windows := make([]ScheduleWindow, 0, len(c.ProcessingSchedule))
+	for _, entry := range c.ProcessingSchedule {
+		w, err := ParseScheduleWindow(entry)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// InProcessingWindow reports whether files may be transcribed at t.
+// Without a schedule, or with an invalid one, processing is always allowed.
+func (c *Config) InProcessingWindow(t time.Time) bool {
+	windows, err := c.ProcessingWindows()
+	if err != nil || len(windows) == 0 {
+		return true
+	}
+	for _, w := range windows {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextProcessingWindow returns when the next processing window starts after t.
+// It reports false when there is no (valid) schedule.
+func (c *Config) NextProcessingWindow(t time.Time) (time.Time, bool) {
+	windows, err := c.ProcessingWindows()
+	if err != nil || len(windows) == 0 {
+		return time.Time{}, false
+	}
+	var next time.Time
+	for _, w := range windows {
+		if start := w.nextStart(t); !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return next, !next.IsZero()
+}
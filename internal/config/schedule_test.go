@@ -0,0 +1,94 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// at returns a local time in the week starting Sunday 2026-10-11
+func at(day time.Weekday, hour, minute int) time.Time {
+	return time.Date(2026, 10, 11+int(day), hour, minute, 0, 0, time.Local)
+}
+
+func TestParseScheduleWindow(t *testing.T) {
+	w, err := ParseScheduleWindow("mon-fri 18:00-08:00")
+	require.NoError(t, err)
+	assert.Equal(t, [7]bool{false, true, true, true, true, true, false}, w.Days)
+	assert.Equal(t, 18*60, w.Start)
+	assert.Equal(t, 8*60, w.End)
+
+	w, err = ParseScheduleWindow("Weekends")
+	require.NoError(t, err)
+	assert.Equal(t, [7]bool{true, false, false, false, false, false, true}, w.Days)
+	assert.Equal(t, 0, w.Start)
+	assert.Equal(t, 24*60, w.End)
+
+	w, err = ParseScheduleWindow("22:00-24:00")
+	require.NoError(t, err)
+	assert.Equal(t, [7]bool{true, true, true, true, true, true, true}, w.Days)
+
+	w, err = ParseScheduleWindow("fri-mon,wed")
+	require.NoError(t, err)
+	assert.Equal(t, [7]bool{true, true, false, true, false, true, true}, w.Days)
+
+	for _, invalid := range []string{"", "someday", "mon 18:00", "mon 25:00-26:00", "mon 10:00-10:00", "mon 10:00-12:00 extra"} {
+		_, err := ParseScheduleWindow(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestScheduleWindow_Contains(t *testing.T) {
+	w, err := ParseScheduleWindow("mon-fri 18:00-08:00")
+	require.NoError(t, err)
+
+	assert.True(t, w.Contains(at(time.Monday, 18, 0)))
+	assert.True(t, w.Contains(at(time.Tuesday, 7, 59)), "the morning after a weekday evening")
+	assert.True(t, w.Contains(at(time.Saturday, 3, 0)), "Friday night runs into Saturday")
+	assert.False(t, w.Contains(at(time.Monday, 3, 0)), "Sunday evening is not scheduled")
+	assert.False(t, w.Contains(at(time.Wednesday, 12, 0)))
+	assert.False(t, w.Contains(at(time.Saturday, 18, 0)))
+}
+
+func TestInProcessingWindow(t *testing.T) {
+	c := GetDefaultConfig()
+	assert.True(t, c.InProcessingWindow(at(time.Wednesday, 12, 0)), "no schedule means any time")
+
+	c.ProcessingSchedule = []string{"mon-fri 18:00-08:00", "weekends"}
+	assert.False(t, c.InProcessingWindow(at(time.Wednesday, 12, 0)))
+	assert.True(t, c.InProcessingWindow(at(time.Sunday, 12, 0)))
+
+	c.ProcessingSchedule = []string{"not a schedule"}
+	assert.True(t, c.InProcessingWindow(at(time.Wednesday, 12, 0)), "an invalid schedule does not hold files back")
+}
+
+func TestNextProcessingWindow(t *testing.T) {
+	c := GetDefaultConfig()
+	_, ok := c.NextProcessingWindow(at(time.Wednesday, 12, 0))
+	assert.False(t, ok)
+
+	c.ProcessingSchedule = []string{"mon-fri 18:00-08:00", "weekends"}
+	next, ok := c.NextProcessingWindow(at(time.Wednesday, 12, 0))
+	require.True(t, ok)
+	assert.Equal(t, at(time.Wednesday, 18, 0), next)
+
+	c.ProcessingSchedule = []string{"mon 09:00-10:00"}
+	next, ok = c.NextProcessingWindow(at(time.Monday, 9, 30))
+	require.True(t, ok)
+	assert.Equal(t, at(time.Monday, 9, 0).AddDate(0, 0, 7), next, "the next start is a week later")
+}
+
+func TestParseScheduleList(t *testing.T) {
+	schedule, err := ParseScheduleList(" mon-fri 18:00-08:00 ;weekends; ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mon-fri 18:00-08:00", "weekends"}, schedule)
+
+	schedule, err = ParseScheduleList("")
+	require.NoError(t, err)
+	assert.Empty(t, schedule)
+
+	_, err = ParseScheduleList("weekends; sometimes")
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+)
+
+// How the vocabulary is given to whisper (vocabulary_mode)
+const (
+	VocabularyModePrompt   = "prompt"   // As the initial prompt, so whisper expects these spellings (all backends)
+	VocabularyModeHotwords = "hotwords" // As hotwords (whisper-ctranslate2 only; the other backends use the prompt)
+)
+
+// VocabularyModes lists the values accepted for vocabulary_mode
+var VocabularyModes = []string{VocabularyModePrompt, VocabularyModeHotwords}
+
+// VocabularyText returns the vocabulary as one string for whisper: the words
+// separated by "、" for Japanese and by ", " otherwise, in the style whisper
+// continues the prompt with
+func (c *Config) VocabularyText() string {
+	if c.Language == "ja" {
+		return strings.Join(c.Vocabulary, "、")
+	}
+	return strings.Join(c.Vocabulary, ", ")
+}
+
+// VocabularyHotwords reports whether the vocabulary is passed as hotwords
+// instead of the initial prompt
+func (c *Config) VocabularyHotwords() bool {
+	return c.VocabularyMode == VocabularyModeHotwords && len(c.Vocabulary) > 0
+}
+
+// VocabularyDisplay returns the vocabulary for the settings screens
+func (c *Config) VocabularyDisplay() string {
+	return strings.Join(c.Vocabulary, ", ")
+}
+
+// ParseVocabulary splits the words entered in the settings screens,
+// separated by commas, "、" or new lines. Empty entries and duplicates are
+// dropped.
+func ParseVocabulary(text string) []string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '、' || r == '，' || r == '\n' || r == '\r'
+	})
+	return mergeVocabulary(nil, words)
+}
+
+// mergeVocabulary returns base followed by the words of extra it does not
+// have yet, as a new slice
+func mergeVocabulary(base, extra []string) []string {
+	seen := make(map[string]bool, len(base)+len(extra))
+	var merged []string
+	for _, list := range [][]string{base, extra} {
+		for _, word := range list {
+			word = strings.TrimSpace(word)
+			if word == "" || seen[word] {
+				continue
+			}
+			seen[word] = true
+			merged = append(merged, word)
+		}
+	}
+	return merged
+}
+
+// ProfileVocabularies returns the words each profile adds to the vocabulary,
+// keyed by profile name: the profiles of the config and the .koemoji.json
+// files below the input folder. Profiles without words are left out.
+func (c *Config) ProfileVocabularies() map[string][]string {
+	vocabularies := make(map[string][]string)
+	for pattern, profile := range c.Profiles {
+		if len(profile.Vocabulary) > 0 {
+			vocabularies[profileName(profile, pattern)] = profile.Vocabulary
+		}
+	}
+	filepath.WalkDir(c.InputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != ProfileFileName {
+			return nil
+		}
+		profile, found, err := loadProfileFile(path)
+		if err != nil || !found || len(profile.Vocabulary) == 0 {
+			return nil
+		}
+		rel, err := filepath.Rel(c.InputDir, filepath.Dir(path))
+		if err != nil {
+			return nil
+		}
+		vocabularies[profileName(profile, filepath.ToSlash(rel))] = profile.Vocabulary
+		return nil
+	})
+	return vocabularies
+}
@@ -24,6 +24,10 @@ func Run() {
 	fmt.Println("\n[Configuration]")
 	checkConfiguration()
 
+	// Transcription Backend
+	fmt.Println("\n[Transcription Backend]")
+	checkTranscription()
+
 	// Summary
 	fmt.Println("\n[Summary]")
 	printSummary()
@@ -50,6 +50,14 @@ func printSummary() {
 		errors++
 	}
 
+	// Transcription backend checks
+	totalChecks++
+	if transcriptionOK {
+		passedChecks++
+	} else {
+		errors++
+	}
+
 	// Print summary
 	if passedChecks > 0 {
 		fmt.Printf("✓ %d check(s) passed\n", passedChecks)
@@ -0,0 +1,86 @@
+package diagnostics
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/whisper"
+)
+
+var (
+	transcriptionOK     = true
+	transcriptionErrors []string
+)
+
+func checkTranscription() {
+	// Use the configured backend, or the default when there is no config.json
+	cfg := config.GetDefaultConfig()
+	if _, err := os.Stat(config.GetConfigFilePath()); err == nil {
+		if loaded, err := config.LoadConfig(config.GetConfigFilePath(), nil); err == nil {
+			cfg = loaded
+		}
+	}
+
+	transcriber, err := whisper.NewTranscriber(cfg)
+	if err != nil {
+		transcriptionOK = false
+		transcriptionErrors = append(transcriptionErrors, err.Error())
+		fmt.Printf("✗ %v\n", err)
+		return
+	}
+
+	fmt.Printf("✓ Backend: %s\n", transcriber.Name())
+	caps := transcriber.Capabilities()
+	fmt.Printf("  Output formats: %s\n", strings.Join(caps.OutputFormats, ", "))
+	if caps.Local {
+		fmt.Println("  Runs locally (audio is not uploaded)")
+	} else {
+		fmt.Println("  Audio is uploaded for transcription")
+	}
+
+	if err := transcriber.Available(); err != nil {
+		transcriptionOK = false
+		transcriptionErrors = append(transcriptionErrors, err.Error())
+		fmt.Printf("✗ Not available: %v\n", err)
+		return
+	}
+	fmt.Println("✓ Available")
+	printVocabulary(cfg)
+
+	for _, format := range cfg.Formats() {
+		if !caps.SupportsFormat(format) {
+			transcriptionOK = false
+			transcriptionErrors = append(transcriptionErrors, fmt.Sprintf("output format %q not supported", format))
+			fmt.Printf("✗ Output format \"%s\" is not supported by this backend\n", format)
+		}
+	}
+}
+
+// printVocabulary lists the custom vocabulary and the words the profiles add
+func printVocabulary(cfg *config.Config) {
+	as := "initial prompt"
+	if cfg.VocabularyMode == config.VocabularyModeHotwords {
+		as = "hotwords"
+		if cfg.TranscriptionBackend != config.BackendWhisperCTranslate2 && cfg.TranscriptionBackend != "" {
+			as = "initial prompt (hotwords need whisper-ctranslate2)"
+		}
+	}
+	if len(cfg.Vocabulary) == 0 {
+		fmt.Println("  Vocabulary: none")
+	} else {
+		fmt.Printf("  Vocabulary (%s): %s\n", as, cfg.VocabularyDisplay())
+	}
+
+	profiles := cfg.ProfileVocabularies()
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("  Vocabulary of profile %s: +%s\n", name, strings.Join(profiles[name], ", "))
+	}
+}
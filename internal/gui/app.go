@@ -14,7 +14,9 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
 	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/processor"
 	"github.com/infoHiroki/KoeMoji-Go/internal/recorder"
 	"github.com/infoHiroki/KoeMoji-Go/internal/ui"
 )
@@ -25,8 +27,9 @@ type GUIApp struct {
 	*config.Config
 	configPath     string
 	debugMode      bool
-	processedFiles map[string]bool
+	jobStore       *jobs.Store
 	mu             sync.Mutex
+	wg             sync.WaitGroup // 文字起こしワーカーの終了待ち
 	logger         *log.Logger
 
 	// UI related fields
@@ -38,10 +41,11 @@ type GUIApp struct {
 	outputCount  int
 	archiveCount int
 
-	// Queue management for sequential processing
-	queuedFiles    []string // 処理待ちファイルキュー
-	processingFile string   // 現在処理中のファイル名（表示用）
-	isProcessing   bool     // 処理中フラグ
+	// Queue management for the worker pool
+	queuedFiles     []string                // 処理待ちファイルキュー
+	processingFiles []string                // 処理中のファイル（ワーカーごと）
+	isProcessing    bool                    // 処理中フラグ
+	schedule        processor.ScheduleState // 処理時間帯と「今すぐ処理」の状態
 
 	// GUI specific fields
 	fyneApp fyne.App
@@ -69,6 +73,11 @@ type GUIApp struct {
 	filesContainer  fyne.CanvasObject
 	timingContainer fyne.CanvasObject
 
+	// Transcription progress (shown while whisper reports segments)
+	progressBar       *widget.ProgressBar
+	progressLabel     *widget.Label
+	progressContainer fyne.CanvasObject
+
 	// Recording related fields
 	recorder              recorder.AudioRecorder // Interface for both Recorder and DualRecorder
 	recordingDeviceSelect *widget.SelectEntry
@@ -80,6 +89,32 @@ type GUIApp struct {
 	micVolumeRadio        *widget.RadioGroup
 	dualSettingsContainer *fyne.Container
 
+	// Processing settings UI references
+	maxConcurrentJobsEntry *widget.Entry
+	maxCpuPercentEntry     *widget.Entry
+	watchModeSelect        *widget.Select
+	fileSettleEntry        *widget.Entry
+	maxRetriesEntry        *widget.Entry
+	failedDirEntry         *widget.Entry
+	recursiveScanCheck     *widget.Check
+	duplicateActionSelect  *widget.Select
+	pauseRecordingCheck    *widget.Check
+	scheduleEntry          *widget.Entry
+	chunkMinutesEntry      *widget.Entry
+	hallucinationSelect    *widget.Select
+	vocabularyEntry        *widget.Entry
+	vocabularyModeSelect   *widget.Select
+	cleanTranscriptCheck   *widget.Check
+	summarySourceSelect    *widget.Select
+
+	// Transcription backend UI reference
+	backendSelect *widget.Select
+
+	// Output formats UI reference (multi-select)
+	outputFormatsCheck  *widget.CheckGroup
+	wordTimestampsCheck *widget.Check
+	lowConfidenceEntry  *widget.Entry
+
 	// UI safety fields
 	uiInitialized bool
 
@@ -96,7 +131,6 @@ func Run(configPath string, debugMode bool) {
 	guiApp := &GUIApp{
 		configPath:     configPath,
 		debugMode:      debugMode,
-		processedFiles: make(map[string]bool),
 		startTime:      time.Now(),
 		logBuffer:      make([]logger.LogEntry, 0, 12),
 		queuedFiles:    make([]string, 0),
@@ -120,6 +154,14 @@ func (app *GUIApp) loadConfig() {
 	// Initialize logger first (similar to main.go)
 	app.initLogger()
 
+	// Load job store (processed and interrupted files survive restarts)
+	store, err := jobs.Open(config.GetJobStorePath())
+	if err != nil {
+		msg := ui.GetMessages(config.GetDefaultConfigResolved())
+		logger.LogError(app.logger, &app.logBuffer, &app.logMutex, msg.JobStoreLoadError, err)
+	}
+	app.jobStore = store
+
 	// Load configuration
 	cfg, err := config.LoadConfig(app.configPath, app.logger) // Use logger for consistent behavior
 	if err != nil {
@@ -167,6 +209,10 @@ func (app *GUIApp) initLogger() {
 	logger.LogInfo(app.logger, &app.logBuffer, &app.logMutex, msg.AppStartedGUI)
 }
 
+// shutdownTimeout is how long ForceCleanup waits for running transcriptions
+// to be stopped and recorded for the next start
+const shutdownTimeout = 5 * time.Second
+
 // ForceCleanup performs immediate resource cleanup for application exit
 func (app *GUIApp) ForceCleanup() {
 	// Phase 2: Cancel all goroutines
@@ -174,6 +220,18 @@ func (app *GUIApp) ForceCleanup() {
 		app.cancelFunc()
 	}
 
+	// Wait for workers to kill whisper and put interrupted files back in the queue
+	done := make(chan struct{})
+	go func() {
+		app.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		logger.LogError(app.logger, &app.logBuffer, &app.logMutex, "Timed out waiting for transcriptions to stop")
+	}
+
 	// Clean up recorder resources (PortAudio)
 	if app.recorder != nil {
 		app.recorder.Close()
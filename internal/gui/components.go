@@ -42,8 +42,8 @@ func (app *GUIApp) startPeriodicUpdate() {
 
 	// Phase 2: Start file processing with context
 	go processor.StartProcessing(app.ctx, app.Config, app.logger, &app.logBuffer, &app.logMutex,
-		&app.lastScanTime, &app.queuedFiles, &app.processingFile, &app.isProcessing,
-		&app.processedFiles, &app.mu, nil, app.debugMode)
+		&app.lastScanTime, &app.queuedFiles, &app.processingFiles, &app.isProcessing,
+		&app.schedule, app.jobStore, &app.mu, &app.wg, app.debugMode)
 
 	// Start periodic updates in a goroutine with context cancellation
 	go func() {
@@ -91,6 +91,9 @@ func (app *GUIApp) updateUI() {
 	// KISS Design: Direct query, no synchronization needed
 	isCurrentlyRecording := app.isRecording()
 
+	// 録音が上限で自動停止した場合もここで再開される
+	processor.PauseForRecording(app.Config, app.logger, &app.logBuffer, &app.logMutex, isCurrentlyRecording)
+
 	msg := ui.GetMessages(app.Config)
 
 	// Update file counts
@@ -98,7 +101,10 @@ func (app *GUIApp) updateUI() {
 
 	// Update status label and icon
 	status := msg.Active
-	if app.isProcessing {
+	if app.isProcessing && whisper.Paused() {
+		status = msg.PausedForRecording
+		app.statusIcon.SetResource(theme.MediaPauseIcon()) // ⏸ 録音中のため一時停止
+	} else if app.isProcessing {
 		status = msg.Processing
 		app.statusIcon.SetResource(theme.WarningIcon())  // ⚠ 処理中
 	} else {
@@ -108,13 +114,23 @@ func (app *GUIApp) updateUI() {
 	app.mu.Lock()
 	queueCount := len(app.queuedFiles)
 	processingDisplay := msg.None
-	if app.processingFile != "" {
-		processingDisplay = app.processingFile
+	if len(app.processingFiles) > 0 {
+		processingDisplay = ui.JoinFileNamesWithProfiles(app.processingFiles, app.profileOf)
 	}
+	processing := append([]string(nil), app.processingFiles...)
+	queued := append([]string(nil), app.queuedFiles...)
 	app.mu.Unlock()
 
 	statusText := fmt.Sprintf("%s | %s: %d | %s: %s",
 		status, msg.Queue, queueCount, msg.Processing, processingDisplay)
+	if app.jobStore != nil {
+		if waiting := app.jobStore.Waiting(); len(waiting) > 0 {
+			statusText += fmt.Sprintf(" | %s(%d): %s", msg.Waiting, len(waiting), ui.JoinFileNames(waiting))
+		}
+	}
+	if next, ok := processor.NextWindow(app.Config, &app.schedule, time.Now()); ok {
+		statusText += fmt.Sprintf(" | %s: %s", msg.NextWindow, ui.FormatNextWindow(next))
+	}
 
 	// Update files label
 	filesText := fmt.Sprintf("%s: %d → %s: %d → %s: %d",
@@ -132,6 +148,11 @@ func (app *GUIApp) updateUI() {
 
 	timingText := fmt.Sprintf("%s: %s | %s: %s | %s: %s",
 		msg.Last, lastScanStr, msg.Next, nextScanStr, msg.Uptime, formatDuration(uptime))
+	if app.jobStore != nil {
+		if etaText := ui.FormatETA(processor.EstimateETA(app.Config, app.jobStore, processing, queued), msg); etaText != "" {
+			timingText += " | " + etaText
+		}
+	}
 
 	// Add recording status if recording and update icon
 	if isCurrentlyRecording {
@@ -146,6 +167,7 @@ func (app *GUIApp) updateUI() {
 	app.statusLabel.SetText(statusText)
 	app.filesLabel.SetText(filesText)
 	app.timingLabel.SetText(timingText)
+	app.updateProgress(processing, msg)
 
 	// Update log display
 	app.updateLogDisplay()
@@ -193,6 +215,33 @@ func (app *GUIApp) updateLogDisplay() {
 	app.logText.ParseMarkdown(logText)
 }
 
+// updateProgress shows the progress and the latest recognized text of the
+// first running file that whisper has reported on
+func (app *GUIApp) updateProgress(processing []string, msg *ui.Messages) {
+	for _, path := range processing {
+		p, ok := whisper.CurrentProgress(path)
+		if !ok {
+			continue
+		}
+
+		text := filepath.Base(path)
+		if percent, known := p.Percent(); known {
+			app.progressBar.SetValue(percent / 100)
+			app.progressBar.Show()
+			text += fmt.Sprintf(" %.0f%%", percent)
+		} else {
+			app.progressBar.Hide()
+		}
+		if p.LastText != "" {
+			text += fmt.Sprintf(" | %s: %s", msg.LastHeard, p.LastText)
+		}
+		app.progressLabel.SetText(text)
+		app.progressContainer.Show()
+		return
+	}
+	app.progressContainer.Hide()
+}
+
 // formatDuration formats a duration for display (copied from ui/ui.go)
 func formatDuration(d time.Duration) string {
 	hours := int(d.Hours())
@@ -232,10 +281,80 @@ func (app *GUIApp) onLogsPressed() {
 func (app *GUIApp) onScanPressed() {
 	logger.LogInfo(app.logger, &app.logBuffer, &app.logMutex, "手動スキャンを実行しました")
 
-	// Use existing sync.WaitGroup reference if available, or create minimal scan
-	processor.ScanAndProcess(app.Config, app.logger, &app.logBuffer, &app.logMutex,
-		&app.lastScanTime, &app.queuedFiles, &app.processingFile, &app.isProcessing,
-		&app.processedFiles, &app.mu, nil, app.debugMode)
+	processor.ScanAndProcess(app.ctx, app.Config, app.logger, &app.logBuffer, &app.logMutex,
+		&app.lastScanTime, &app.queuedFiles, &app.processingFiles, &app.isProcessing,
+		&app.schedule, app.jobStore, &app.mu, &app.wg, app.debugMode)
+}
+
+// onCancelJobPressed lets the user stop one of the running transcriptions
+func (app *GUIApp) onCancelJobPressed() {
+	msg := ui.GetMessages(app.Config)
+
+	app.mu.Lock()
+	files := append([]string(nil), app.processingFiles...)
+	app.mu.Unlock()
+
+	app.showFileActionDialog(msg.SelectFileToCancel, msg.CancelJobCmd, msg.NoProcessingFiles, files,
+		func(path string) error {
+			if !processor.CancelFile(app.jobStore, path) {
+				return fmt.Errorf(msg.NotProcessing, filepath.Base(path))
+			}
+			return nil
+		})
+}
+
+// onDequeuePressed lets the user take a file out of the queue before it starts
+func (app *GUIApp) onDequeuePressed() {
+	msg := ui.GetMessages(app.Config)
+
+	app.mu.Lock()
+	files := append([]string(nil), app.queuedFiles...)
+	app.mu.Unlock()
+
+	app.showFileActionDialog(msg.SelectFileToDequeue, msg.DequeueCmd, msg.NoQueuedFiles, files,
+		func(path string) error {
+			if !processor.RemoveFromQueue(app.Config, app.logger, &app.logBuffer, &app.logMutex,
+				&app.queuedFiles, app.jobStore, &app.mu, path) {
+				return fmt.Errorf(msg.NotQueued, filepath.Base(path))
+			}
+			return nil
+		})
+}
+
+// onProcessNowPressed starts the queued files even outside processing_schedule
+func (app *GUIApp) onProcessNowPressed() {
+	processor.ProcessNow(app.ctx, app.Config, app.logger, &app.logBuffer, &app.logMutex,
+		&app.queuedFiles, &app.processingFiles, &app.isProcessing,
+		&app.schedule, app.jobStore, &app.mu, &app.wg, app.debugMode)
+}
+
+// onAddRulePressed lets the user turn a misrecognized word from the log or
+// the latest transcript into a replacement rule
+func (app *GUIApp) onAddRulePressed() {
+	app.mu.Lock()
+	files := append([]string(nil), app.processingFiles...)
+	app.mu.Unlock()
+
+	var current []string
+	for _, path := range files {
+		if p, ok := whisper.CurrentProgress(path); ok {
+			current = append(current, p.LastText)
+		}
+	}
+
+	app.logMutex.RLock()
+	logs := append([]logger.LogEntry(nil), app.logBuffer...)
+	app.logMutex.RUnlock()
+
+	app.showAddRuleDialog(ui.RuleSourceLines(app.Config, current, logs))
+}
+
+// profileOf returns the processing profile recorded for path (for the status display)
+func (app *GUIApp) profileOf(path string) string {
+	if app.jobStore == nil {
+		return ""
+	}
+	return app.jobStore.Profile(path)
 }
 
 // onInputDirPressed handles the input directory button press
@@ -344,6 +463,9 @@ func (app *GUIApp) updateRecordingUI() {
 	// KISS Design: Direct query for current state
 	isCurrentlyRecording := app.isRecording()
 
+	// 録音の開始・停止に合わせて文字起こしを一時停止・再開
+	processor.PauseForRecording(app.Config, app.logger, &app.logBuffer, &app.logMutex, isCurrentlyRecording)
+
 	// Use fyne.Do to safely update UI
 	fyne.Do(func() {
 		if isCurrentlyRecording {
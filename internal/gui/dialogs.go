@@ -2,8 +2,10 @@ package gui
 
 import (
 	"fmt"
+	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"time"
 
 	"fyne.io/fyne/v2"
@@ -14,6 +16,7 @@ import (
 	"github.com/infoHiroki/KoeMoji-Go/internal/config"
 	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
 	"github.com/infoHiroki/KoeMoji-Go/internal/recorder"
+	"github.com/infoHiroki/KoeMoji-Go/internal/transcript"
 	"github.com/infoHiroki/KoeMoji-Go/internal/ui"
 )
 
@@ -105,12 +108,38 @@ func (app *GUIApp) showConfigDialog() {
 	scanIntervalEntry := widget.NewEntry()
 	scanIntervalEntry.SetText(strconv.Itoa(app.Config.ScanIntervalMinutes))
 
+	backendSelect := widget.NewSelect(config.TranscriptionBackends, nil)
+	if app.Config.TranscriptionBackend == "" {
+		backendSelect.SetSelected(config.BackendWhisperCTranslate2)
+	} else {
+		backendSelect.SetSelected(app.Config.TranscriptionBackend)
+	}
+	app.backendSelect = backendSelect
+
+	outputFormatsCheck := widget.NewCheckGroup(config.OutputFormatChoices, nil)
+	outputFormatsCheck.Horizontal = true
+	outputFormatsCheck.SetSelected(app.Config.Formats())
+	app.outputFormatsCheck = outputFormatsCheck
+
+	wordTimestampsCheck := widget.NewCheck("", nil)
+	wordTimestampsCheck.SetChecked(app.Config.WordTimestamps)
+	app.wordTimestampsCheck = wordTimestampsCheck
+
+	lowConfidenceEntry := widget.NewEntry()
+	lowConfidenceEntry.SetText(strconv.FormatFloat(app.Config.LowConfidenceThreshold, 'f', -1, 64))
+	lowConfidenceEntry.SetPlaceHolder("0.5")
+	app.lowConfidenceEntry = lowConfidenceEntry
+
 	// Basic settings form
 	basicForm := widget.NewForm(
 		widget.NewFormItem(msg.LanguageLabel, uiLanguageSelect),
 		widget.NewFormItem(msg.WhisperModelLabel, whisperModelSelect),
 		widget.NewFormItem(msg.SpeechLanguageLabel, languageSelect),
 		widget.NewFormItem(msg.ScanIntervalLabel, scanIntervalEntry),
+		widget.NewFormItem(msg.BackendLabel, backendSelect),
+		widget.NewFormItem(msg.OutputFormatsLabel, outputFormatsCheck),
+		widget.NewFormItem(msg.WordTimestampsLabel, wordTimestampsCheck),
+		widget.NewFormItem(msg.LowConfidenceLabel, lowConfidenceEntry),
 	)
 
 	// Directory settings - show relative paths for user-friendly display
@@ -141,10 +170,21 @@ func (app *GUIApp) showConfigDialog() {
 	// Use BorderContainer to give entry field priority over button
 	archiveDirContainer := container.NewBorder(nil, nil, nil, archiveDirBrowseBtn, archiveDirEntry)
 
+	failedDirEntry := widget.NewEntry()
+	failedDirEntry.SetText(config.GetRelativePath(app.Config.FailedDir))
+	failedDirBrowseBtn := widget.NewButton(msg.BrowseBtn, func() {
+		app.showFolderSelectDialog(failedDirEntry)
+	})
+	failedDirBrowseBtn.Resize(fyne.NewSize(80, 40))
+	// Use BorderContainer to give entry field priority over button
+	failedDirContainer := container.NewBorder(nil, nil, nil, failedDirBrowseBtn, failedDirEntry)
+	app.failedDirEntry = failedDirEntry
+
 	dirForm := widget.NewForm(
 		widget.NewFormItem(msg.InputDirLabel, inputDirContainer),
 		widget.NewFormItem(msg.OutputDirLabel, outputDirContainer),
 		widget.NewFormItem(msg.ArchiveDirLabel, archiveDirContainer),
+		widget.NewFormItem(msg.FailedDirLabel, failedDirContainer),
 	)
 
 	// LLM settings
@@ -173,12 +213,16 @@ func (app *GUIApp) showConfigDialog() {
 	// Recording settings
 	recordingForm := app.createRecordingForm()
 
+	// Processing settings
+	processingForm := app.createProcessingForm()
+
 	// Create tabs
 	tabs := container.NewAppTabs(
 		container.NewTabItem(msg.BasicTab, basicForm),
 		container.NewTabItem(msg.DirectoriesTab, dirForm),
 		container.NewTabItem(msg.LLMTab, llmForm),
 		container.NewTabItem(msg.RecordingTab, recordingForm),
+		container.NewTabItem(msg.ProcessingTab, processingForm),
 	)
 
 	// Create dialog content
@@ -400,6 +444,118 @@ func (app *GUIApp) createRecordingForm() *widget.Form {
 	return widget.NewForm(formItems...)
 }
 
+// createProcessingForm creates the processing (queue/worker) settings form
+func (app *GUIApp) createProcessingForm() *widget.Form {
+	msg := ui.GetMessages(app.Config)
+
+	maxJobsEntry := widget.NewEntry()
+	maxJobsEntry.SetText(strconv.Itoa(app.Config.MaxConcurrentJobs))
+	app.maxConcurrentJobsEntry = maxJobsEntry
+
+	maxCpuEntry := widget.NewEntry()
+	maxCpuEntry.SetText(strconv.Itoa(app.Config.MaxCpuPercent))
+	app.maxCpuPercentEntry = maxCpuEntry
+
+	watchModeSelect := widget.NewSelect([]string{msg.WatchModeWatchOption, msg.WatchModePollOption}, nil)
+	if app.Config.WatchMode == config.WatchModePoll {
+		watchModeSelect.SetSelected(msg.WatchModePollOption)
+	} else {
+		watchModeSelect.SetSelected(msg.WatchModeWatchOption)
+	}
+	app.watchModeSelect = watchModeSelect
+
+	fileSettleEntry := widget.NewEntry()
+	fileSettleEntry.SetText(strconv.Itoa(app.Config.FileSettleSeconds))
+	app.fileSettleEntry = fileSettleEntry
+
+	maxRetriesEntry := widget.NewEntry()
+	maxRetriesEntry.SetText(strconv.Itoa(app.Config.MaxRetries))
+	app.maxRetriesEntry = maxRetriesEntry
+
+	recursiveScanCheck := widget.NewCheck("", nil)
+	recursiveScanCheck.SetChecked(app.Config.RecursiveScan)
+	app.recursiveScanCheck = recursiveScanCheck
+
+	duplicateActionSelect := widget.NewSelect([]string{msg.DuplicateReuseOption, msg.DuplicateSkipOption, msg.DuplicateProcessOption}, nil)
+	switch app.Config.DuplicateAction {
+	case config.DuplicateActionSkip:
+		duplicateActionSelect.SetSelectedIndex(1)
+	case config.DuplicateActionProcess:
+		duplicateActionSelect.SetSelectedIndex(2)
+	default:
+		duplicateActionSelect.SetSelectedIndex(0)
+	}
+	app.duplicateActionSelect = duplicateActionSelect
+
+	pauseRecordingCheck := widget.NewCheck("", nil)
+	pauseRecordingCheck.SetChecked(app.Config.PauseWhileRecording)
+	app.pauseRecordingCheck = pauseRecordingCheck
+
+	scheduleEntry := widget.NewEntry()
+	scheduleEntry.SetText(strings.Join(app.Config.ProcessingSchedule, "; "))
+	scheduleEntry.SetPlaceHolder("mon-fri 18:00-08:00; weekends")
+	app.scheduleEntry = scheduleEntry
+
+	chunkMinutesEntry := widget.NewEntry()
+	chunkMinutesEntry.SetText(strconv.Itoa(app.Config.ChunkMinutes))
+	app.chunkMinutesEntry = chunkMinutesEntry
+
+	hallucinationSelect := widget.NewSelect([]string{msg.HallucinationDrop, msg.HallucinationFlag, msg.HallucinationOff}, nil)
+	switch app.Config.HallucinationFilter {
+	case config.HallucinationFilterFlag:
+		hallucinationSelect.SetSelectedIndex(1)
+	case config.HallucinationFilterOff:
+		hallucinationSelect.SetSelectedIndex(2)
+	default:
+		hallucinationSelect.SetSelectedIndex(0)
+	}
+	app.hallucinationSelect = hallucinationSelect
+
+	vocabularyEntry := widget.NewMultiLineEntry()
+	vocabularyEntry.SetText(strings.Join(app.Config.Vocabulary, "\n"))
+	vocabularyEntry.SetPlaceHolder("KoeMoji\n山田太郎")
+	vocabularyEntry.SetMinRowsVisible(3)
+	app.vocabularyEntry = vocabularyEntry
+
+	vocabularyModeSelect := widget.NewSelect([]string{msg.VocabPromptOption, msg.VocabHotwordsOption}, nil)
+	if app.Config.VocabularyMode == config.VocabularyModeHotwords {
+		vocabularyModeSelect.SetSelectedIndex(1)
+	} else {
+		vocabularyModeSelect.SetSelectedIndex(0)
+	}
+	app.vocabularyModeSelect = vocabularyModeSelect
+
+	cleanTranscriptCheck := widget.NewCheck("", nil)
+	cleanTranscriptCheck.SetChecked(app.Config.CleanTranscript)
+	app.cleanTranscriptCheck = cleanTranscriptCheck
+
+	summarySourceSelect := widget.NewSelect([]string{msg.SummaryCleanOption, msg.SummaryVerbatimOption}, nil)
+	if app.Config.SummarySource == config.SummarySourceVerbatim {
+		summarySourceSelect.SetSelectedIndex(1)
+	} else {
+		summarySourceSelect.SetSelectedIndex(0)
+	}
+	app.summarySourceSelect = summarySourceSelect
+
+	return widget.NewForm(
+		widget.NewFormItem(msg.MaxConcurrentJobsLabel, maxJobsEntry),
+		widget.NewFormItem(msg.MaxCPUPercentLabel, maxCpuEntry),
+		widget.NewFormItem(msg.WatchModeLabel, watchModeSelect),
+		widget.NewFormItem(msg.FileSettleLabel, fileSettleEntry),
+		widget.NewFormItem(msg.MaxRetriesLabel, maxRetriesEntry),
+		widget.NewFormItem(msg.RecursiveScanLabel, recursiveScanCheck),
+		widget.NewFormItem(msg.DuplicateActionLabel, duplicateActionSelect),
+		widget.NewFormItem(msg.PauseRecordingLabel, pauseRecordingCheck),
+		widget.NewFormItem(msg.ScheduleLabel, scheduleEntry),
+		widget.NewFormItem(msg.ChunkMinutesLabel, chunkMinutesEntry),
+		widget.NewFormItem(msg.HallucinationLabel, hallucinationSelect),
+		widget.NewFormItem(msg.VocabularyLabel, vocabularyEntry),
+		widget.NewFormItem(msg.VocabularyModeLabel, vocabularyModeSelect),
+		widget.NewFormItem(msg.CleanTranscriptLabel, cleanTranscriptCheck),
+		widget.NewFormItem(msg.SummarySourceLabel, summarySourceSelect),
+	)
+}
+
 // saveConfigFromDialog saves the configuration from dialog form entries
 func (app *GUIApp) saveConfigFromDialog(whisperModel, language *widget.Select,
 	uiLanguage *widget.Select, scanInterval *widget.Entry,
@@ -449,11 +605,40 @@ func (app *GUIApp) saveConfigFromDialog(whisperModel, language *widget.Select,
 	if interval, err := strconv.Atoi(scanInterval.Text); err == nil {
 		app.Config.ScanIntervalMinutes = interval
 	}
+	if app.backendSelect != nil && app.backendSelect.Selected != "" {
+		app.Config.TranscriptionBackend = app.backendSelect.Selected
+	}
+	if app.outputFormatsCheck != nil && len(app.outputFormatsCheck.Selected) > 0 {
+		// Keep the order of OutputFormatChoices, not the order of clicking
+		var formats []string
+		for _, format := range config.OutputFormatChoices {
+			for _, selected := range app.outputFormatsCheck.Selected {
+				if format == selected {
+					formats = append(formats, format)
+				}
+			}
+		}
+		app.Config.SetFormats(formats)
+	}
+	if app.wordTimestampsCheck != nil {
+		app.Config.WordTimestamps = app.wordTimestampsCheck.Checked
+	}
+	if app.lowConfidenceEntry != nil {
+		if threshold, err := strconv.ParseFloat(app.lowConfidenceEntry.Text, 64); err == nil && threshold >= 0 && threshold < 1 {
+			app.Config.LowConfidenceThreshold = threshold
+		}
+	}
 
 	// Resolve relative paths to absolute paths for internal storage
 	app.Config.InputDir = config.ResolvePath(inputDir.Text)
 	app.Config.OutputDir = config.ResolvePath(outputDir.Text)
 	app.Config.ArchiveDir = config.ResolvePath(archiveDir.Text)
+	if app.failedDirEntry != nil {
+		app.Config.FailedDir = ""
+		if failedDir := strings.TrimSpace(app.failedDirEntry.Text); failedDir != "" {
+			app.Config.FailedDir = config.ResolvePath(failedDir)
+		}
+	}
 	app.Config.LLMSummaryEnabled = llmEnabled.Checked
 	app.Config.LLMAPIKey = llmAPIKey.Text
 	app.Config.LLMModel = llmModel.Selected
@@ -506,6 +691,80 @@ func (app *GUIApp) saveConfigFromDialog(whisperModel, language *widget.Select,
 		}
 	}
 
+	// Update processing settings
+	if app.maxConcurrentJobsEntry != nil {
+		if jobs, err := strconv.Atoi(app.maxConcurrentJobsEntry.Text); err == nil && jobs >= 1 {
+			app.Config.MaxConcurrentJobs = jobs
+		}
+	}
+	if app.maxCpuPercentEntry != nil {
+		if percent, err := strconv.Atoi(app.maxCpuPercentEntry.Text); err == nil && percent >= 1 && percent <= 100 {
+			app.Config.MaxCpuPercent = percent
+		}
+	}
+	if app.watchModeSelect != nil {
+		// Compare by index: the option labels follow the UI language, which may have changed above
+		if app.watchModeSelect.SelectedIndex() == 1 {
+			app.Config.WatchMode = config.WatchModePoll
+		} else {
+			app.Config.WatchMode = config.WatchModeWatch
+		}
+	}
+	if app.fileSettleEntry != nil {
+		if seconds, err := strconv.Atoi(app.fileSettleEntry.Text); err == nil && seconds >= 0 && seconds <= 600 {
+			app.Config.FileSettleSeconds = seconds
+		}
+	}
+	if app.maxRetriesEntry != nil {
+		if retries, err := strconv.Atoi(app.maxRetriesEntry.Text); err == nil && retries >= 0 && retries <= 10 {
+			app.Config.MaxRetries = retries
+		}
+	}
+	if app.recursiveScanCheck != nil {
+		app.Config.RecursiveScan = app.recursiveScanCheck.Checked
+	}
+	if app.duplicateActionSelect != nil {
+		actions := []string{config.DuplicateActionReuse, config.DuplicateActionSkip, config.DuplicateActionProcess}
+		if idx := app.duplicateActionSelect.SelectedIndex(); idx >= 0 && idx < len(actions) {
+			app.Config.DuplicateAction = actions[idx]
+		}
+	}
+	if app.hallucinationSelect != nil {
+		modes := []string{config.HallucinationFilterDrop, config.HallucinationFilterFlag, config.HallucinationFilterOff}
+		if idx := app.hallucinationSelect.SelectedIndex(); idx >= 0 && idx < len(modes) {
+			app.Config.HallucinationFilter = modes[idx]
+		}
+	}
+	if app.vocabularyEntry != nil {
+		app.Config.Vocabulary = config.ParseVocabulary(app.vocabularyEntry.Text)
+	}
+	if app.vocabularyModeSelect != nil {
+		if idx := app.vocabularyModeSelect.SelectedIndex(); idx >= 0 && idx < len(config.VocabularyModes) {
+			app.Config.VocabularyMode = config.VocabularyModes[idx]
+		}
+	}
+	if app.cleanTranscriptCheck != nil {
+		app.Config.CleanTranscript = app.cleanTranscriptCheck.Checked
+	}
+	if app.summarySourceSelect != nil {
+		if idx := app.summarySourceSelect.SelectedIndex(); idx >= 0 && idx < len(config.SummarySources) {
+			app.Config.SummarySource = config.SummarySources[idx]
+		}
+	}
+	if app.pauseRecordingCheck != nil {
+		app.Config.PauseWhileRecording = app.pauseRecordingCheck.Checked
+	}
+	if app.scheduleEntry != nil {
+		if schedule, err := config.ParseScheduleList(app.scheduleEntry.Text); err == nil {
+			app.Config.ProcessingSchedule = schedule
+		}
+	}
+	if app.chunkMinutesEntry != nil {
+		if minutes, err := strconv.Atoi(app.chunkMinutesEntry.Text); err == nil && minutes >= 0 && minutes <= 240 {
+			app.Config.ChunkMinutes = minutes
+		}
+	}
+
 	// Save to file
 	msg := ui.GetMessages(app.Config)
 	if err := config.SaveConfig(app.Config, app.configPath); err != nil {
@@ -545,13 +804,85 @@ func (app *GUIApp) showRecordingExitWarning() {
 
 // forceQuit performs immediate application exit with cleanup
 func (app *GUIApp) forceQuit() {
-	// Perform cleanup
+	// Perform cleanup (stops whisper and requeues the interrupted files)
 	app.ForceCleanup()
 
-	// Immediate exit - OS will handle whisper process termination
 	app.fyneApp.Quit()
 }
 
+// showFileActionDialog lets the user pick one of files and runs action on it.
+// Used for stopping a running transcription and removing a file from the queue.
+func (app *GUIApp) showFileActionDialog(title, actionLabel, emptyMessage string, files []string,
+	action func(path string) error) {
+
+	if len(files) == 0 {
+		dialog.ShowInformation(title, emptyMessage, app.window)
+		return
+	}
+
+	msg := ui.GetMessages(app.Config)
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = filepath.Base(file)
+	}
+	fileSelect := widget.NewSelect(names, nil)
+	fileSelect.SetSelectedIndex(0)
+
+	confirmDialog := dialog.NewCustomConfirm(title, actionLabel, msg.CancelBtn, fileSelect, func(confirmed bool) {
+		if !confirmed || fileSelect.SelectedIndex() < 0 {
+			return
+		}
+		if err := action(files[fileSelect.SelectedIndex()]); err != nil {
+			dialog.ShowError(err, app.window)
+			return
+		}
+		app.updateUI()
+	}, app.window)
+	confirmDialog.Show()
+}
+
+// showAddRuleDialog edits a new replacement rule, starting from one of lines
+// when the user picks it, and adds it to the rules file
+func (app *GUIApp) showAddRuleDialog(lines []string) {
+	msg := ui.GetMessages(app.Config)
+
+	fromEntry := widget.NewEntry()
+	toEntry := widget.NewEntry()
+	sourceSelect := widget.NewSelect(lines, func(line string) {
+		fromEntry.SetText(line)
+	})
+	regexCheck := widget.NewCheck(msg.RuleRegexOption, nil)
+	ignoreCaseCheck := widget.NewCheck(msg.RuleIgnoreCaseOption, nil)
+	wholeWordCheck := widget.NewCheck(msg.RuleWholeWordOption, nil)
+
+	form := widget.NewForm(
+		widget.NewFormItem(msg.RuleSourceLabel, sourceSelect),
+		widget.NewFormItem(msg.RuleFromLabel, fromEntry),
+		widget.NewFormItem(msg.RuleToLabel, toEntry),
+	)
+	content := container.NewVBox(form, regexCheck, ignoreCaseCheck, wholeWordCheck)
+
+	ruleDialog := dialog.NewCustomConfirm(msg.AddRuleTitle, msg.SaveBtn, msg.CancelBtn, content, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		rule := transcript.Rule{
+			From:       fromEntry.Text,
+			To:         toEntry.Text,
+			Regex:      regexCheck.Checked,
+			IgnoreCase: ignoreCaseCheck.Checked,
+			WholeWord:  wholeWordCheck.Checked,
+		}
+		if err := transcript.AddRule(app.Config.ReplacementRulesFile, rule); err != nil {
+			dialog.ShowError(err, app.window)
+			return
+		}
+		dialog.ShowInformation(msg.Success, fmt.Sprintf(msg.RuleAdded, filepath.Base(app.Config.ReplacementRulesFile)), app.window)
+	}, app.window)
+	ruleDialog.Resize(fyne.NewSize(600, 300))
+	ruleDialog.Show()
+}
+
 // formatRecordingDuration formats a duration for display in recording dialog
 func formatRecordingDuration(d time.Duration) string {
 	hours := int(d.Hours())
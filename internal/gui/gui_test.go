@@ -12,6 +12,7 @@ import (
 
 	"fyne.io/fyne/v2/test"
 	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
 	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -39,7 +40,7 @@ func createTestGUIApp(t *testing.T) *GUIApp {
 		Config:            cfg,
 		configPath:        filepath.Join(tempDir, "config.json"),
 		debugMode:         false,
-		processedFiles:    make(map[string]bool),
+		jobStore:          jobs.NewMemoryStore(),
 		mu:                sync.Mutex{},
 		logger:            log.New(os.Stdout, "", log.LstdFlags),
 		startTime:         time.Now(),
@@ -64,7 +65,7 @@ func TestGUIApp_Creation(t *testing.T) {
 	assert.False(t, app.debugMode)
 	assert.False(t, app.isProcessing)
 	assert.Empty(t, app.queuedFiles)
-	assert.Empty(t, app.processingFile)
+	assert.Empty(t, app.processingFiles)
 }
 
 func TestGUIApp_FyneAppInitialization(t *testing.T) {
@@ -150,7 +151,7 @@ func TestGUIApp_ProcessingState(t *testing.T) {
 	
 	// Test processing state management
 	assert.False(t, app.isProcessing)
-	assert.Empty(t, app.processingFile)
+	assert.Empty(t, app.processingFiles)
 	assert.Empty(t, app.queuedFiles)
 	
 	// Simulate adding files to queue
@@ -167,16 +168,16 @@ func TestGUIApp_ProcessingState(t *testing.T) {
 	// Simulate processing
 	app.mu.Lock()
 	app.isProcessing = true
-	app.processingFile = "test1.wav"
+	app.processingFiles = append(app.processingFiles, "test1.wav", "test2.wav")
 	app.mu.Unlock()
 	
 	app.mu.Lock()
 	processing := app.isProcessing
-	currentFile := app.processingFile
+	currentFiles := append([]string(nil), app.processingFiles...)
 	app.mu.Unlock()
 	
 	assert.True(t, processing)
-	assert.Equal(t, "test1.wav", currentFile)
+	assert.Equal(t, []string{"test1.wav", "test2.wav"}, currentFiles)
 }
 
 func TestGUIApp_ConfigurationAccess(t *testing.T) {
@@ -107,11 +107,19 @@ func (app *GUIApp) createStatusPanel(msg *ui.Messages) fyne.CanvasObject {
 	app.timingLabel = widget.NewLabel(msg.Last + ": " + msg.Never + " | " + msg.Next + ": " + msg.Soon + " | " + msg.Uptime + ": 0s")
 	app.timingContainer = container.NewHBox(app.timingIcon, app.timingLabel)
 
+	// Status line 4: Transcription progress and the latest recognized text
+	app.progressBar = widget.NewProgressBar()
+	app.progressLabel = widget.NewLabel("")
+	app.progressLabel.Truncation = fyne.TextTruncateEllipsis
+	app.progressContainer = container.NewVBox(app.progressBar, app.progressLabel)
+	app.progressContainer.Hide()
+
 	// Create a card container for the status panel
 	statusCard := widget.NewCard("", "", container.NewVBox(
 		app.statusContainer,
 		app.filesContainer,
 		app.timingContainer,
+		app.progressContainer,
 	))
 
 	return statusCard
@@ -150,6 +158,11 @@ func (app *GUIApp) createButtonPanel(msg *ui.Messages) fyne.CanvasObject {
 	})
 	logsBtn.Resize(buttonSize)
 
+	ruleBtn := widget.NewButton(msg.RuleCmd, func() {
+		app.onAddRulePressed()
+	})
+	ruleBtn.Resize(buttonSize)
+
 	scanBtn := widget.NewButton(msg.ScanCmd, func() {
 		app.onScanPressed()
 	})
@@ -176,6 +189,21 @@ func (app *GUIApp) createButtonPanel(msg *ui.Messages) fyne.CanvasObject {
 	})
 	outputBtn.Resize(buttonSize)
 
+	cancelJobBtn := widget.NewButton(msg.CancelJobCmd, func() {
+		app.onCancelJobPressed()
+	})
+	cancelJobBtn.Resize(buttonSize)
+
+	dequeueBtn := widget.NewButton(msg.DequeueCmd, func() {
+		app.onDequeuePressed()
+	})
+	dequeueBtn.Resize(buttonSize)
+
+	processNowBtn := widget.NewButton(msg.ProcessCmd, func() {
+		app.onProcessNowPressed()
+	})
+	processNowBtn.Resize(buttonSize)
+
 	quitBtn := widget.NewButton(msg.QuitCmd, func() {
 		app.onQuitPressed()
 	})
@@ -188,9 +216,16 @@ func (app *GUIApp) createButtonPanel(msg *ui.Messages) fyne.CanvasObject {
 		recordBtn,
 	)
 
+	jobButtons := container.NewHBox(
+		cancelJobBtn,
+		dequeueBtn,
+		processNowBtn,
+	)
+
 	configButtons := container.NewHBox(
 		configBtn,
 		logsBtn,
+		ruleBtn,
 	)
 
 	directoryButtons := container.NewHBox(
@@ -203,6 +238,8 @@ func (app *GUIApp) createButtonPanel(msg *ui.Messages) fyne.CanvasObject {
 		layout.NewSpacer(),
 		primaryButtons,
 		widget.NewLabel("   "), // Fixed spacing between groups
+		jobButtons,
+		widget.NewLabel("   "), // Fixed spacing between groups
 		configButtons,
 		widget.NewLabel("   "), // Fixed spacing between groups
 		directoryButtons,
@@ -0,0 +1,146 @@
+package jobs
+
+import (
+	"time"
+)
+
+// RTFStat accumulates the real-time factor (processing time per second of
+// audio) of the finished transcriptions for one model/compute_type
+type RTFStat struct {
+	Jobs              int     `json:"jobs"`
+	AudioSeconds      float64 `json:"audio_seconds"`
+	ProcessingSeconds float64 `json:"processing_seconds"`
+}
+
+// Factor returns the processing time per second of audio (zero without data)
+func (r RTFStat) Factor() float64 {
+	if r.AudioSeconds <= 0 {
+		return 0
+	}
+	return r.ProcessingSeconds / r.AudioSeconds
+}
+
+// RTFKey returns the statistics key for a whisper model and compute type
+func RTFKey(model, computeType string) string {
+	return model + "/" + computeType
+}
+
+// ETA is the estimated remaining transcription time of the running files
+// and of everything queued. The Known flags are false until every file
+// involved has an audio duration and its model has finished a job before.
+type ETA struct {
+	Current      time.Duration // Until the running files are transcribed
+	Total        time.Duration // Until the running and queued files are transcribed
+	CurrentKnown bool
+	TotalKnown   bool
+	Running      int // Files covered by Current
+	Queued       int // Queued files, also covered by Total
+}
+
+// SetAudio records the playing time of path and the statistics key of the
+// model it will be transcribed with
+func (s *Store) SetAudio(path string, audio time.Duration, rtfKey string) error {
+	return s.update(path, func(job *Job) {
+		job.AudioSeconds = audio.Seconds()
+		job.RTFKey = rtfKey
+	})
+}
+
+// RecordRTF adds a finished transcription to the statistics of rtfKey
+func (s *Store) RecordRTF(rtfKey string, audio, processing time.Duration) error {
+	if audio <= 0 || processing <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stat, ok := s.rtf[rtfKey]
+	if !ok {
+		stat = &RTFStat{}
+		s.rtf[rtfKey] = stat
+	}
+	stat.Jobs++
+	stat.AudioSeconds += audio.Seconds()
+	stat.ProcessingSeconds += processing.Seconds()
+	return s.saveLocked()
+}
+
+// RTF returns the statistics recorded for rtfKey
+func (s *Store) RTF(rtfKey string) (RTFStat, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stat, ok := s.rtf[rtfKey]
+	if !ok || stat.Factor() == 0 {
+		return RTFStat{}, false
+	}
+	return *stat, true
+}
+
+// EstimateETA estimates the remaining time of the processing and queued
+// files when workers files are transcribed in parallel
+func (s *Store) EstimateETA(processing, queued []string, workers int, now time.Time) ETA {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	eta := ETA{
+		CurrentKnown: len(processing) > 0,
+		TotalKnown:   len(processing)+len(queued) > 0,
+		Running:      len(processing),
+		Queued:       len(queued),
+	}
+	var sum time.Duration
+	for _, path := range processing {
+		estimate, ok := s.estimateLocked(path)
+		if !ok {
+			eta.CurrentKnown, eta.TotalKnown = false, false
+			continue
+		}
+		remaining := estimate
+		if job := s.jobs[path]; !job.StartedAt.IsZero() {
+			remaining -= now.Sub(job.StartedAt)
+		}
+		if remaining < 0 {
+			remaining = 0 // Taking longer than usual
+		}
+		if remaining > eta.Current {
+			eta.Current = remaining
+		}
+		sum += remaining
+	}
+	for _, path := range queued {
+		estimate, ok := s.estimateLocked(path)
+		if !ok {
+			eta.TotalKnown = false
+			continue
+		}
+		sum += estimate
+	}
+
+	// The pool works on several files at once, but never finishes before
+	// the longest running file
+	eta.Total = sum / time.Duration(workers)
+	if eta.Total < eta.Current {
+		eta.Total = eta.Current
+	}
+	return eta
+}
+
+// estimateLocked returns the expected transcription time of path.
+// Caller must hold s.mu.
+func (s *Store) estimateLocked(path string) (time.Duration, bool) {
+	job, ok := s.jobs[path]
+	if !ok || job.AudioSeconds <= 0 {
+		return 0, false
+	}
+	stat, ok := s.rtf[job.RTFKey]
+	if !ok || stat.Factor() == 0 {
+		return 0, false
+	}
+	return time.Duration(job.AudioSeconds * stat.Factor() * float64(time.Second)), true
+}
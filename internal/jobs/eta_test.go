@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// claimWithAudio claims a new input file with the given playing time
+func claimWithAudio(t *testing.T, store *Store, path string, audio time.Duration, rtfKey string) {
+	writeFile(t, path, filepath.Base(path))
+	claimed, err := store.Claim(path)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.NoError(t, store.SetAudio(path, audio, rtfKey))
+}
+
+func TestRecordRTF_PersistsAcrossOpen(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "jobs.json")
+	store, err := Open(storePath)
+	require.NoError(t, err)
+
+	key := RTFKey("large-v3", "int8")
+	require.NoError(t, store.RecordRTF(key, 10*time.Minute, 5*time.Minute))
+	require.NoError(t, store.RecordRTF(key, 20*time.Minute, 4*time.Minute))
+	require.NoError(t, store.RecordRTF(key, 0, time.Minute), "jobs without a duration are ignored")
+
+	reopened, err := Open(storePath)
+	require.NoError(t, err)
+	stat, ok := reopened.RTF(key)
+	require.True(t, ok)
+	assert.Equal(t, 2, stat.Jobs)
+	assert.InDelta(t, 0.3, stat.Factor(), 0.0001)
+
+	_, ok = reopened.RTF(RTFKey("medium", "int8"))
+	assert.False(t, ok)
+}
+
+func TestEstimateETA(t *testing.T) {
+	dir := t.TempDir()
+	store := NewMemoryStore()
+	key := RTFKey("large-v3", "int8")
+	require.NoError(t, store.RecordRTF(key, 10*time.Minute, 5*time.Minute)) // 0.5
+
+	running := filepath.Join(dir, "running.wav")
+	first := filepath.Join(dir, "first.wav")
+	second := filepath.Join(dir, "second.wav")
+	claimWithAudio(t, store, running, 10*time.Minute, key)
+	claimWithAudio(t, store, first, 20*time.Minute, key)
+	claimWithAudio(t, store, second, 4*time.Minute, key)
+	require.NoError(t, store.MarkProcessing(running))
+	job, _ := store.Get(running)
+	now := job.StartedAt.Add(2 * time.Minute)
+
+	eta := store.EstimateETA([]string{running}, []string{first, second}, 1, now)
+	assert.True(t, eta.CurrentKnown)
+	assert.True(t, eta.TotalKnown)
+	assert.Equal(t, 3*time.Minute, eta.Current)
+	assert.Equal(t, 3*time.Minute+10*time.Minute+2*time.Minute, eta.Total)
+	assert.Equal(t, 1, eta.Running)
+	assert.Equal(t, 2, eta.Queued)
+
+	eta = store.EstimateETA([]string{running}, []string{first, second}, 4, now)
+	assert.Equal(t, 15*time.Minute/4, eta.Total, "parallel workers share the queue")
+
+	eta = store.EstimateETA([]string{running}, nil, 4, now)
+	assert.Equal(t, 3*time.Minute, eta.Total, "never earlier than the running file")
+}
+
+func TestEstimateETA_UnknownWithoutHistory(t *testing.T) {
+	dir := t.TempDir()
+	store := NewMemoryStore()
+	require.NoError(t, store.RecordRTF(RTFKey("large-v3", "int8"), time.Minute, time.Minute))
+
+	known := filepath.Join(dir, "known.wav")
+	newModel := filepath.Join(dir, "new-model.wav")
+	claimWithAudio(t, store, known, time.Minute, RTFKey("large-v3", "int8"))
+	claimWithAudio(t, store, newModel, time.Minute, RTFKey("medium", "int8"))
+
+	eta := store.EstimateETA(nil, []string{known, newModel}, 1, time.Now())
+	assert.False(t, eta.CurrentKnown)
+	assert.False(t, eta.TotalKnown)
+
+	assert.Equal(t, ETA{}, store.EstimateETA(nil, nil, 1, time.Now()))
+}
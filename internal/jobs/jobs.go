@@ -0,0 +1,670 @@
+package jobs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/transcript"
+)
+
+// Status represents the lifecycle state of a job
+type Status string
+
+const (
+	StatusQueued     Status = "queued"
+	StatusProcessing Status = "processing"
+	StatusDone       Status = "done"
+	StatusFailed     Status = "failed"
+	// StatusCancelled is set when the user cancels a running job or removes it
+	// from the queue. Like failed jobs it is only picked up again when the
+	// file changes.
+	StatusCancelled Status = "cancelled"
+)
+
+// Done jobs are compacted once there are more than maxDoneJobs of them,
+// keeping the keepDoneJobs most recently finished
+const (
+	maxDoneJobs  = 5000
+	keepDoneJobs = 2500
+)
+
+// storeVersion is bumped when the on-disk format changes incompatibly
+const storeVersion = 1
+
+// Job is the persisted record of one input file
+type Job struct {
+	Path       string    `json:"path"`
+	Hash       string    `json:"hash"`
+	Size       int64     `json:"size"`
+	ModTime    time.Time `json:"mod_time"`
+	Status     Status    `json:"status"`
+	Attempts   int       `json:"attempts"`
+	QueuedAt   time.Time `json:"queued_at"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Error      string    `json:"error,omitempty"`
+	// RetryAt is set while a failed job waits for its next attempt
+	RetryAt time.Time `json:"retry_at"`
+	// MovedTo is where the file was moved after its last attempt failed
+	MovedTo string `json:"moved_to,omitempty"`
+	// Profile names the processing profiles applied to the last attempt
+	Profile string `json:"profile,omitempty"`
+	// Outputs lists the transcript and summary files written for a done job
+	Outputs []string `json:"outputs,omitempty"`
+	// DuplicateOf is the earlier job with the same content when this one was
+	// not transcribed itself
+	DuplicateOf string `json:"duplicate_of,omitempty"`
+	// AudioSeconds is the playing time of the file (zero when unknown)
+	AudioSeconds float64 `json:"audio_seconds,omitempty"`
+	// RTFKey selects the real-time factor statistics used for its estimate
+	RTFKey string `json:"rtf_key,omitempty"`
+}
+
+// Duration returns how long the last attempt took (zero while unfinished)
+func (j Job) Duration() time.Duration {
+	if j.StartedAt.IsZero() || j.FinishedAt.IsZero() {
+		return 0
+	}
+	return j.FinishedAt.Sub(j.StartedAt)
+}
+
+type storeFile struct {
+	Version int                 `json:"version"`
+	Jobs    map[string]*Job     `json:"jobs"`
+	RTF     map[string]*RTFStat `json:"rtf,omitempty"`
+}
+
+// Store keeps track of every file the processor has seen.
+// State is written to disk on every change so a restart does not
+// re-transcribe finished files or forget interrupted ones.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	jobs map[string]*Job
+	// active marks jobs that were claimed during this session.
+	// Unfinished or failed jobs loaded from disk are not active until
+	// the next scan picks them up again.
+	active map[string]bool
+	// waiting holds new or changed files that are not queued yet because
+	// they may still be written to. Kept in memory only.
+	waiting map[string]*sighting
+	// running holds the cancel functions of jobs being transcribed right now
+	running map[string]context.CancelFunc
+	// inflight maps the content hash of each job a worker is on to its path
+	inflight map[string]string
+	// held lists the jobs waiting for the inflight job with the same hash
+	held map[string][]string
+	// rtf holds the real-time factor statistics per model/compute_type (see eta.go)
+	rtf map[string]*RTFStat
+}
+
+// sighting is the last observed state of a file that has not settled yet
+type sighting struct {
+	size    int64
+	modTime time.Time
+	since   time.Time // when this size/mtime was first observed
+}
+
+// Open loads the store from path, creating an empty store if the file does not exist.
+// An empty path gives an in-memory store that is never persisted.
+// On error a usable empty store is still returned so callers can log and continue.
+func Open(path string) (*Store, error) {
+	s := NewMemoryStore()
+	s.path = path
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to read job store: %w", err)
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		// Keep the broken file for inspection and start over
+		os.Rename(path, path+".corrupt")
+		return s, fmt.Errorf("failed to parse job store %s: %w", path, err)
+	}
+	if file.Version > storeVersion {
+		return s, fmt.Errorf("job store %s has unsupported version %d", path, file.Version)
+	}
+	for p, job := range file.Jobs {
+		if job != nil {
+			job.Path = p
+			s.jobs[p] = job
+		}
+	}
+	for key, stat := range file.RTF {
+		if stat != nil {
+			s.rtf[key] = stat
+		}
+	}
+	return s, nil
+}
+
+// NewMemoryStore returns a store without a backing file
+func NewMemoryStore() *Store {
+	return &Store{
+		jobs:     make(map[string]*Job),
+		active:   make(map[string]bool),
+		waiting:  make(map[string]*sighting),
+		running:  make(map[string]context.CancelFunc),
+		inflight: make(map[string]string),
+		held:     make(map[string][]string),
+		rtf:      make(map[string]*RTFStat),
+	}
+}
+
+// Path returns the backing file path (empty for in-memory stores)
+func (s *Store) Path() string {
+	return s.path
+}
+
+// TranscriptDir returns where the segments of transcribed files are kept,
+// next to the backing file (empty for in-memory stores)
+func (s *Store) TranscriptDir() string {
+	if s.path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(s.path), "transcripts")
+}
+
+// Claim decides whether path should be queued. New files, files whose
+// size or mtime changed since they were last seen, jobs a previous run left
+// queued or processing, and failed files moved back into place by the user
+// are claimed and marked as queued. Failed and cancelled files left in
+// place are not retried until they change, e.g. when the user saves them again.
+func (s *Store) Claim(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	job, exists := s.jobs[path]
+	if exists && job.Size == info.Size() && job.ModTime.Equal(info.ModTime()) {
+		interrupted := job.Status == StatusQueued || job.Status == StatusProcessing
+		recovered := interrupted && !s.active[path]
+		// A file that was moved out after failing and shows up again is a manual retry
+		returned := job.Status == StatusFailed && job.MovedTo != ""
+		if !recovered && !returned {
+			s.mu.Unlock()
+			return false, nil
+		}
+		if returned {
+			job.Attempts = 0
+			job.Error = ""
+			job.MovedTo = ""
+		}
+		job.Status = StatusQueued
+		job.RetryAt = time.Time{}
+		s.active[path] = true
+		delete(s.waiting, path)
+		err := s.saveLocked()
+		s.mu.Unlock()
+		return true, err
+	}
+	s.mu.Unlock()
+
+	// Hash outside the lock; large recordings can take a while
+	hash, err := HashFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Another scan may have claimed the file while it was being hashed
+	if current := s.jobs[path]; current != job {
+		return false, nil
+	}
+	s.jobs[path] = &Job{
+		Path:     path,
+		Hash:     hash,
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+		Status:   StatusQueued,
+		QueuedAt: time.Now(),
+	}
+	s.active[path] = true
+	delete(s.waiting, path)
+	return true, s.saveLocked()
+}
+
+// Settled reports whether path may be claimed. Files the store already knows
+// with the same size and mtime are settled right away; new or changed files
+// must keep their size and mtime for window before they are settled.
+// Until then they are listed by Waiting.
+func (s *Store) Settled(path string, info os.FileInfo, window time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, ok := s.jobs[path]; ok && job.Size == info.Size() && job.ModTime.Equal(info.ModTime()) {
+		delete(s.waiting, path)
+		return true
+	}
+
+	now := time.Now()
+	seen, ok := s.waiting[path]
+	if !ok || seen.size != info.Size() || !seen.modTime.Equal(info.ModTime()) {
+		s.waiting[path] = &sighting{size: info.Size(), modTime: info.ModTime(), since: now}
+		return window <= 0
+	}
+	return now.Sub(seen.since) >= window
+}
+
+// Waiting returns the files that were seen but are not settled or claimed yet.
+// Files that disappeared in the meantime are dropped.
+func (s *Store) Waiting() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []string
+	for path := range s.waiting {
+		if _, err := os.Stat(path); err != nil {
+			delete(s.waiting, path)
+			continue
+		}
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// MarkProcessing records the start of an attempt
+func (s *Store) MarkProcessing(path string) error {
+	return s.update(path, func(job *Job) {
+		job.Status = StatusProcessing
+		job.Attempts++
+		job.StartedAt = time.Now()
+		job.FinishedAt = time.Time{}
+		job.Error = ""
+	})
+}
+
+// SetProfile records the processing profiles used for the current attempt
+func (s *Store) SetProfile(path, profile string) error {
+	return s.update(path, func(job *Job) {
+		job.Profile = profile
+	})
+}
+
+// MarkDone records a successful attempt
+func (s *Store) MarkDone(path string) error {
+	return s.finish(path, StatusDone, nil)
+}
+
+// MarkDoneWithOutputs records a successful attempt and the files it produced
+func (s *Store) MarkDoneWithOutputs(path string, outputs []string) error {
+	return s.update(path, func(job *Job) {
+		job.Status = StatusDone
+		job.FinishedAt = time.Now()
+		job.Outputs = outputs
+		job.DuplicateOf = ""
+	})
+}
+
+// MarkDuplicate records that path has the same content as the done job
+// original and was handled without transcribing it
+func (s *Store) MarkDuplicate(path, original string, outputs []string) error {
+	return s.update(path, func(job *Job) {
+		job.Status = StatusDone
+		job.FinishedAt = time.Now()
+		job.Outputs = outputs
+		job.DuplicateOf = original
+	})
+}
+
+// FindDone returns the done job whose content hash is hash, ignoring the job
+// for exclude. Jobs with recorded outputs are preferred, then the most recent.
+func (s *Store) FindDone(hash, exclude string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if hash == "" {
+		return Job{}, false
+	}
+	var found *Job
+	for path, job := range s.jobs {
+		if path == exclude || job.Status != StatusDone || job.Hash != hash {
+			continue
+		}
+		if found == nil || betterOriginal(job, found) {
+			found = job
+		}
+	}
+	if found == nil {
+		return Job{}, false
+	}
+	return *found, true
+}
+
+// AcquireHash registers a worker on the job for path. When another job
+// with the same content is already being worked on, path is held back and
+// false is returned; ReleaseHash on that job hands it back, so it can reuse
+// the outputs instead of transcribing the same audio twice.
+func (s *Store) AcquireHash(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[path]
+	if !ok || job.Hash == "" {
+		return true
+	}
+	if holder, busy := s.inflight[job.Hash]; busy && holder != path {
+		s.held[job.Hash] = append(s.held[job.Hash], path)
+		return false
+	}
+	s.inflight[job.Hash] = path
+	return true
+}
+
+// ReleaseHash ends the work registered by AcquireHash and returns the jobs
+// that were held back in the meantime
+func (s *Store) ReleaseHash(path string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Looked up by path: the job may have been claimed again with new content
+	for hash, holder := range s.inflight {
+		if holder == path {
+			delete(s.inflight, hash)
+			held := s.held[hash]
+			delete(s.held, hash)
+			return held
+		}
+	}
+	return nil
+}
+
+// betterOriginal reports whether a is a better source for reused outputs than b
+func betterOriginal(a, b *Job) bool {
+	if (len(a.Outputs) > 0) != (len(b.Outputs) > 0) {
+		return len(a.Outputs) > 0
+	}
+	return a.FinishedAt.After(b.FinishedAt)
+}
+
+// MarkFailed records a failed attempt together with its error
+func (s *Store) MarkFailed(path string, jobErr error) error {
+	return s.finish(path, StatusFailed, jobErr)
+}
+
+// MarkCancelled records that the user cancelled the job.
+// The file stays in place and is not claimed again during this session.
+func (s *Store) MarkCancelled(path string) error {
+	return s.update(path, func(job *Job) {
+		job.Status = StatusCancelled
+		job.FinishedAt = time.Now()
+		job.RetryAt = time.Time{}
+	})
+}
+
+// Requeue puts a job that was interrupted by shutdown back to queued so the
+// next start picks it up again. The interrupted attempt is not counted.
+func (s *Store) Requeue(path string) error {
+	return s.update(path, func(job *Job) {
+		job.Status = StatusQueued
+		if job.Attempts > 0 {
+			job.Attempts--
+		}
+		job.StartedAt = time.Time{}
+	})
+}
+
+// SetRunning registers cancel as the way to stop the running job for path
+func (s *Store) SetRunning(path string, cancel context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running[path] = cancel
+}
+
+// ClearRunning removes the cancel function registered by SetRunning
+func (s *Store) ClearRunning(path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.running, path)
+}
+
+// CancelRunning cancels the running job for path.
+// It reports false when no job for path is running.
+func (s *Store) CancelRunning(path string) bool {
+	s.mu.Lock()
+	cancel, ok := s.running[path]
+	s.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// MarkMovedToFailed records a failed attempt after which the file was moved to movedTo
+func (s *Store) MarkMovedToFailed(path string, jobErr error, movedTo string) error {
+	return s.update(path, func(job *Job) {
+		job.Status = StatusFailed
+		job.FinishedAt = time.Now()
+		job.Error = jobErr.Error()
+		job.MovedTo = movedTo
+	})
+}
+
+// ScheduleRetry records a failed attempt and queues the job again once at has passed.
+// The job is handed out by DueRetries when it is time.
+func (s *Store) ScheduleRetry(path string, jobErr error, at time.Time) error {
+	return s.update(path, func(job *Job) {
+		job.Status = StatusQueued
+		job.FinishedAt = time.Now()
+		job.Error = jobErr.Error()
+		job.RetryAt = at
+	})
+}
+
+// DueRetries returns the jobs whose retry time has passed, oldest first.
+// Each job is returned only once.
+func (s *Store) DueRetries(now time.Time) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*Job
+	for _, job := range s.jobs {
+		if job.Status == StatusQueued && !job.RetryAt.IsZero() && !job.RetryAt.After(now) {
+			due = append(due, job)
+		}
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+	sort.Slice(due, func(i, k int) bool { return due[i].RetryAt.Before(due[k].RetryAt) })
+
+	paths := make([]string, len(due))
+	for i, job := range due {
+		job.RetryAt = time.Time{}
+		paths[i] = job.Path
+	}
+	return paths, s.saveLocked()
+}
+
+func (s *Store) finish(path string, status Status, jobErr error) error {
+	return s.update(path, func(job *Job) {
+		job.Status = status
+		job.FinishedAt = time.Now()
+		if jobErr != nil {
+			job.Error = jobErr.Error()
+		}
+	})
+}
+
+// update applies fn to the job under the store lock and persists the result
+func (s *Store) update(path string, fn func(job *Job)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[path]
+	if !ok {
+		job = &Job{Path: path, QueuedAt: time.Now()}
+		s.jobs[path] = job
+	}
+	fn(job)
+	return s.saveLocked()
+}
+
+// Get returns a copy of the job for path
+func (s *Store) Get(path string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[path]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// Profile returns the processing profile recorded for path (empty when none)
+func (s *Store) Profile(path string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, ok := s.jobs[path]; ok {
+		return job.Profile
+	}
+	return ""
+}
+
+// List returns copies of all jobs with the given statuses (all jobs when none given),
+// oldest first
+func (s *Store) List(statuses ...Status) []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []Job
+	for _, job := range s.jobs {
+		if len(statuses) == 0 || hasStatus(job.Status, statuses) {
+			result = append(result, *job)
+		}
+	}
+	sort.Slice(result, func(i, k int) bool {
+		if result[i].QueuedAt.Equal(result[k].QueuedAt) {
+			return result[i].Path < result[k].Path
+		}
+		return result[i].QueuedAt.Before(result[k].QueuedAt)
+	})
+	return result
+}
+
+// Count returns the number of jobs with the given status
+func (s *Store) Count(status Status) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, job := range s.jobs {
+		if job.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+func hasStatus(status Status, statuses []Status) bool {
+	for _, st := range statuses {
+		if st == status {
+			return true
+		}
+	}
+	return false
+}
+
+// compactLocked drops the oldest done jobs (including duplicates) once there
+// are more than maxDoneJobs. Jobs claimed during this session are kept so a
+// file left in the input folder is not picked up again. The stored transcript
+// of a dropped job is removed unless a remaining job shares its hash.
+// Caller must hold s.mu.
+func (s *Store) compactLocked() {
+	var done []*Job
+	for path, job := range s.jobs {
+		if job.Status == StatusDone && !s.active[path] {
+			done = append(done, job)
+		}
+	}
+	if len(done) <= maxDoneJobs {
+		return
+	}
+	sort.Slice(done, func(i, k int) bool { return done[i].FinishedAt.After(done[k].FinishedAt) })
+	dropped := make(map[string]bool)
+	for _, job := range done[keepDoneJobs:] {
+		delete(s.jobs, job.Path)
+		if job.Hash != "" {
+			dropped[job.Hash] = true
+		}
+	}
+
+	dir := s.TranscriptDir()
+	if dir == "" {
+		return
+	}
+	for _, job := range s.jobs {
+		delete(dropped, job.Hash)
+	}
+	for hash := range dropped {
+		// Best effort: a leftover file only costs disk space
+		os.Remove(transcript.Path(dir, hash))
+	}
+}
+
+// saveLocked compacts and writes the store atomically. The whole store is
+// rewritten on every state change; compaction keeps it to a few thousand
+// jobs, so this stays cheap next to a transcription. Caller must hold s.mu.
+func (s *Store) saveLocked() error {
+	s.compactLocked()
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(storeFile{Version: storeVersion, Jobs: s.jobs, RTF: s.rtf}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode job store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create job store directory: %w", err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write job store: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace job store: %w", err)
+	}
+	return nil
+}
+
+// HashFile returns the hex encoded SHA-256 of the file content
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
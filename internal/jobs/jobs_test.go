@@ -0,0 +1,524 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestOpen_MissingFileGivesEmptyStore(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "jobs.json"))
+	require.NoError(t, err)
+	assert.Empty(t, store.List())
+}
+
+func TestOpen_CorruptFileIsKeptAside(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	writeFile(t, path, "{not json")
+
+	store, err := Open(path)
+	assert.Error(t, err)
+	require.NotNil(t, store)
+	assert.Empty(t, store.List())
+	assert.FileExists(t, path+".corrupt")
+}
+
+func TestClaim_NewFile(t *testing.T) {
+	dir := t.TempDir()
+	audio := filepath.Join(dir, "a.wav")
+	writeFile(t, audio, "hello")
+
+	store := NewMemoryStore()
+	claimed, err := store.Claim(audio)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	job, ok := store.Get(audio)
+	require.True(t, ok)
+	assert.Equal(t, StatusQueued, job.Status)
+	// sha256("hello")
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", job.Hash)
+	assert.Equal(t, int64(5), job.Size)
+	assert.False(t, job.QueuedAt.IsZero())
+}
+
+func TestClaim_MissingFile(t *testing.T) {
+	store := NewMemoryStore()
+	claimed, err := store.Claim(filepath.Join(t.TempDir(), "missing.wav"))
+	assert.Error(t, err)
+	assert.False(t, claimed)
+}
+
+func TestClaim_ChangedContentIsRequeued(t *testing.T) {
+	dir := t.TempDir()
+	audio := filepath.Join(dir, "a.wav")
+	writeFile(t, audio, "first")
+
+	store := NewMemoryStore()
+	claimed, err := store.Claim(audio)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.NoError(t, store.MarkProcessing(audio))
+	require.NoError(t, store.MarkDone(audio))
+
+	claimed, err = store.Claim(audio)
+	require.NoError(t, err)
+	assert.False(t, claimed, "finished file must not be claimed again")
+
+	// Same name, new recording
+	writeFile(t, audio, "second take")
+	require.NoError(t, os.Chtimes(audio, time.Now(), time.Now().Add(time.Minute)))
+
+	claimed, err = store.Claim(audio)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	job, _ := store.Get(audio)
+	assert.Equal(t, StatusQueued, job.Status)
+	assert.Equal(t, 0, job.Attempts)
+}
+
+func TestLifecycle_PersistsAcrossOpen(t *testing.T) {
+	dir := t.TempDir()
+	storePath := filepath.Join(dir, "jobs.json")
+	okFile := filepath.Join(dir, "ok.wav")
+	badFile := filepath.Join(dir, "bad.wav")
+	writeFile(t, okFile, "ok")
+	writeFile(t, badFile, "bad")
+
+	store, err := Open(storePath)
+	require.NoError(t, err)
+	for _, f := range []string{okFile, badFile} {
+		claimed, err := store.Claim(f)
+		require.NoError(t, err)
+		require.True(t, claimed)
+		require.NoError(t, store.MarkProcessing(f))
+	}
+	require.NoError(t, store.MarkDone(okFile))
+	require.NoError(t, store.MarkFailed(badFile, errors.New("whisper exited with status 1")))
+
+	reopened, err := Open(storePath)
+	require.NoError(t, err)
+
+	job, ok := reopened.Get(okFile)
+	require.True(t, ok)
+	assert.Equal(t, StatusDone, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+	assert.GreaterOrEqual(t, job.Duration(), time.Duration(0))
+	assert.False(t, job.FinishedAt.IsZero())
+
+	job, ok = reopened.Get(badFile)
+	require.True(t, ok)
+	assert.Equal(t, StatusFailed, job.Status)
+	assert.Equal(t, "whisper exited with status 1", job.Error)
+
+	assert.Equal(t, 1, reopened.Count(StatusDone))
+	assert.Equal(t, 1, reopened.Count(StatusFailed))
+	assert.Len(t, reopened.List(StatusDone, StatusFailed), 2)
+}
+
+func TestClaim_RecoversInterruptedJobOnce(t *testing.T) {
+	dir := t.TempDir()
+	storePath := filepath.Join(dir, "jobs.json")
+	audio := filepath.Join(dir, "a.wav")
+	writeFile(t, audio, "audio")
+
+	store, err := Open(storePath)
+	require.NoError(t, err)
+	_, err = store.Claim(audio)
+	require.NoError(t, err)
+	require.NoError(t, store.MarkProcessing(audio))
+
+	reopened, err := Open(storePath)
+	require.NoError(t, err)
+
+	claimed, err := reopened.Claim(audio)
+	require.NoError(t, err)
+	assert.True(t, claimed, "interrupted job should be queued again after restart")
+
+	claimed, err = reopened.Claim(audio)
+	require.NoError(t, err)
+	assert.False(t, claimed, "recovered job must only be queued once")
+}
+
+func TestSettled_NewFileWaitsForWindow(t *testing.T) {
+	dir := t.TempDir()
+	audio := filepath.Join(dir, "a.wav")
+	writeFile(t, audio, "partial")
+	store := NewMemoryStore()
+
+	info, err := os.Stat(audio)
+	require.NoError(t, err)
+	assert.False(t, store.Settled(audio, info, time.Hour))
+	assert.Equal(t, []string{audio}, store.Waiting())
+
+	// Still unchanged, but the window has not passed
+	assert.False(t, store.Settled(audio, info, time.Hour))
+
+	// Window of zero settles on the next check
+	assert.True(t, store.Settled(audio, info, 0))
+	assert.Equal(t, []string{audio}, store.Waiting(), "settled files wait until claimed")
+
+	claimed, err := store.Claim(audio)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Empty(t, store.Waiting())
+}
+
+func TestSettled_ChangeRestartsWindow(t *testing.T) {
+	dir := t.TempDir()
+	audio := filepath.Join(dir, "a.wav")
+	writeFile(t, audio, "partial")
+	store := NewMemoryStore()
+
+	info, err := os.Stat(audio)
+	require.NoError(t, err)
+	store.Settled(audio, info, 50*time.Millisecond)
+	time.Sleep(60 * time.Millisecond)
+
+	// The copy continued: size and mtime changed, so the window starts over
+	writeFile(t, audio, "partial and more")
+	require.NoError(t, os.Chtimes(audio, time.Now(), time.Now().Add(time.Second)))
+	info, err = os.Stat(audio)
+	require.NoError(t, err)
+	assert.False(t, store.Settled(audio, info, 50*time.Millisecond))
+
+	time.Sleep(60 * time.Millisecond)
+	assert.True(t, store.Settled(audio, info, 50*time.Millisecond))
+}
+
+func TestSettled_KnownFileIsSettled(t *testing.T) {
+	dir := t.TempDir()
+	audio := filepath.Join(dir, "a.wav")
+	writeFile(t, audio, "audio")
+	store := NewMemoryStore()
+
+	_, err := store.Claim(audio)
+	require.NoError(t, err)
+	require.NoError(t, store.MarkProcessing(audio))
+	require.NoError(t, store.MarkDone(audio))
+
+	info, err := os.Stat(audio)
+	require.NoError(t, err)
+	assert.True(t, store.Settled(audio, info, time.Hour))
+	assert.Empty(t, store.Waiting())
+}
+
+func TestWaiting_DropsRemovedFiles(t *testing.T) {
+	dir := t.TempDir()
+	audio := filepath.Join(dir, "a.wav")
+	writeFile(t, audio, "partial")
+	store := NewMemoryStore()
+
+	info, err := os.Stat(audio)
+	require.NoError(t, err)
+	store.Settled(audio, info, time.Hour)
+	require.NoError(t, os.Remove(audio))
+
+	assert.Empty(t, store.Waiting())
+}
+
+func TestScheduleRetry_DueRetriesReturnsOnce(t *testing.T) {
+	dir := t.TempDir()
+	early := filepath.Join(dir, "early.wav")
+	late := filepath.Join(dir, "late.wav")
+	writeFile(t, early, "early")
+	writeFile(t, late, "late")
+
+	store := NewMemoryStore()
+	now := time.Now()
+	for _, f := range []string{early, late} {
+		_, err := store.Claim(f)
+		require.NoError(t, err)
+		require.NoError(t, store.MarkProcessing(f))
+	}
+	require.NoError(t, store.ScheduleRetry(early, errors.New("crashed"), now.Add(time.Second)))
+	require.NoError(t, store.ScheduleRetry(late, errors.New("crashed"), now.Add(time.Hour)))
+
+	job, _ := store.Get(early)
+	assert.Equal(t, StatusQueued, job.Status)
+	assert.Equal(t, "crashed", job.Error)
+
+	due, err := store.DueRetries(now)
+	require.NoError(t, err)
+	assert.Empty(t, due, "nothing is due yet")
+
+	due, err = store.DueRetries(now.Add(2 * time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, []string{early}, due)
+
+	due, err = store.DueRetries(now.Add(2 * time.Second))
+	require.NoError(t, err)
+	assert.Empty(t, due, "a due retry is handed out only once")
+
+	// Waiting for a retry counts as queued, so scans do not claim it again
+	claimed, err := store.Claim(late)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+}
+
+func TestClaim_FailedFileMovedBackIsRequeued(t *testing.T) {
+	dir := t.TempDir()
+	audio := filepath.Join(dir, "a.wav")
+	writeFile(t, audio, "audio")
+	store := NewMemoryStore()
+
+	_, err := store.Claim(audio)
+	require.NoError(t, err)
+	require.NoError(t, store.MarkProcessing(audio))
+
+	failedPath := filepath.Join(dir, "failed.wav")
+	require.NoError(t, os.Rename(audio, failedPath))
+	require.NoError(t, store.MarkMovedToFailed(audio, errors.New("GPU error"), failedPath))
+
+	job, _ := store.Get(audio)
+	assert.Equal(t, StatusFailed, job.Status)
+	assert.Equal(t, failedPath, job.MovedTo)
+
+	// The user moves the file back to try again
+	require.NoError(t, os.Rename(failedPath, audio))
+	claimed, err := store.Claim(audio)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+
+	job, _ = store.Get(audio)
+	assert.Equal(t, StatusQueued, job.Status)
+	assert.Equal(t, 0, job.Attempts)
+	assert.Empty(t, job.MovedTo)
+}
+
+func TestClaim_FailedInPlaceNotRetried(t *testing.T) {
+	dir := t.TempDir()
+	storePath := filepath.Join(dir, "jobs.json")
+	audio := filepath.Join(dir, "a.wav")
+	writeFile(t, audio, "audio")
+
+	store, err := Open(storePath)
+	require.NoError(t, err)
+	_, err = store.Claim(audio)
+	require.NoError(t, err)
+	require.NoError(t, store.MarkProcessing(audio))
+	require.NoError(t, store.MarkFailed(audio, errors.New("whisper-ctranslate2 not found")))
+
+	claimed, err := store.Claim(audio)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	// A restart does not retry it blindly
+	reopened, err := Open(storePath)
+	require.NoError(t, err)
+	claimed, err = reopened.Claim(audio)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	job, _ := reopened.Get(audio)
+	assert.Equal(t, StatusFailed, job.Status)
+	assert.Equal(t, 1, job.Attempts)
+
+	// Saving the file again is a retry with fresh attempts
+	require.NoError(t, os.Chtimes(audio, time.Now(), time.Now().Add(time.Minute)))
+	claimed, err = reopened.Claim(audio)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	job, _ = reopened.Get(audio)
+	assert.Equal(t, StatusQueued, job.Status)
+	assert.Equal(t, 0, job.Attempts)
+}
+
+func TestRequeue_InterruptedJobResumesAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	storePath := filepath.Join(dir, "jobs.json")
+	audio := filepath.Join(dir, "a.wav")
+	writeFile(t, audio, "audio")
+
+	store, err := Open(storePath)
+	require.NoError(t, err)
+	_, err = store.Claim(audio)
+	require.NoError(t, err)
+	require.NoError(t, store.MarkProcessing(audio))
+	require.NoError(t, store.Requeue(audio))
+
+	job, _ := store.Get(audio)
+	assert.Equal(t, StatusQueued, job.Status)
+	assert.Equal(t, 0, job.Attempts, "an interrupted attempt is not counted")
+	assert.True(t, job.StartedAt.IsZero())
+
+	reopened, err := Open(storePath)
+	require.NoError(t, err)
+	claimed, err := reopened.Claim(audio)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func TestMarkCancelled_NotClaimedAgain(t *testing.T) {
+	dir := t.TempDir()
+	storePath := filepath.Join(dir, "jobs.json")
+	audio := filepath.Join(dir, "a.wav")
+	writeFile(t, audio, "audio")
+
+	store, err := Open(storePath)
+	require.NoError(t, err)
+	_, err = store.Claim(audio)
+	require.NoError(t, err)
+	require.NoError(t, store.MarkCancelled(audio))
+
+	job, _ := store.Get(audio)
+	assert.Equal(t, StatusCancelled, job.Status)
+
+	claimed, err := store.Claim(audio)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	reopened, err := Open(storePath)
+	require.NoError(t, err)
+	claimed, err = reopened.Claim(audio)
+	require.NoError(t, err)
+	assert.False(t, claimed, "a cancelled file stays cancelled after a restart")
+}
+
+func TestCancelRunning(t *testing.T) {
+	store := NewMemoryStore()
+	assert.False(t, store.CancelRunning("/in/a.wav"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store.SetRunning("/in/a.wav", cancel)
+	assert.True(t, store.CancelRunning("/in/a.wav"))
+	assert.Error(t, ctx.Err())
+
+	store.ClearRunning("/in/a.wav")
+	assert.False(t, store.CancelRunning("/in/a.wav"))
+}
+
+func TestSetProfile_Persisted(t *testing.T) {
+	dir := t.TempDir()
+	storePath := filepath.Join(dir, "jobs.json")
+	audio := filepath.Join(dir, "a.wav")
+	writeFile(t, audio, "audio")
+
+	store, err := Open(storePath)
+	require.NoError(t, err)
+	_, err = store.Claim(audio)
+	require.NoError(t, err)
+	require.NoError(t, store.SetProfile(audio, "english"))
+
+	reopened, err := Open(storePath)
+	require.NoError(t, err)
+	job, ok := reopened.Get(audio)
+	require.True(t, ok)
+	assert.Equal(t, "english", job.Profile)
+}
+
+func TestFindDone_SameContentUnderAnotherName(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "meeting.wav")
+	second := filepath.Join(dir, "meeting-copy.wav")
+	other := filepath.Join(dir, "other.wav")
+	writeFile(t, first, "same audio")
+	writeFile(t, second, "same audio")
+	writeFile(t, other, "different audio")
+
+	store := NewMemoryStore()
+	for _, path := range []string{first, second, other} {
+		_, err := store.Claim(path)
+		require.NoError(t, err)
+	}
+
+	job, _ := store.Get(second)
+	_, found := store.FindDone(job.Hash, second)
+	assert.False(t, found, "the first file is not done yet")
+
+	require.NoError(t, store.MarkDoneWithOutputs(first, []string{"/output/meeting.txt"}))
+	original, found := store.FindDone(job.Hash, second)
+	require.True(t, found)
+	assert.Equal(t, first, original.Path)
+	assert.Equal(t, []string{"/output/meeting.txt"}, original.Outputs)
+
+	otherJob, _ := store.Get(other)
+	_, found = store.FindDone(otherJob.Hash, other)
+	assert.False(t, found)
+
+	// A duplicate without outputs does not replace the original as the source
+	require.NoError(t, store.MarkDuplicate(second, first, nil))
+	original, found = store.FindDone(job.Hash, filepath.Join(dir, "third.wav"))
+	require.True(t, found)
+	assert.Equal(t, first, original.Path)
+}
+
+func TestSave_CompactsOldestDoneJobs(t *testing.T) {
+	store := NewMemoryStore()
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < maxDoneJobs; i++ {
+		path := filepath.Join("/input", fmt.Sprintf("%05d.wav", i))
+		store.jobs[path] = &Job{Path: path, Status: StatusDone, FinishedAt: start.Add(time.Duration(i) * time.Second)}
+	}
+	failed := filepath.Join("/input", "failed.wav")
+	store.jobs[failed] = &Job{Path: failed, Status: StatusFailed}
+
+	latest := filepath.Join("/input", "latest.wav")
+	require.NoError(t, store.MarkDone(latest))
+
+	assert.Len(t, store.List(StatusDone), keepDoneJobs)
+	_, ok := store.Get(latest)
+	assert.True(t, ok, "the most recent job is kept")
+	_, ok = store.Get(filepath.Join("/input", "00000.wav"))
+	assert.False(t, ok, "the oldest job is dropped")
+	_, ok = store.Get(failed)
+	assert.True(t, ok, "only done jobs are compacted")
+}
+
+func TestSave_CompactionRemovesDroppedTranscripts(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "jobs.json"))
+	require.NoError(t, err)
+	dir := store.TranscriptDir()
+	require.NoError(t, os.MkdirAll(dir, 0755))
+
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < maxDoneJobs; i++ {
+		path := filepath.Join("/input", fmt.Sprintf("%05d.wav", i))
+		store.jobs[path] = &Job{Path: path, Hash: fmt.Sprintf("hash%05d", i), Status: StatusDone, FinishedAt: start.Add(time.Duration(i) * time.Second)}
+	}
+	// A recent duplicate shares the hash of the oldest job
+	shared := filepath.Join("/input", "copy.wav")
+	store.jobs[shared] = &Job{Path: shared, Hash: "hash00000", Status: StatusDone, FinishedAt: time.Now()}
+	for _, hash := range []string{"hash00000", "hash00001"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, hash+".json"), []byte("{}"), 0644))
+	}
+
+	require.NoError(t, store.MarkDone(filepath.Join("/input", "latest.wav")))
+
+	_, ok := store.Get(filepath.Join("/input", "00001.wav"))
+	require.False(t, ok)
+	assert.NoFileExists(t, filepath.Join(dir, "hash00001.json"), "transcript of a dropped job is removed")
+	assert.FileExists(t, filepath.Join(dir, "hash00000.json"), "transcript shared with a kept job stays")
+}
+
+func TestAcquireHash_HoldsSameContent(t *testing.T) {
+	dir := t.TempDir()
+	original := filepath.Join(dir, "meeting.wav")
+	duplicate := filepath.Join(dir, "copy.wav")
+	require.NoError(t, os.WriteFile(original, []byte("same audio"), 0644))
+	require.NoError(t, os.WriteFile(duplicate, []byte("same audio"), 0644))
+	store := NewMemoryStore()
+	for _, file := range []string{original, duplicate} {
+		_, err := store.Claim(file)
+		require.NoError(t, err)
+	}
+
+	assert.True(t, store.AcquireHash(original))
+	assert.True(t, store.AcquireHash(original), "acquiring twice for the same job is fine")
+	assert.False(t, store.AcquireHash(duplicate), "same content is held back")
+
+	assert.Nil(t, store.ReleaseHash(duplicate), "only the holder releases")
+	assert.Equal(t, []string{duplicate}, store.ReleaseHash(original))
+	assert.True(t, store.AcquireHash(duplicate))
+}
@@ -0,0 +1,117 @@
+//go:build !windows
+// +build !windows
+
+package processor
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupSlowWhisper puts a whisper-ctranslate2 stand-in that never finishes on PATH
+// and returns a config and a claimed audio file
+func setupSlowWhisper(t *testing.T) (*config.Config, *jobs.Store, string) {
+	t.Helper()
+	tempDir := t.TempDir()
+
+	binDir := filepath.Join(tempDir, "bin")
+	require.NoError(t, os.MkdirAll(binDir, 0755))
+	script := "#!/bin/sh\nsleep 30\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "whisper-ctranslate2"), []byte(script), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(tempDir, "input")
+	cfg.OutputDir = filepath.Join(tempDir, "output")
+	cfg.ArchiveDir = filepath.Join(tempDir, "archive")
+	cfg.FailedDir = filepath.Join(tempDir, "failed")
+	require.NoError(t, EnsureDirectories(cfg, log.New(os.Stdout, "", log.LstdFlags)))
+
+	audio := filepath.Join(cfg.InputDir, "meeting.wav")
+	require.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
+
+	store := jobs.NewMemoryStore()
+	_, err := store.Claim(audio)
+	require.NoError(t, err)
+	return cfg, store, audio
+}
+
+// runProcessFile runs processFile in the background and returns a channel closed when it returns
+func runProcessFile(ctx context.Context, cfg *config.Config, store *jobs.Store, audio string) <-chan struct{} {
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		processFile(ctx, cfg, log.New(os.Stdout, "", log.LstdFlags), &logBuffer, &logMutex, store, false, audio)
+	}()
+	return done
+}
+
+func waitDone(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("processFile did not return after cancellation")
+	}
+}
+
+func TestProcessFile_UserCancel(t *testing.T) {
+	cfg, store, audio := setupSlowWhisper(t)
+	done := runProcessFile(context.Background(), cfg, store, audio)
+
+	// Wait until the transcription is registered as running, then cancel it
+	require.Eventually(t, func() bool { return CancelFile(store, audio) }, 5*time.Second, 10*time.Millisecond)
+	waitDone(t, done)
+
+	job, _ := store.Get(audio)
+	assert.Equal(t, jobs.StatusCancelled, job.Status)
+	assert.FileExists(t, audio, "a cancelled file stays in the input folder")
+	assert.False(t, CancelFile(store, audio), "the job is no longer running")
+}
+
+func TestProcessFile_ShutdownRequeues(t *testing.T) {
+	cfg, store, audio := setupSlowWhisper(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := runProcessFile(ctx, cfg, store, audio)
+
+	require.Eventually(t, func() bool {
+		job, _ := store.Get(audio)
+		return job.Status == jobs.StatusProcessing
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond) // let whisper start
+	cancel()
+	waitDone(t, done)
+
+	job, _ := store.Get(audio)
+	assert.Equal(t, jobs.StatusQueued, job.Status)
+	assert.Equal(t, 0, job.Attempts, "an interrupted attempt is not counted")
+	assert.FileExists(t, audio)
+	assert.NoFileExists(t, filepath.Join(cfg.FailedDir, "meeting.wav"))
+}
+
+func TestProcessFile_RecordsProfile(t *testing.T) {
+	cfg, store, audio := setupSlowWhisper(t)
+	cfg.Profiles = map[string]config.Profile{
+		"*.wav": {Name: "english", Language: "en"},
+	}
+	done := runProcessFile(context.Background(), cfg, store, audio)
+
+	require.Eventually(t, func() bool { return CancelFile(store, audio) }, 5*time.Second, 10*time.Millisecond)
+	waitDone(t, done)
+
+	assert.Equal(t, "english", store.Profile(audio))
+}
@@ -0,0 +1,144 @@
+package processor
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/ui"
+	"github.com/infoHiroki/KoeMoji-Go/internal/whisper"
+)
+
+// duplicateAction reports whether files with already transcribed content
+// reuse the earlier outputs or are skipped. Both are false for "process".
+func duplicateAction(c *config.Config) (reuse, skip bool) {
+	return c.DuplicateAction == config.DuplicateActionReuse, c.DuplicateAction == config.DuplicateActionSkip
+}
+
+// holdDuplicate reports whether filePath must wait because a worker is on a
+// file with the same content right now. processQueue queues it again once
+// that job finishes, and handleDuplicate then reuses the outputs.
+func holdDuplicate(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	jobStore *jobs.Store, filePath string) bool {
+
+	if reuse, skip := duplicateAction(config); !reuse && !skip {
+		return false
+	}
+	if jobStore.AcquireHash(filePath) {
+		return false
+	}
+	msg := ui.GetMessages(config)
+	logger.LogInfo(log, logBuffer, logMutex, msg.DuplicateHeld, filepath.Base(filePath))
+	return true
+}
+
+// handleDuplicate checks whether filePath has the same content as a file that
+// was already transcribed. Depending on duplicate_action the earlier outputs
+// are copied under the new name, or the file is archived without outputs.
+// It reports true when the file was handled and must not be transcribed.
+func handleDuplicate(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	jobStore *jobs.Store, filePath string) bool {
+
+	reuse, skip := duplicateAction(config)
+	if !reuse && !skip {
+		return false
+	}
+
+	job, ok := jobStore.Get(filePath)
+	if !ok {
+		return false
+	}
+	original, ok := jobStore.FindDone(job.Hash, filePath)
+	if !ok {
+		return false
+	}
+
+	fileName := filepath.Base(filePath)
+	originalName := filepath.Base(original.Path)
+	msg := ui.GetMessages(config)
+
+	var outputs []string
+	if reuse {
+		var err error
+		outputs, err = copyOutputs(config, original, filePath)
+		if err != nil {
+			// Fall back to a normal transcription
+			logger.LogError(log, logBuffer, logMutex, msg.DuplicateReuseFailed, fileName, err)
+			return false
+		}
+		logger.LogDone(log, logBuffer, logMutex, msg.DuplicateReused, fileName, originalName)
+	} else {
+		logger.LogInfo(log, logBuffer, logMutex, msg.DuplicateSkipped, fileName, originalName)
+	}
+
+	if err := moveToArchive(config, filePath); err != nil {
+		logger.LogError(log, logBuffer, logMutex, msg.ProcessFailed, fileName, err)
+	}
+	if err := jobStore.MarkDuplicate(filePath, original.Path, outputs); err != nil {
+		logger.LogError(log, logBuffer, logMutex, "Failed to update job store: %v", err)
+	}
+	return true
+}
+
+// copyOutputs copies the outputs of original next to where the outputs of
+// filePath would be written, replacing the old base name with the new one
+// (meeting.txt and meeting_summary.txt become copy.txt and copy_summary.txt).
+func copyOutputs(config *config.Config, original jobs.Job, filePath string) ([]string, error) {
+	if len(original.Outputs) == 0 {
+		return nil, fmt.Errorf("no outputs recorded for %s", filepath.Base(original.Path))
+	}
+
+	oldBase := strings.TrimSuffix(filepath.Base(original.Path), filepath.Ext(original.Path))
+	newBase := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	outputDir := config.MirrorDir(config.OutputDir, filePath)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, err
+	}
+
+	outputs := make([]string, 0, len(original.Outputs))
+	for _, src := range original.Outputs {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(src)
+		if strings.HasPrefix(name, oldBase) {
+			name = newBase + strings.TrimPrefix(name, oldBase)
+		}
+		dst := filepath.Join(outputDir, name)
+		if dst != src {
+			if err := os.WriteFile(dst, data, 0644); err != nil {
+				return nil, err
+			}
+		}
+		outputs = append(outputs, dst)
+	}
+	return outputs, nil
+}
+
+// existingOutputs returns the transcript, clean transcript and summary files
+// written for filePath
+func existingOutputs(config *config.Config, filePath string) []string {
+	basename := strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	outputDir := config.MirrorDir(config.OutputDir, filePath)
+	var candidates []string
+	for _, format := range config.Formats() {
+		candidates = append(candidates, filepath.Join(outputDir, basename+"."+format))
+		candidates = append(candidates, filepath.Join(outputDir, basename+whisper.CleanOutputSuffix+"."+format))
+	}
+	candidates = append(candidates, filepath.Join(outputDir, basename+"_summary.txt"))
+
+	var outputs []string
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			outputs = append(outputs, path)
+		}
+	}
+	return outputs
+}
@@ -0,0 +1,197 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/ui"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupDuplicateTest creates meeting.wav that was transcribed already and a
+// claimed copy.wav with the same content
+func setupDuplicateTest(t *testing.T, action string) (*config.Config, *jobs.Store, string, string) {
+	tempDir := t.TempDir()
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(tempDir, "input")
+	cfg.OutputDir = filepath.Join(tempDir, "output")
+	cfg.ArchiveDir = filepath.Join(tempDir, "archive")
+	cfg.DuplicateAction = action
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	require.NoError(t, os.MkdirAll(cfg.OutputDir, 0755))
+
+	original := filepath.Join(cfg.InputDir, "meeting.wav")
+	duplicate := filepath.Join(cfg.InputDir, "copy.wav")
+	require.NoError(t, os.WriteFile(original, []byte("same audio"), 0644))
+	require.NoError(t, os.WriteFile(duplicate, []byte("same audio"), 0644))
+
+	store := jobs.NewMemoryStore()
+	_, err := store.Claim(original)
+	require.NoError(t, err)
+	_, err = store.Claim(duplicate)
+	require.NoError(t, err)
+
+	transcript := filepath.Join(cfg.OutputDir, "meeting.txt")
+	summary := filepath.Join(cfg.OutputDir, "meeting_summary.txt")
+	require.NoError(t, os.WriteFile(transcript, []byte("transcript"), 0644))
+	require.NoError(t, os.WriteFile(summary, []byte("summary"), 0644))
+	require.NoError(t, store.MarkDoneWithOutputs(original, existingOutputs(cfg, original)))
+	return cfg, store, original, duplicate
+}
+
+func TestHandleDuplicate_Reuse(t *testing.T) {
+	cfg, store, original, duplicate := setupDuplicateTest(t, config.DuplicateActionReuse)
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	assert.True(t, handleDuplicate(cfg, nil, &logBuffer, &logMutex, store, duplicate))
+
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "copy.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "transcript", string(data))
+	data, err = os.ReadFile(filepath.Join(cfg.OutputDir, "copy_summary.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "summary", string(data))
+
+	assert.NoFileExists(t, duplicate)
+	assert.FileExists(t, filepath.Join(cfg.ArchiveDir, "copy.wav"))
+
+	job, _ := store.Get(duplicate)
+	assert.Equal(t, jobs.StatusDone, job.Status)
+	assert.Equal(t, original, job.DuplicateOf)
+	assert.Len(t, job.Outputs, 2)
+}
+
+func TestHandleDuplicate_Skip(t *testing.T) {
+	cfg, store, original, duplicate := setupDuplicateTest(t, config.DuplicateActionSkip)
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	assert.True(t, handleDuplicate(cfg, nil, &logBuffer, &logMutex, store, duplicate))
+
+	assert.NoFileExists(t, filepath.Join(cfg.OutputDir, "copy.txt"))
+	assert.FileExists(t, filepath.Join(cfg.ArchiveDir, "copy.wav"))
+	job, _ := store.Get(duplicate)
+	assert.Equal(t, jobs.StatusDone, job.Status)
+	assert.Equal(t, original, job.DuplicateOf)
+	assert.Empty(t, job.Outputs)
+}
+
+func TestHandleDuplicate_Process(t *testing.T) {
+	cfg, store, _, duplicate := setupDuplicateTest(t, config.DuplicateActionProcess)
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	assert.False(t, handleDuplicate(cfg, nil, &logBuffer, &logMutex, store, duplicate))
+	assert.FileExists(t, duplicate)
+}
+
+func TestHandleDuplicate_MissingOutputsFallsBack(t *testing.T) {
+	cfg, store, _, duplicate := setupDuplicateTest(t, config.DuplicateActionReuse)
+	require.NoError(t, os.Remove(filepath.Join(cfg.OutputDir, "meeting.txt")))
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	assert.False(t, handleDuplicate(cfg, nil, &logBuffer, &logMutex, store, duplicate),
+		"the file is transcribed when the earlier outputs are gone")
+	assert.FileExists(t, duplicate)
+	job, _ := store.Get(duplicate)
+	assert.Equal(t, jobs.StatusQueued, job.Status)
+}
+
+func TestHandleDuplicate_NoEarlierJob(t *testing.T) {
+	cfg, store, original, _ := setupDuplicateTest(t, config.DuplicateActionReuse)
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	// The original itself is not a duplicate of its own job
+	assert.False(t, handleDuplicate(cfg, nil, &logBuffer, &logMutex, store, original))
+}
+
+func TestProcessQueue_DuplicateHeldUntilOriginalDone(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(tempDir, "input")
+	cfg.OutputDir = filepath.Join(tempDir, "output")
+	cfg.ArchiveDir = filepath.Join(tempDir, "archive")
+	cfg.TranscriptionBackend = config.BackendMock
+	cfg.DuplicateAction = config.DuplicateActionReuse
+	cfg.MaxConcurrentJobs = 2
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+
+	original := filepath.Join(cfg.InputDir, "meeting.wav")
+	duplicate := filepath.Join(cfg.InputDir, "copy.wav")
+	require.NoError(t, os.WriteFile(original, []byte("same audio"), 0644))
+	require.NoError(t, os.WriteFile(duplicate, []byte("same audio"), 0644))
+	store := jobs.NewMemoryStore()
+	for _, file := range []string{original, duplicate} {
+		_, err := store.Claim(file)
+		require.NoError(t, err)
+	}
+
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var isProcessing bool
+
+	// The first worker has started on the original when the second takes the copy
+	require.True(t, store.AcquireHash(original))
+	processingFiles := []string{original}
+	queuedFiles := []string{duplicate}
+	startWorkers(context.Background(), cfg, nil, &logBuffer, &logMutex, &queuedFiles, &processingFiles,
+		&isProcessing, &ScheduleState{}, store, &mu, &wg, false)
+	wg.Wait()
+
+	assert.Empty(t, queuedFiles, "the copy is held back, not queued")
+	assert.Equal(t, []string{original}, processingFiles)
+	assert.NoFileExists(t, filepath.Join(cfg.OutputDir, "copy.txt"))
+
+	// The first worker finishes the original and then takes the copy back
+	wg.Add(1)
+	processQueue(context.Background(), cfg, nil, &logBuffer, &logMutex, original, &queuedFiles, &processingFiles,
+		&isProcessing, &ScheduleState{}, store, &mu, &wg, false)
+
+	job, _ := store.Get(duplicate)
+	assert.Equal(t, jobs.StatusDone, job.Status)
+	assert.Equal(t, original, job.DuplicateOf)
+	assert.FileExists(t, filepath.Join(cfg.OutputDir, "copy.txt"))
+	assert.Empty(t, processingFiles)
+
+	msg := ui.GetMessages(cfg)
+	transcribed := 0
+	for _, entry := range logBuffer {
+		if entry.Message == fmt.Sprintf(msg.ProcessingFile, "meeting.wav") ||
+			entry.Message == fmt.Sprintf(msg.ProcessingFile, "copy.wav") {
+			transcribed++
+		}
+	}
+	assert.Equal(t, 1, transcribed, "the same audio is transcribed once")
+}
+
+func TestExistingOutputs_SeveralFormats(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(t.TempDir(), "input")
+	cfg.OutputDir = filepath.Join(t.TempDir(), "output")
+	cfg.OutputFormats = []string{"srt", "txt"}
+	require.NoError(t, os.MkdirAll(cfg.OutputDir, 0755))
+	for _, name := range []string{"talk.srt", "talk.txt", "talk.vtt", "talk_clean.txt", "talk_clean.vtt", "talk_summary.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(cfg.OutputDir, name), []byte("x"), 0644))
+	}
+
+	outputs := existingOutputs(cfg, filepath.Join(cfg.InputDir, "talk.wav"))
+	assert.Equal(t, []string{
+		filepath.Join(cfg.OutputDir, "talk.srt"),
+		filepath.Join(cfg.OutputDir, "talk.txt"),
+		filepath.Join(cfg.OutputDir, "talk_clean.txt"),
+		filepath.Join(cfg.OutputDir, "talk_summary.txt"),
+	}, outputs, "formats that were not requested are not outputs of the job")
+}
@@ -0,0 +1,87 @@
+package processor
+
+import (
+	"context"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/whisper"
+)
+
+// recordAudio stores the audio duration of filePath and the model it is
+// transcribed with (c, after profiles) for the queue ETA. The duration is
+// only read once per file.
+func recordAudio(c *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	jobStore *jobs.Store, debugMode bool, filePath string) {
+
+	job, ok := jobStore.Get(filePath)
+	if !ok {
+		return
+	}
+	audio := time.Duration(job.AudioSeconds * float64(time.Second))
+	if audio <= 0 {
+		var err error
+		if audio, err = whisper.AudioDuration(filePath); err != nil {
+			// Without a duration the file simply has no estimate
+			logger.LogDebug(log, logBuffer, logMutex, debugMode, "Audio duration unknown for %s: %v", filepath.Base(filePath), err)
+		}
+	}
+	if err := jobStore.SetAudio(filePath, audio, rtfKey(c)); err != nil {
+		logger.LogError(log, logBuffer, logMutex, "Failed to update job store: %v", err)
+	}
+}
+
+// rtfKey returns the statistics key of the backend and model c transcribes
+// with. whisper-ctranslate2 keeps the plain model/compute_type key.
+func rtfKey(c *config.Config) string {
+	key := jobs.RTFKey(c.WhisperModel, c.ComputeType)
+	if c.TranscriptionBackend != "" && c.TranscriptionBackend != config.BackendWhisperCTranslate2 {
+		key = c.TranscriptionBackend + ":" + key
+	}
+	return key
+}
+
+// recordQueuedAudio runs recordAudio for newly queued files with the
+// settings of their processing profiles. It stops early when ctx is done.
+func recordQueuedAudio(ctx context.Context, config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry,
+	logMutex *sync.RWMutex, jobStore *jobs.Store, debugMode bool, files []string) {
+
+	for _, file := range files {
+		if ctx.Err() != nil {
+			return
+		}
+		fileConfig, _, err := config.ResolveProfile(file)
+		if err != nil {
+			fileConfig = config // Reported when the file is processed
+		}
+		recordAudio(fileConfig, log, logBuffer, logMutex, jobStore, debugMode, file)
+	}
+}
+
+// recordRTF adds a finished transcription of filePath to the real-time
+// factor statistics of its model
+func recordRTF(log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	jobStore *jobs.Store, debugMode bool, filePath string, elapsed time.Duration) {
+
+	job, ok := jobStore.Get(filePath)
+	if !ok || job.AudioSeconds <= 0 {
+		return
+	}
+	audio := time.Duration(job.AudioSeconds * float64(time.Second))
+	if err := jobStore.RecordRTF(job.RTFKey, audio, elapsed); err != nil {
+		logger.LogError(log, logBuffer, logMutex, "Failed to update job store: %v", err)
+	}
+	logger.LogDebug(log, logBuffer, logMutex, debugMode, "Real-time factor for %s (%s): %.2f",
+		filepath.Base(filePath), job.RTFKey, elapsed.Seconds()/job.AudioSeconds)
+}
+
+// EstimateETA estimates the remaining time of the running and queued files
+// from their audio durations and the recorded real-time factors
+func EstimateETA(config *config.Config, jobStore *jobs.Store, processingFiles, queuedFiles []string) jobs.ETA {
+	return jobStore.EstimateETA(processingFiles, queuedFiles, maxConcurrentJobs(config), time.Now())
+}
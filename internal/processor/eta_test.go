@@ -0,0 +1,53 @@
+package processor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// silentWAV returns a 16 kHz mono 16-bit WAV file of the given length
+func silentWAV(seconds int) []byte {
+	data := seconds * 32000
+	header := []byte("RIFF\x00\x00\x00\x00WAVEfmt \x10\x00\x00\x00\x01\x00\x01\x00\x80\x3e\x00\x00\x00\x7d\x00\x00\x02\x00\x10\x00data")
+	header = append(header, byte(data), byte(data>>8), byte(data>>16), byte(data>>24))
+	return append(header, make([]byte, data)...)
+}
+
+func TestRecordAudioAndRTF(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.WhisperModel = "large-v3"
+	cfg.ComputeType = "int8"
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	store := jobs.NewMemoryStore()
+
+	path := filepath.Join(t.TempDir(), "memo.wav")
+	require.NoError(t, os.WriteFile(path, silentWAV(4), 0644))
+	claimed, err := store.Claim(path)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	recordQueuedAudio(context.Background(), cfg, nil, &logBuffer, &logMutex, store, false, []string{path})
+	job, _ := store.Get(path)
+	assert.Equal(t, 4.0, job.AudioSeconds)
+	assert.Equal(t, "large-v3/int8", job.RTFKey)
+
+	recordRTF(nil, &logBuffer, &logMutex, store, false, path, 2*time.Second)
+	stat, ok := store.RTF("large-v3/int8")
+	require.True(t, ok)
+	assert.Equal(t, 0.5, stat.Factor())
+
+	eta := EstimateETA(cfg, store, nil, []string{path})
+	assert.True(t, eta.TotalKnown)
+	assert.Equal(t, 2*time.Second, eta.Total)
+}
@@ -0,0 +1,120 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
+	"github.com/infoHiroki/KoeMoji-Go/internal/transcript"
+	"github.com/infoHiroki/KoeMoji-Go/internal/whisper"
+)
+
+// minHashPrefix is how many characters of a content hash select a job
+const minHashPrefix = 6
+
+// saveTranscript keeps the segments of filePath under the content hash of
+// its job, so that other formats can be exported later without transcribing
+// again. In-memory job stores keep nothing.
+func saveTranscript(c *config.Config, jobStore *jobs.Store, filePath string, segments []whisper.Segment) error {
+	dir := jobStore.TranscriptDir()
+	if dir == "" {
+		return nil
+	}
+	job, ok := jobStore.Get(filePath)
+	if !ok {
+		return fmt.Errorf("no job for %s", filepath.Base(filePath))
+	}
+	return transcript.Save(dir, job.Hash, whisper.NewTranscript(c, filePath, segments))
+}
+
+// FindJob returns the job ref names: its input path, its file name (with or
+// without extension) or the start of its content hash
+func FindJob(jobStore *jobs.Store, ref string) (jobs.Job, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return jobs.Job{}, fmt.Errorf("no job given")
+	}
+	if abs, err := filepath.Abs(ref); err == nil {
+		if job, ok := jobStore.Get(abs); ok {
+			return job, nil
+		}
+	}
+	if job, ok := jobStore.Get(ref); ok {
+		return job, nil
+	}
+
+	var matches []jobs.Job
+	for _, job := range jobStore.List() {
+		base := filepath.Base(job.Path)
+		if base == ref || strings.TrimSuffix(base, filepath.Ext(base)) == ref ||
+			(len(ref) >= minHashPrefix && strings.HasPrefix(job.Hash, strings.ToLower(ref))) {
+			matches = append(matches, job)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return jobs.Job{}, fmt.Errorf("no job matches %q", ref)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, job := range matches {
+		names[i] = fmt.Sprintf("%s (%s)", job.Path, shortHash(job.Hash))
+	}
+	return jobs.Job{}, fmt.Errorf("%q matches several jobs, use the path or hash:\n  %s", ref, strings.Join(names, "\n  "))
+}
+
+// shortHash returns the start of a content hash for messages
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// Export renders the stored segments of the job ref names in format. The
+// file is written to output, or next to the other outputs of the job when
+// output is empty. It returns the path written.
+func Export(c *config.Config, jobStore *jobs.Store, ref, format, output string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if !isExportFormat(format) {
+		return "", fmt.Errorf("unsupported format %q (available: %s)", format, strings.Join(transcript.Formats, ", "))
+	}
+	job, err := FindJob(jobStore, ref)
+	if err != nil {
+		return "", err
+	}
+	doc, err := transcript.Load(jobStore.TranscriptDir(), job.Hash)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("no stored segments for %s (transcribed with an older version?)", filepath.Base(job.Path))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if output == "" {
+		basename := strings.TrimSuffix(filepath.Base(job.Path), filepath.Ext(job.Path))
+		dir := c.MirrorDir(c.OutputDir, job.Path)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create output directory: %w", err)
+		}
+		output = filepath.Join(dir, basename+"."+format)
+	}
+	if err := transcript.WriteFile(output, format, doc, whisper.RenderOptions(c)); err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+// isExportFormat reports whether format is one of transcript.Formats
+func isExportFormat(format string) bool {
+	for _, f := range transcript.Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package processor
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/transcript"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(tempDir, "input")
+	cfg.OutputDir = filepath.Join(tempDir, "output")
+	cfg.ArchiveDir = filepath.Join(tempDir, "archive")
+	cfg.FailedDir = filepath.Join(tempDir, "failed")
+	cfg.TranscriptionBackend = config.BackendMock
+	cfg.OutputFormat = "txt"
+
+	require.NoError(t, os.MkdirAll(filepath.Join(cfg.InputDir, "clientA"), 0755))
+	audio := filepath.Join(cfg.InputDir, "clientA", "weekly.wav")
+	require.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
+
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	store, err := jobs.Open(filepath.Join(tempDir, "jobs.json"))
+	require.NoError(t, err)
+	_, err = store.Claim(audio)
+	require.NoError(t, err)
+
+	processFile(context.Background(), cfg, log.New(os.Stdout, "", log.LstdFlags), &logBuffer, &logMutex, store, false, audio)
+
+	job, _ := store.Get(audio)
+	require.Equal(t, jobs.StatusDone, job.Status)
+	assert.FileExists(t, transcript.Path(filepath.Join(tempDir, "transcripts"), job.Hash), "the segments are kept per content hash")
+	assert.NoFileExists(t, filepath.Join(cfg.OutputDir, "clientA", "weekly.vtt"))
+
+	// Other formats are rendered from the stored segments; the audio is archived by now
+	path, err := Export(cfg, store, "weekly", "vtt", "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(cfg.OutputDir, "clientA", "weekly.vtt"), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "WEBVTT\n\n00:00.000 --> "), string(data))
+
+	output := filepath.Join(tempDir, "notes.md")
+	path, err = Export(cfg, store, job.Hash[:8], "MD", output)
+	require.NoError(t, err)
+	assert.Equal(t, output, path)
+	data, err = os.ReadFile(output)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "# weekly\n\n**[00:00]** "), string(data))
+
+	_, err = Export(cfg, store, "weekly", "docx", "")
+	assert.Error(t, err)
+	_, err = Export(cfg, store, "monthly", "txt", "")
+	assert.Error(t, err)
+}
+
+func TestFindJob(t *testing.T) {
+	dir := t.TempDir()
+	store := jobs.NewMemoryStore()
+	var paths []string
+	for _, name := range []string{"a/memo.wav", "b/memo.mp3", "call.m4a"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+		_, err := store.Claim(path)
+		require.NoError(t, err)
+		paths = append(paths, path)
+	}
+
+	job, err := FindJob(store, "call")
+	require.NoError(t, err)
+	assert.Equal(t, paths[2], job.Path)
+
+	job, err = FindJob(store, "memo.mp3")
+	require.NoError(t, err)
+	assert.Equal(t, paths[1], job.Path)
+
+	job, err = FindJob(store, paths[0])
+	require.NoError(t, err)
+	assert.Equal(t, paths[0], job.Path)
+
+	job, err = FindJob(store, strings.ToUpper(job.Hash[:minHashPrefix]))
+	require.NoError(t, err)
+	assert.Equal(t, paths[0], job.Path)
+
+	_, err = FindJob(store, "memo")
+	assert.ErrorContains(t, err, "several jobs")
+	_, err = FindJob(store, job.Hash[:minHashPrefix-1])
+	assert.Error(t, err, "too short to be a hash")
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+// +build !windows
+
+package processor
+
+import (
+	"os"
+	"syscall"
+)
+
+// isFileLocked reports whether another process holds an exclusive advisory lock
+// on the file. The probe takes a shared lock so it never keeps a writer from
+// locking. Writers on macOS/Linux rarely lock, so the settle window does most
+// of the work here.
+func isFileLocked(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_SH|syscall.LOCK_NB); err != nil {
+		return err == syscall.EWOULDBLOCK
+	}
+	syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+	return false
+}
@@ -0,0 +1,41 @@
+//go:build !windows
+// +build !windows
+
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsFileLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.wav")
+	require.NoError(t, os.WriteFile(path, []byte("audio"), 0644))
+	assert.False(t, isFileLocked(path))
+
+	// Simulate a recorder holding an exclusive lock while writing
+	writer, err := os.OpenFile(path, os.O_WRONLY, 0)
+	require.NoError(t, err)
+	defer writer.Close()
+	require.NoError(t, syscall.Flock(int(writer.Fd()), syscall.LOCK_EX))
+	assert.True(t, isFileLocked(path))
+
+	require.NoError(t, syscall.Flock(int(writer.Fd()), syscall.LOCK_UN))
+	assert.False(t, isFileLocked(path))
+
+	// Readers holding a shared lock do not block processing
+	reader, err := os.Open(path)
+	require.NoError(t, err)
+	defer reader.Close()
+	require.NoError(t, syscall.Flock(int(reader.Fd()), syscall.LOCK_SH))
+	assert.False(t, isFileLocked(path))
+}
+
+func TestIsFileLocked_MissingFile(t *testing.T) {
+	assert.False(t, isFileLocked(filepath.Join(t.TempDir(), "missing.wav")))
+}
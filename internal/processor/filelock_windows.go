@@ -0,0 +1,28 @@
+//go:build windows
+// +build windows
+
+package processor
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// Win32 error codes returned when another process holds the file open without write sharing
+const (
+	errorSharingViolation syscall.Errno = 32
+	errorLockViolation    syscall.Errno = 33
+)
+
+// isFileLocked reports whether a writer still holds the file open.
+// Explorer copies and most recorders open the target without write sharing,
+// so opening it for writing fails until they are done.
+func isFileLocked(path string) bool {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return errors.Is(err, errorSharingViolation) || errors.Is(err, errorLockViolation)
+	}
+	file.Close()
+	return false
+}
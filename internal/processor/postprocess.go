@@ -0,0 +1,129 @@
+package processor
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/transcript"
+	"github.com/infoHiroki/KoeMoji-Go/internal/ui"
+	"github.com/infoHiroki/KoeMoji-Go/internal/whisper"
+)
+
+// postProcess runs the stages between the transcription and the summary:
+// the hallucination filter, then the replacement rules. When either changed
+// the segments the outputs are written again. It returns the segments the
+// stored transcript and the summary use.
+func postProcess(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	filePath string, segments []whisper.Segment) []whisper.Segment {
+
+	filtered, filterChanged := filterHallucinations(config, log, logBuffer, logMutex, filePath, segments)
+	replaced, replaceChanged := applyReplacements(config, log, logBuffer, logMutex, filePath, filtered)
+	if !filterChanged && !replaceChanged {
+		return segments
+	}
+	if err := whisper.RewriteOutputs(config, filePath, replaced); err != nil {
+		// The outputs of the backend are still there, unchanged
+		msg := ui.GetMessages(config)
+		logger.LogError(log, logBuffer, logMutex, msg.OutputRewriteFailed, filepath.Base(filePath), err)
+		return segments
+	}
+	return replaced
+}
+
+// filterHallucinations removes or flags (hallucination_filter) the segments
+// whisper most likely made up and reports whether it changed anything
+func filterHallucinations(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	filePath string, segments []whisper.Segment) ([]whisper.Segment, bool) {
+
+	opts, ok := hallucinationOptions(config)
+	if !ok {
+		return segments, false
+	}
+	filtered, report := transcript.FilterHallucinations(segments, opts)
+	if !report.Changed() {
+		return segments, false
+	}
+
+	fileName := filepath.Base(filePath)
+	msg := ui.GetMessages(config)
+	if report.Removed > 0 {
+		logger.LogInfo(log, logBuffer, logMutex, msg.HallucinationsRemoved, fileName, report.Removed, report.Total, report)
+	} else {
+		logger.LogInfo(log, logBuffer, logMutex, msg.HallucinationsFlagged, fileName, report.Flagged, report.Total, report)
+	}
+	return filtered, true
+}
+
+// hallucinationOptions returns the filter options of c, or false when the
+// filter is off
+func hallucinationOptions(c *config.Config) (transcript.FilterOptions, bool) {
+	switch c.HallucinationFilter {
+	case config.HallucinationFilterOff:
+		return transcript.FilterOptions{}, false
+	case config.HallucinationFilterDrop:
+		return transcript.FilterOptions{Phrases: c.HallucinationPhrases}, true
+	default:
+		// Unknown values never delete anything
+		return transcript.FilterOptions{Phrases: c.HallucinationPhrases, Flag: true}, true
+	}
+}
+
+// writeCleanTranscript writes the clean transcript (clean_transcript) next
+// to the verbatim one and returns its segments, or nil when it is off or
+// could not be written. The verbatim outputs are not touched.
+func writeCleanTranscript(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	filePath string, segments []whisper.Segment) []whisper.Segment {
+
+	if !config.CleanTranscript {
+		return nil
+	}
+	fileName := filepath.Base(filePath)
+	msg := ui.GetMessages(config)
+	clean := transcript.NewCleaner(config.FillerWords).Clean(segments)
+	outputs, err := whisper.WriteCleanOutputs(config, filePath, clean)
+	if err != nil {
+		logger.LogError(log, logBuffer, logMutex, msg.CleanTranscriptFailed, fileName, err)
+		return nil
+	}
+	names := make([]string, len(outputs))
+	for i, output := range outputs {
+		names[i] = filepath.Base(output)
+	}
+	logger.LogInfo(log, logBuffer, logMutex, msg.CleanTranscriptSaved, fileName, strings.Join(names, ", "))
+	return clean
+}
+
+// applyReplacements applies the rules of replacement_rules_file and reports
+// whether any segment changed. A broken rules file is logged and skipped;
+// the transcript is still good without it.
+func applyReplacements(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	filePath string, segments []whisper.Segment) ([]whisper.Segment, bool) {
+
+	if config.ReplacementRulesFile == "" {
+		return segments, false
+	}
+	msg := ui.GetMessages(config)
+	rules, err := transcript.LoadRules(config.ReplacementRulesFile)
+	if err == nil && len(rules) == 0 {
+		return segments, false
+	}
+	var replacer *transcript.Replacer
+	if err == nil {
+		replacer, err = transcript.NewReplacer(rules)
+	}
+	if err != nil {
+		logger.LogError(log, logBuffer, logMutex, msg.ReplacementsInvalid, err)
+		return segments, false
+	}
+
+	replaced, changed := replacer.Apply(segments)
+	if changed == 0 {
+		return segments, false
+	}
+	logger.LogInfo(log, logBuffer, logMutex, msg.ReplacementsApplied, filepath.Base(filePath), changed)
+	return replaced, true
+}
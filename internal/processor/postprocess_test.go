@@ -0,0 +1,132 @@
+package processor
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/transcript"
+	"github.com/infoHiroki/KoeMoji-Go/internal/whisper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func postProcessTestConfig(t *testing.T) *config.Config {
+	tempDir := t.TempDir()
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(tempDir, "input")
+	cfg.OutputDir = filepath.Join(tempDir, "output")
+	cfg.TranscriptionBackend = config.BackendMock
+	cfg.OutputFormats = []string{"txt"}
+	cfg.ReplacementRulesFile = filepath.Join(tempDir, "replacements.json")
+	require.NoError(t, os.MkdirAll(cfg.OutputDir, 0755))
+	return cfg
+}
+
+func TestPostProcess_Hallucinations(t *testing.T) {
+	cfg := postProcessTestConfig(t)
+	cfg.HallucinationFilter = config.HallucinationFilterDrop
+	audio := filepath.Join(cfg.InputDir, "meeting.wav")
+	outputFile := filepath.Join(cfg.OutputDir, "meeting.txt")
+
+	segments := []whisper.Segment{
+		{Start: 0, End: 3 * time.Second, Text: "会議を始めます"},
+		{Start: 3 * time.Second, End: 30 * time.Second, Text: "ご視聴ありがとうございました"},
+	}
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	logOut := log.New(os.Stdout, "", log.LstdFlags)
+
+	kept := postProcess(cfg, logOut, &logBuffer, &logMutex, audio, segments)
+	assert.Equal(t, segments[:1], kept)
+	data, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, "会議を始めます\n", string(data), "the outputs are written again without the phrase")
+	require.NotEmpty(t, logBuffer)
+	assert.Contains(t, logBuffer[len(logBuffer)-1].Message, "1/2", "the job log tells how many segments were removed")
+
+	cfg.HallucinationFilter = config.HallucinationFilterFlag
+	kept = postProcess(cfg, logOut, &logBuffer, &logMutex, audio, segments)
+	require.Len(t, kept, 2)
+	data, err = os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Equal(t, "会議を始めます\n[?] ご視聴ありがとうございました\n", string(data))
+	assert.Equal(t, "会議を始めます\n", segmentsText(kept), "the summary leaves flagged segments out")
+	assert.True(t, hasSuspect(kept))
+
+	cfg.HallucinationFilter = config.HallucinationFilterOff
+	require.NoError(t, os.Remove(outputFile))
+	assert.Equal(t, segments, postProcess(cfg, logOut, &logBuffer, &logMutex, audio, segments))
+	assert.NoFileExists(t, outputFile, "nothing is rewritten when nothing changed")
+
+	cfg.HallucinationFilter = config.HallucinationFilterDrop
+	kept = postProcess(cfg, logOut, &logBuffer, &logMutex, audio, segments[1:])
+	assert.Empty(t, kept)
+	data, err = os.ReadFile(outputFile)
+	require.NoError(t, err)
+	assert.Empty(t, strings.TrimSpace(string(data)), "silence leaves an empty transcript")
+}
+
+func TestPostProcess_Replacements(t *testing.T) {
+	cfg := postProcessTestConfig(t)
+	cfg.OutputFormats = []string{"srt"}
+	audio := filepath.Join(cfg.InputDir, "demo.wav")
+	require.NoError(t, transcript.SaveRules(cfg.ReplacementRulesFile, []transcript.Rule{
+		{From: "コエモジ", To: "KoeMoji"},
+		{From: "議事(禄|碌)", To: "議事録", Regex: true},
+	}))
+
+	segments := []whisper.Segment{
+		{Start: 1500 * time.Millisecond, End: 4 * time.Second, Text: "コエモジで議事禄を作ります"},
+		{Start: 4 * time.Second, End: 6 * time.Second, Text: "よろしくお願いします"},
+	}
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	replaced := postProcess(cfg, log.New(os.Stdout, "", log.LstdFlags), &logBuffer, &logMutex, audio, segments)
+	assert.Equal(t, "KoeMojiで議事録を作ります", replaced[0].Text)
+	assert.Equal(t, segments[1], replaced[1])
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "demo.srt"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "1\n00:00:01,500 --> 00:00:04,000\nKoeMojiで議事録を作ります\n"),
+		"replaced segments keep their times: %s", data)
+
+	// A broken rules file is reported, the transcript stays as it is
+	require.NoError(t, os.WriteFile(cfg.ReplacementRulesFile, []byte(`[{"from": "(", "regex": true}]`), 0644))
+	assert.Equal(t, segments, postProcess(cfg, log.New(os.Stdout, "", log.LstdFlags), &logBuffer, &logMutex, audio, segments))
+	assert.Equal(t, "ERROR", logBuffer[len(logBuffer)-1].Level)
+}
+
+func TestWriteCleanTranscript(t *testing.T) {
+	cfg := postProcessTestConfig(t)
+	cfg.OutputFormats = []string{"txt", "srt"}
+	audio := filepath.Join(cfg.InputDir, "meeting.wav")
+	segments := []whisper.Segment{
+		{Start: 0, End: 2 * time.Second, Text: "えー、"},
+		{Start: 2 * time.Second, End: 5 * time.Second, Text: "あのー、本日の議題は２つです"},
+	}
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	logOut := log.New(os.Stdout, "", log.LstdFlags)
+
+	assert.Nil(t, writeCleanTranscript(cfg, logOut, &logBuffer, &logMutex, audio, segments), "off by default")
+	assert.NoFileExists(t, filepath.Join(cfg.OutputDir, "meeting_clean.txt"))
+
+	cfg.CleanTranscript = true
+	clean := writeCleanTranscript(cfg, logOut, &logBuffer, &logMutex, audio, segments)
+	assert.Equal(t, []whisper.Segment{{Start: 2 * time.Second, End: 5 * time.Second, Text: "本日の議題は2つです。"}}, clean)
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "meeting_clean.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "本日の議題は2つです。\n", string(data))
+	data, err = os.ReadFile(filepath.Join(cfg.OutputDir, "meeting_clean.srt"))
+	require.NoError(t, err)
+	assert.Equal(t, "1\n00:00:02,000 --> 00:00:05,000\n本日の議題は2つです。\n\n", string(data))
+	assert.NoFileExists(t, filepath.Join(cfg.OutputDir, "meeting.txt"), "the verbatim outputs are left alone")
+	assert.Contains(t, logBuffer[len(logBuffer)-1].Message, "meeting_clean.txt, meeting_clean.srt")
+}
@@ -4,108 +4,334 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
 	"github.com/infoHiroki/KoeMoji-Go/internal/llm"
 	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
 	"github.com/infoHiroki/KoeMoji-Go/internal/ui"
 	"github.com/infoHiroki/KoeMoji-Go/internal/whisper"
 )
 
+// settleCheckInterval is how often files waiting to settle are checked again
+const settleCheckInterval = time.Second
+
 func StartProcessing(ctx context.Context, config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
-	lastScanTime *time.Time, queuedFiles *[]string, processingFile *string, isProcessing *bool,
-	processedFiles *map[string]bool, mu *sync.Mutex, wg *sync.WaitGroup, debugMode bool) {
+	lastScanTime *time.Time, queuedFiles *[]string, processingFiles *[]string, isProcessing *bool, schedule *ScheduleState,
+	jobStore *jobs.Store, mu *sync.Mutex, wg *sync.WaitGroup, debugMode bool) {
+
+	msg := ui.GetMessages(config)
+
+	if _, err := config.ProcessingWindows(); err != nil {
+		logger.LogError(log, logBuffer, logMutex, msg.ScheduleInvalid, err)
+	}
 
 	// Initial scan
-	ScanAndProcess(config, log, logBuffer, logMutex, lastScanTime, queuedFiles, processingFile,
-		isProcessing, processedFiles, mu, wg, debugMode)
+	ScanAndProcess(ctx, config, log, logBuffer, logMutex, lastScanTime, queuedFiles, processingFiles,
+		isProcessing, schedule, jobStore, mu, wg, debugMode)
+
+	// File system events pick up new files immediately. The periodic scan below
+	// keeps running as a fallback for missed events (e.g. on network shares).
+	var watcher *fsnotify.Watcher
+	var events <-chan fsnotify.Event
+	var watchErrors <-chan error
+	if watchEnabled(config) {
+		var err error
+		watcher, err = newInputWatcher(config)
+		if err != nil {
+			logger.LogError(log, logBuffer, logMutex, msg.WatcherFailed, err)
+		} else {
+			defer watcher.Close()
+			events, watchErrors = watcher.Events, watcher.Errors
+			logger.LogInfo(log, logBuffer, logMutex, msg.WatchingDir, config.InputDir, config.ScanIntervalMinutes)
+		}
+	}
 
 	// Periodic scan with context cancellation
 	ticker := time.NewTicker(time.Duration(config.ScanIntervalMinutes) * time.Minute)
 	defer ticker.Stop()
 
+	// Re-check files that are still being written, failed files waiting for a
+	// retry and queued files waiting for the processing window
+	settleTicker := time.NewTicker(settleCheckInterval)
+	defer settleTicker.Stop()
+
+	// Files reported by the watcher, queued once events have been quiet for watchDebounce
+	pending := make(map[string]bool)
+	var debounce <-chan time.Time
+
 	for {
 		select {
 		case <-ctx.Done():
 			logger.LogInfo(log, logBuffer, logMutex, "File processing stopped by context cancellation")
 			return
 		case <-ticker.C:
-			ScanAndProcess(config, log, logBuffer, logMutex, lastScanTime, queuedFiles, processingFile,
-				isProcessing, processedFiles, mu, wg, debugMode)
+			ScanAndProcess(ctx, config, log, logBuffer, logMutex, lastScanTime, queuedFiles, processingFiles,
+				isProcessing, schedule, jobStore, mu, wg, debugMode)
+		case <-settleTicker.C:
+			if waiting := jobStore.Waiting(); len(waiting) > 0 {
+				enqueueFiles(ctx, config, log, logBuffer, logMutex, waiting, queuedFiles, processingFiles,
+					isProcessing, schedule, jobStore, mu, wg, debugMode)
+			}
+			due, err := jobStore.DueRetries(time.Now())
+			if err != nil {
+				logger.LogError(log, logBuffer, logMutex, "Failed to update job store: %v", err)
+			}
+			if len(due) > 0 {
+				addToQueue(ctx, config, log, logBuffer, logMutex, due, queuedFiles, processingFiles,
+					isProcessing, schedule, jobStore, mu, wg, debugMode)
+			} else {
+				startWorkers(ctx, config, log, logBuffer, logMutex, queuedFiles, processingFiles,
+					isProcessing, schedule, jobStore, mu, wg, debugMode)
+			}
+		case ev, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if config.RecursiveScan && isNewDirEvent(ev) {
+				// Watch the new subfolder and pick up files that came with it
+				logger.LogDebug(log, logBuffer, logMutex, debugMode, "Folder event: %s", ev)
+				for _, file := range watchSubdirs(watcher, config, ev.Name) {
+					if ui.IsAudioFile(file) {
+						pending[file] = true
+					}
+				}
+				debounce = time.After(watchDebounce)
+			} else if isAudioEvent(ev) {
+				logger.LogDebug(log, logBuffer, logMutex, debugMode, "File event: %s", ev)
+				pending[ev.Name] = true
+				debounce = time.After(watchDebounce)
+			}
+		case err, ok := <-watchErrors:
+			if !ok {
+				watchErrors = nil
+				continue
+			}
+			logger.LogError(log, logBuffer, logMutex, "File watcher error: %v", err)
+		case <-debounce:
+			debounce = nil
+			files := make([]string, 0, len(pending))
+			for file := range pending {
+				files = append(files, file)
+			}
+			pending = make(map[string]bool)
+			sort.Strings(files)
+			enqueueFiles(ctx, config, log, logBuffer, logMutex, files, queuedFiles, processingFiles,
+				isProcessing, schedule, jobStore, mu, wg, debugMode)
 		}
 	}
 }
 
-func ScanAndProcess(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
-	lastScanTime *time.Time, queuedFiles *[]string, processingFile *string, isProcessing *bool,
-	processedFiles *map[string]bool, mu *sync.Mutex, wg *sync.WaitGroup, debugMode bool) {
+func ScanAndProcess(ctx context.Context, config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	lastScanTime *time.Time, queuedFiles *[]string, processingFiles *[]string, isProcessing *bool, schedule *ScheduleState,
+	jobStore *jobs.Store, mu *sync.Mutex, wg *sync.WaitGroup, debugMode bool) {
 
 	*lastScanTime = time.Now()
 	msg := ui.GetMessages(config)
 	logger.LogInfo(log, logBuffer, logMutex, msg.ScanningDir)
 
-	files, err := filepath.Glob(filepath.Join(config.InputDir, "*"))
+	files, err := listInputFiles(config)
 	if err != nil {
 		logger.LogError(log, logBuffer, logMutex, "Failed to scan input directory: %v", err)
 		return
 	}
 
-	newFiles := filterNewAudioFiles(files, processedFiles, mu)
+	enqueueFiles(ctx, config, log, logBuffer, logMutex, files, queuedFiles, processingFiles,
+		isProcessing, schedule, jobStore, mu, wg, debugMode)
+}
+
+// listInputFiles returns the entries of the input folder, including files in
+// subfolders when recursive_scan is enabled
+func listInputFiles(config *config.Config) ([]string, error) {
+	if !config.RecursiveScan {
+		return filepath.Glob(filepath.Join(config.InputDir, "*"))
+	}
+
+	var files []string
+	err := filepath.WalkDir(config.InputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == config.InputDir {
+				return err
+			}
+			return nil // Skip unreadable subfolders and keep scanning the rest
+		}
+		if d.IsDir() {
+			if path != config.InputDir && skipInputDir(config, path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	return files, err
+}
+
+// skipInputDir reports whether a subfolder of the input folder is left out of
+// recursive scans: hidden folders, and the output/archive/failed folders when
+// they are placed inside the input folder
+func skipInputDir(config *config.Config, dir string) bool {
+	if strings.HasPrefix(filepath.Base(dir), ".") {
+		return true
+	}
+	for _, other := range []string{config.OutputDir, config.ArchiveDir, config.FailedDir} {
+		if other != "" && samePath(dir, other) {
+			return true
+		}
+	}
+	return false
+}
+
+// samePath reports whether a and b refer to the same location
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
+
+// enqueueFiles queues the audio files among files that the job store claims
+// and starts workers for them. Used by both the periodic scan and the watcher.
+func enqueueFiles(ctx context.Context, config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	files []string, queuedFiles *[]string, processingFiles *[]string, isProcessing *bool, schedule *ScheduleState,
+	jobStore *jobs.Store, mu *sync.Mutex, wg *sync.WaitGroup, debugMode bool) {
+
+	newFiles := filterNewAudioFiles(files, jobStore, fileSettleWindow(config), log, logBuffer, logMutex)
 	if len(newFiles) == 0 {
 		logger.LogDebug(log, logBuffer, logMutex, debugMode, "No new files found")
 		return
 	}
 
+	msg := ui.GetMessages(config)
 	logger.LogInfo(log, logBuffer, logMutex, msg.FoundFiles, len(newFiles))
 
-	// Add files to queue
+	addToQueue(ctx, config, log, logBuffer, logMutex, newFiles, queuedFiles, processingFiles,
+		isProcessing, schedule, jobStore, mu, wg, debugMode)
+}
+
+// addToQueue appends already claimed files to the queue and starts workers for them
+func addToQueue(ctx context.Context, config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	files []string, queuedFiles *[]string, processingFiles *[]string, isProcessing *bool, schedule *ScheduleState,
+	jobStore *jobs.Store, mu *sync.Mutex, wg *sync.WaitGroup, debugMode bool) {
+
 	mu.Lock()
-	*queuedFiles = append(*queuedFiles, newFiles...)
+	*queuedFiles = append(*queuedFiles, files...)
+	mu.Unlock()
 
-	// Phase 1: Periodic cleanup of processed files map
-	if len(*processedFiles) > 5000 {
-		cleanupProcessedFiles(processedFiles, mu, log, logBuffer, logMutex)
+	startWorkers(ctx, config, log, logBuffer, logMutex, queuedFiles, processingFiles,
+		isProcessing, schedule, jobStore, mu, wg, debugMode)
+
+	// Audio durations for the queue ETA. Reading them may run ffprobe for
+	// every file, so neither the workers nor the caller wait for them.
+	if wg != nil {
+		wg.Add(1)
 	}
-	mu.Unlock()
+	go func() {
+		if wg != nil {
+			defer wg.Done()
+		}
+		recordQueuedAudio(ctx, config, log, logBuffer, logMutex, jobStore, debugMode, files)
+	}()
+}
+
+// maxConcurrentJobs returns the worker pool size (at least 1)
+func maxConcurrentJobs(config *config.Config) int {
+	if config.MaxConcurrentJobs < 1 {
+		return 1
+	}
+	return config.MaxConcurrentJobs
+}
+
+// startWorkers launches workers until the queue is empty or max_concurrent_jobs is reached.
+// Each worker is handed its first file here, so while mu is not held
+// len(*processingFiles) always equals the number of running workers.
+// Nothing is started once ctx is cancelled; queued jobs are resumed on the next start.
+// Outside processing_schedule the files stay queued until the next window.
+func startWorkers(ctx context.Context, config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	queuedFiles *[]string, processingFiles *[]string, isProcessing *bool, schedule *ScheduleState, jobStore *jobs.Store,
+	mu *sync.Mutex, wg *sync.WaitGroup, debugMode bool) {
 
-	// Start processing if not already processing (with proper locking)
 	mu.Lock()
-	if !*isProcessing {
-		*isProcessing = true
+	allowed := scheduleAllows(config, schedule, time.Now())
+	if !allowed && len(*queuedFiles) > 0 {
+		queued := len(*queuedFiles)
 		mu.Unlock()
+		logScheduleDeferred(config, log, logBuffer, logMutex, schedule, queued)
+		return
+	}
+	defer mu.Unlock()
+	if len(*queuedFiles) > 0 {
+		schedule.deferred.Store(false)
+	}
+
+	for ctx.Err() == nil && len(*queuedFiles) > 0 && len(*processingFiles) < maxConcurrentJobs(config) {
+		filePath := (*queuedFiles)[0]
+		*queuedFiles = (*queuedFiles)[1:]
+		*processingFiles = append(*processingFiles, filePath)
+
 		if wg != nil {
 			wg.Add(1)
 		}
-		go processQueue(config, log, logBuffer, logMutex, queuedFiles, processingFile,
-			isProcessing, mu, wg, debugMode)
-	} else {
-		mu.Unlock()
+		go processQueue(ctx, config, log, logBuffer, logMutex, filePath, queuedFiles, processingFiles,
+			isProcessing, schedule, jobStore, mu, wg, debugMode)
 	}
+	*isProcessing = len(*processingFiles) > 0
 }
 
-func filterNewAudioFiles(files []string, processedFiles *map[string]bool, mu *sync.Mutex) []string {
-	mu.Lock()
-	defer mu.Unlock()
+// fileSettleWindow returns how long a new file must stay unchanged before it is queued
+func fileSettleWindow(config *config.Config) time.Duration {
+	if config.FileSettleSeconds < 0 {
+		return 0
+	}
+	return time.Duration(config.FileSettleSeconds) * time.Second
+}
+
+// filterNewAudioFiles returns the audio files the job store claims for queuing.
+// Files that changed within the settle window or are still locked by a writer
+// are left waiting and checked again later.
+func filterNewAudioFiles(files []string, jobStore *jobs.Store, settle time.Duration, log *log.Logger,
+	logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex) []string {
 
 	var newFiles []string
 	for _, file := range files {
-		if ui.IsAudioFile(file) && !(*processedFiles)[file] {
-			(*processedFiles)[file] = true
+		if !ui.IsAudioFile(file) {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if !jobStore.Settled(file, info, settle) || isFileLocked(file) {
+			continue
+		}
+		claimed, err := jobStore.Claim(file)
+		if err != nil {
+			logger.LogError(log, logBuffer, logMutex, "Failed to register job for %s: %v", filepath.Base(file), err)
+		}
+		if claimed {
 			newFiles = append(newFiles, file)
 		}
 	}
 	return newFiles
 }
 
-func processQueue(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
-	queuedFiles *[]string, processingFile *string, isProcessing *bool, mu *sync.Mutex,
-	wg *sync.WaitGroup, debugMode bool) {
+// processQueue is one worker of the pool. It processes filePath and then keeps
+// taking files from the shared queue until it is empty.
+func processQueue(ctx context.Context, config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	filePath string, queuedFiles *[]string, processingFiles *[]string, isProcessing *bool, schedule *ScheduleState, jobStore *jobs.Store,
+	mu *sync.Mutex, wg *sync.WaitGroup, debugMode bool) {
 
 	defer func() {
 		if wg != nil {
@@ -114,70 +340,237 @@ func processQueue(config *config.Config, log *log.Logger, logBuffer *[]logger.Lo
 	}()
 
 	for {
+		processFile(ctx, config, log, logBuffer, logMutex, jobStore, debugMode, filePath)
+		held := jobStore.ReleaseHash(filePath)
+
 		mu.Lock()
-		if len(*queuedFiles) == 0 {
-			*isProcessing = false
-			*processingFile = ""
+		*processingFiles = removeFile(*processingFiles, filePath)
+		// Duplicates held back while filePath was processed go first
+		*queuedFiles = append(held, *queuedFiles...)
+
+		// "Process now" ends once everything queued has been processed
+		if len(*queuedFiles) == 0 && len(*processingFiles) == 0 {
+			schedule.processNow.Store(false)
+		}
+
+		// Stop when the queue is drained, the pool was shrunk in the settings,
+		// the processing window closed or the application is shutting down
+		if ctx.Err() != nil || len(*queuedFiles) == 0 || len(*processingFiles) >= maxConcurrentJobs(config) ||
+			!scheduleAllows(config, schedule, time.Now()) {
+			*isProcessing = len(*processingFiles) > 0
 			mu.Unlock()
 			return
 		}
 
 		// Get next file from queue
-		filePath := (*queuedFiles)[0]
+		filePath = (*queuedFiles)[0]
 		*queuedFiles = (*queuedFiles)[1:]
-		*processingFile = filepath.Base(filePath)
+		*processingFiles = append(*processingFiles, filePath)
 		*isProcessing = true
 		mu.Unlock()
+	}
+}
 
-		// Process the file
-		msg := ui.GetMessages(config)
-		logger.LogProc(log, logBuffer, logMutex, msg.ProcessingFile, *processingFile)
-		startTime := time.Now()
+// processFile transcribes a single file, generates its summary and archives it.
+// The transcription can be cancelled by the user through CancelFile. When ctx
+// is cancelled (shutdown) the job is put back to queued for the next start.
+func processFile(ctx context.Context, config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	jobStore *jobs.Store, debugMode bool, filePath string) {
 
-		if err := whisper.TranscribeAudio(config, log, logBuffer, logMutex, debugMode, filePath); err != nil {
-			logger.LogError(log, logBuffer, logMutex, msg.ProcessFailed, *processingFile, err)
-		} else {
-			duration := time.Since(startTime)
-			logger.LogDone(log, logBuffer, logMutex, msg.ProcessComplete, *processingFile, formatDuration(duration))
+	// Content that is being or was already transcribed under another name
+	if holdDuplicate(config, log, logBuffer, logMutex, jobStore, filePath) ||
+		handleDuplicate(config, log, logBuffer, logMutex, jobStore, filePath) {
+		return
+	}
 
-			// Generate summary if enabled
-			if config.LLMSummaryEnabled {
-				if err := generateSummary(config, log, logBuffer, logMutex, debugMode, filePath); err != nil {
-					logger.LogError(log, logBuffer, logMutex, "Summary generation failed for %s: %v", *processingFile, err)
-				}
-			}
+	fileName := filepath.Base(filePath)
+	msg := ui.GetMessages(config)
+	logger.LogProc(log, logBuffer, logMutex, msg.ProcessingFile, fileName)
+	startTime := time.Now()
+	if err := jobStore.MarkProcessing(filePath); err != nil {
+		logger.LogError(log, logBuffer, logMutex, "Failed to update job store: %v", err)
+	}
 
-			// Move to archive
-			msg2 := ui.GetMessages(config)
-			logger.LogProc(log, logBuffer, logMutex, msg2.MovingToArchive, *processingFile)
-			if err := moveToArchive(config, filePath); err != nil {
-				logger.LogError(log, logBuffer, logMutex, msg.ProcessFailed, *processingFile, err)
-			}
+	// Per-folder profiles may change the model, language and summary settings
+	profileConfig, profileName, err := config.ResolveProfile(filePath)
+	if err != nil {
+		logger.LogError(log, logBuffer, logMutex, msg.ProfileInvalid, fileName, err)
+		// The file itself is fine; it is processed again once the profile is fixed and the file saved again
+		handleFailure(config, log, logBuffer, logMutex, jobStore, filePath,
+			&whisper.TranscribeError{Kind: whisper.ErrorKindSetup, Err: err})
+		return
+	}
+	if profileName != "" {
+		logger.LogInfo(log, logBuffer, logMutex, msg.ProfileApplied, profileName, fileName,
+			profileConfig.WhisperModel, profileConfig.Language)
+	}
+	if err := jobStore.SetProfile(filePath, profileName); err != nil {
+		logger.LogError(log, logBuffer, logMutex, "Failed to update job store: %v", err)
+	}
+	recordAudio(profileConfig, log, logBuffer, logMutex, jobStore, debugMode, filePath)
+
+	// transcription_backend (profiles may select another backend)
+	transcriber, err := whisper.NewTranscriber(profileConfig)
+	if err != nil {
+		logger.LogError(log, logBuffer, logMutex, msg.ProcessFailed, fileName, err)
+		handleFailure(config, log, logBuffer, logMutex, jobStore, filePath, err)
+		return
+	}
+	transcribeStart := time.Now()
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Time suspended while recording or by max_cpu_percent is not part of the RTF
+	jobCtx, suspended := whisper.WithSuspendClock(jobCtx)
+	jobStore.SetRunning(filePath, cancel)
+	defer jobStore.ClearRunning(filePath)
+
+	result, err := transcriber.Transcribe(jobCtx, log, logBuffer, logMutex, debugMode, filePath)
+	if err != nil {
+		var storeErr error
+		switch {
+		case ctx.Err() != nil:
+			// 終了処理による中断: 次回起動時に再処理する
+			logger.LogInfo(log, logBuffer, logMutex, msg.ProcessInterrupted, fileName)
+			storeErr = jobStore.Requeue(filePath)
+		case jobCtx.Err() != nil:
+			logger.LogInfo(log, logBuffer, logMutex, msg.ProcessCancelled, fileName)
+			storeErr = jobStore.MarkCancelled(filePath)
+		default:
+			logger.LogError(log, logBuffer, logMutex, msg.ProcessFailed, fileName, err)
+			handleFailure(config, log, logBuffer, logMutex, jobStore, filePath, err)
+		}
+		if storeErr != nil {
+			logger.LogError(log, logBuffer, logMutex, "Failed to update job store: %v", storeErr)
+		}
+		return
+	}
+
+	logger.LogDebug(log, logBuffer, logMutex, debugMode, "%s: %d segments (%s)", fileName, len(result.Segments), transcriber.Name())
+	segments := postProcess(profileConfig, log, logBuffer, logMutex, filePath, result.Segments)
+	clean := writeCleanTranscript(profileConfig, log, logBuffer, logMutex, filePath, segments)
+	if err := saveTranscript(profileConfig, jobStore, filePath, segments); err != nil {
+		logger.LogError(log, logBuffer, logMutex, "Failed to store the segments of %s: %v", fileName, err)
+	}
+	recordRTF(log, logBuffer, logMutex, jobStore, debugMode, filePath, time.Since(transcribeStart)-suspended.Total())
+	duration := time.Since(startTime)
+	logger.LogDone(log, logBuffer, logMutex, msg.ProcessComplete, fileName, formatDuration(duration))
+
+	// Generate summary if enabled
+	if profileConfig.LLMSummaryEnabled {
+		summarySegments, fromClean := segments, clean != nil && profileConfig.SummaryFromClean()
+		if fromClean {
+			summarySegments = clean
+		}
+		if err := generateSummary(profileConfig, log, logBuffer, logMutex, debugMode, filePath, summarySegments, fromClean); err != nil {
+			logger.LogError(log, logBuffer, logMutex, "Summary generation failed for %s: %v", fileName, err)
+		}
+	}
+
+	// Move to archive
+	logger.LogProc(log, logBuffer, logMutex, msg.MovingToArchive, fileName)
+	if err := moveToArchive(config, filePath); err != nil {
+		logger.LogError(log, logBuffer, logMutex, msg.ProcessFailed, fileName, err)
+	}
+	if err := jobStore.MarkDoneWithOutputs(filePath, existingOutputs(config, filePath)); err != nil {
+		logger.LogError(log, logBuffer, logMutex, "Failed to update job store: %v", err)
+	}
+}
+
+// CancelFile stops the running transcription of filePath. The file stays in
+// the input folder and is not picked up again until the next start.
+// It reports false when filePath is not being processed.
+func CancelFile(jobStore *jobs.Store, filePath string) bool {
+	return jobStore.CancelRunning(filePath)
+}
+
+// RemoveFromQueue takes filePath out of the queue before it is processed.
+// Like a cancelled job the file stays in place until the next start.
+// It reports false when filePath is not queued.
+func RemoveFromQueue(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	queuedFiles *[]string, jobStore *jobs.Store, mu *sync.Mutex, filePath string) bool {
+
+	mu.Lock()
+	before := len(*queuedFiles)
+	*queuedFiles = removeFile(*queuedFiles, filePath)
+	removed := len(*queuedFiles) < before
+	mu.Unlock()
+
+	if !removed {
+		return false
+	}
+
+	msg := ui.GetMessages(config)
+	logger.LogInfo(log, logBuffer, logMutex, msg.RemovedFromQueue, filepath.Base(filePath))
+	if err := jobStore.MarkCancelled(filePath); err != nil {
+		logger.LogError(log, logBuffer, logMutex, "Failed to update job store: %v", err)
+	}
+	return true
+}
+
+// PauseForRecording suspends running and newly started transcriptions while
+// recording is true and pause_while_recording is enabled, and resumes them
+// otherwise. UIs call it whenever the recording state may have changed.
+func PauseForRecording(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	recording bool) {
+
+	paused := recording && config.PauseWhileRecording
+	if !whisper.SetPaused(paused) {
+		return
+	}
+
+	msg := ui.GetMessages(config)
+	if paused {
+		logger.LogInfo(log, logBuffer, logMutex, msg.ProcessingPaused)
+	} else {
+		logger.LogInfo(log, logBuffer, logMutex, msg.ProcessingResumed)
+	}
+}
+
+// removeFile returns files without the first occurrence of filePath
+func removeFile(files []string, filePath string) []string {
+	for i, f := range files {
+		if f == filePath {
+			return append(files[:i:i], files[i+1:]...)
 		}
 	}
+	return files
 }
 
+// moveToArchive moves a processed file to the archive, keeping its subfolder
 func moveToArchive(config *config.Config, sourcePath string) error {
-	filename := filepath.Base(sourcePath)
-	destPath := filepath.Join(config.ArchiveDir, filename)
+	archiveDir := config.MirrorDir(config.ArchiveDir, sourcePath)
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return err
+	}
+	destPath := uniqueDestPath(archiveDir, filepath.Base(sourcePath))
+
+	if err := os.Rename(sourcePath, destPath); err != nil {
+		return err
+	}
+	return nil
+}
+
+// uniqueDestPath returns dir/filename, adding a timestamp when that file already exists
+func uniqueDestPath(dir, filename string) string {
+	destPath := filepath.Join(dir, filename)
 
 	// Handle duplicate filenames
 	if _, err := os.Stat(destPath); err == nil {
 		timestamp := time.Now().Format("20060102_150405")
 		ext := filepath.Ext(filename)
 		name := strings.TrimSuffix(filename, ext)
-		destPath = filepath.Join(config.ArchiveDir, fmt.Sprintf("%s_%s%s", name, timestamp, ext))
-	}
-
-	if err := os.Rename(sourcePath, destPath); err != nil {
-		return err
+		destPath = filepath.Join(dir, fmt.Sprintf("%s_%s%s", name, timestamp, ext))
 	}
-	return nil
+	return destPath
 }
 
 func EnsureDirectories(config *config.Config, log *log.Logger) error {
-	dirs := []string{config.InputDir, config.OutputDir, config.ArchiveDir}
+	dirs := []string{config.InputDir, config.OutputDir, config.ArchiveDir, config.FailedDir}
 	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
 		if err := os.MkdirAll(dir, 0755); err != nil {
 			msg := ui.GetMessages(config)
 			log.Printf("[ERROR] "+msg.DirCreateError, dir, err)
@@ -188,11 +581,17 @@ func EnsureDirectories(config *config.Config, log *log.Logger) error {
 }
 
 func generateSummary(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry,
-	logMutex *sync.RWMutex, debugMode bool, originalFilePath string) error {
+	logMutex *sync.RWMutex, debugMode bool, originalFilePath string, segments []whisper.Segment, clean bool) error {
 
-	// Find the corresponding transcription file
+	// Find the corresponding transcription file, the clean one when the
+	// summary is made from it (summary_source)
 	basename := strings.TrimSuffix(filepath.Base(originalFilePath), filepath.Ext(originalFilePath))
-	transcriptionFile := filepath.Join(config.OutputDir, basename+"."+config.OutputFormat)
+	outputDir := config.MirrorDir(config.OutputDir, originalFilePath)
+	sourceName := basename
+	if clean {
+		sourceName += whisper.CleanOutputSuffix
+	}
+	transcriptionFile := summarySource(config, outputDir, sourceName)
 
 	// Check if transcription file exists
 	if _, err := os.Stat(transcriptionFile); os.IsNotExist(err) {
@@ -201,11 +600,16 @@ func generateSummary(config *config.Config, log *log.Logger, logBuffer *[]logger
 
 	logger.LogProc(log, logBuffer, logMutex, "Generating summary for %s...", basename)
 
-	// Read transcription content
+	// Read transcription content. Without a txt output, or when it has
+	// segments flagged as hallucinations, the plain text is taken from the
+	// segments instead.
 	content, err := readTranscriptionFile(transcriptionFile)
 	if err != nil {
 		return fmt.Errorf("failed to read transcription file: %w", err)
 	}
+	if (filepath.Ext(transcriptionFile) != ".txt" || hasSuspect(segments)) && len(segments) > 0 {
+		content = segmentsText(segments)
+	}
 
 	// Generate summary using LLM
 	summary, err := llm.SummarizeText(config, log, logBuffer, logMutex, debugMode, content)
@@ -214,7 +618,7 @@ func generateSummary(config *config.Config, log *log.Logger, logBuffer *[]logger
 	}
 
 	// Save summary to file
-	summaryFile := filepath.Join(config.OutputDir, basename+"_summary.txt")
+	summaryFile := filepath.Join(outputDir, basename+"_summary.txt")
 	if err := saveSummaryFile(summaryFile, summary); err != nil {
 		return fmt.Errorf("failed to save summary: %w", err)
 	}
@@ -223,6 +627,40 @@ func generateSummary(config *config.Config, log *log.Logger, logBuffer *[]logger
 	return nil
 }
 
+// summarySource returns the transcript the summary is made from: the txt
+// output when txt is one of the output formats, otherwise the first format
+func summarySource(c *config.Config, outputDir, basename string) string {
+	formats := c.Formats()
+	for _, format := range formats {
+		if format == "txt" {
+			return filepath.Join(outputDir, basename+".txt")
+		}
+	}
+	return filepath.Join(outputDir, basename+"."+formats[0])
+}
+
+// segmentsText returns the recognized text, one segment per line, without
+// the segments flagged as hallucinations
+func segmentsText(segments []whisper.Segment) string {
+	lines := make([]string, 0, len(segments))
+	for _, s := range segments {
+		if s.Suspect == "" {
+			lines = append(lines, s.Text)
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// hasSuspect reports whether the hallucination filter flagged any segment
+func hasSuspect(segments []whisper.Segment) bool {
+	for _, s := range segments {
+		if s.Suspect != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func readTranscriptionFile(filePath string) (string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
@@ -262,30 +700,3 @@ func formatDuration(d time.Duration) string {
 		return fmt.Sprintf("%ds", seconds)
 	}
 }
-
-// Phase 1: Helper function for processed files cleanup
-func cleanupProcessedFiles(processedFiles *map[string]bool, mu *sync.Mutex, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex) {
-	// Keep only the most recent 2500 entries (half of the threshold)
-	if len(*processedFiles) <= 2500 {
-		return
-	}
-
-	// Note: This function is called while mu is already locked in ScanAndProcess
-	// No additional locking needed here as the caller already holds the lock
-
-	// Simple approach: reset the map when it gets too large
-	// In a production system, you might want to keep recent entries based on timestamp
-	newMap := make(map[string]bool)
-	count := 0
-
-	// Keep approximately half of the entries
-	for file := range *processedFiles {
-		if count < 2500 {
-			newMap[file] = true
-			count++
-		}
-	}
-
-	*processedFiles = newMap
-	logger.LogInfo(log, logBuffer, logMutex, "Cleaned up processed files map, kept %d entries", len(*processedFiles))
-}
@@ -1,7 +1,7 @@
 package processor
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"os"
 	"path/filepath"
@@ -11,8 +11,11 @@ import (
 	"time"
 
 	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
 	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/whisper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestEnsureDirectories(t *testing.T) {
@@ -21,6 +24,7 @@ func TestEnsureDirectories(t *testing.T) {
 	cfg.InputDir = filepath.Join(tempDir, "input")
 	cfg.OutputDir = filepath.Join(tempDir, "output")
 	cfg.ArchiveDir = filepath.Join(tempDir, "archive")
+	cfg.FailedDir = filepath.Join(tempDir, "failed")
 
 	logger := log.New(os.Stdout, "", log.LstdFlags)
 
@@ -29,6 +33,7 @@ func TestEnsureDirectories(t *testing.T) {
 	assert.DirExists(t, cfg.InputDir)
 	assert.DirExists(t, cfg.OutputDir)
 	assert.DirExists(t, cfg.ArchiveDir)
+	assert.DirExists(t, cfg.FailedDir)
 }
 
 func TestEnsureDirectories_AlreadyExists(t *testing.T) {
@@ -37,6 +42,7 @@ func TestEnsureDirectories_AlreadyExists(t *testing.T) {
 	cfg.InputDir = filepath.Join(tempDir, "input")
 	cfg.OutputDir = filepath.Join(tempDir, "output")
 	cfg.ArchiveDir = filepath.Join(tempDir, "archive")
+	cfg.FailedDir = filepath.Join(tempDir, "failed")
 
 	// Pre-create directories
 	err := os.MkdirAll(cfg.InputDir, 0755)
@@ -56,79 +62,158 @@ func TestEnsureDirectories_AlreadyExists(t *testing.T) {
 	assert.DirExists(t, cfg.ArchiveDir)
 }
 
+// createTestFiles creates empty files in dir and returns their paths
+func createTestFiles(t *testing.T, dir string, names ...string) []string {
+	var paths []string
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		err := os.WriteFile(path, []byte("content of "+name), 0644)
+		assert.NoError(t, err)
+		paths = append(paths, path)
+	}
+	return paths
+}
+
 func TestFilterNewAudioFiles_EmptyInput(t *testing.T) {
-	processedFiles := make(map[string]bool)
-	var mu sync.Mutex
+	store := jobs.NewMemoryStore()
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
 
-	result := filterNewAudioFiles([]string{}, &processedFiles, &mu)
+	result := filterNewAudioFiles([]string{}, store, 0, nil, &logBuffer, &logMutex)
 
 	assert.Empty(t, result)
-	assert.Empty(t, processedFiles)
+	assert.Empty(t, store.List())
 }
 
 func TestFilterNewAudioFiles_NonAudioFiles(t *testing.T) {
-	processedFiles := make(map[string]bool)
-	var mu sync.Mutex
+	store := jobs.NewMemoryStore()
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
 
-	files := []string{
+	files := createTestFiles(t, t.TempDir(),
 		"test.txt",
 		"document.pdf",
 		"image.jpg",
 		"script.sh",
-	}
+	)
 
-	result := filterNewAudioFiles(files, &processedFiles, &mu)
+	result := filterNewAudioFiles(files, store, 0, nil, &logBuffer, &logMutex)
 
 	assert.Empty(t, result)
+	assert.Empty(t, store.List())
 }
 
 func TestFilterNewAudioFiles_ValidAudioFiles(t *testing.T) {
-	processedFiles := make(map[string]bool)
-	var mu sync.Mutex
+	store := jobs.NewMemoryStore()
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
 
-	files := []string{
+	files := createTestFiles(t, t.TempDir(),
 		"audio1.wav",
 		"audio2.mp3",
 		"audio3.m4a",
 		"document.txt", // Should be filtered out
-	}
+	)
 
-	result := filterNewAudioFiles(files, &processedFiles, &mu)
+	result := filterNewAudioFiles(files, store, 0, nil, &logBuffer, &logMutex)
 
 	// Should only include audio files
 	assert.Len(t, result, 3)
-	assert.Contains(t, result, "audio1.wav")
-	assert.Contains(t, result, "audio2.mp3")
-	assert.Contains(t, result, "audio3.m4a")
-
-	// All audio files should be marked as processed
-	assert.True(t, processedFiles["audio1.wav"])
-	assert.True(t, processedFiles["audio2.mp3"])
-	assert.True(t, processedFiles["audio3.m4a"])
-	assert.False(t, processedFiles["document.txt"])
+	assert.Contains(t, result, files[0])
+	assert.Contains(t, result, files[1])
+	assert.Contains(t, result, files[2])
+
+	// All audio files should be registered as queued jobs
+	for _, file := range files[:3] {
+		job, ok := store.Get(file)
+		assert.True(t, ok)
+		assert.Equal(t, jobs.StatusQueued, job.Status)
+		assert.NotEmpty(t, job.Hash)
+	}
+	_, ok := store.Get(files[3])
+	assert.False(t, ok)
 }
 
 func TestFilterNewAudioFiles_AlreadyProcessed(t *testing.T) {
-	processedFiles := map[string]bool{
-		"audio1.wav": true,
-		"audio2.mp3": true,
-	}
-	var mu sync.Mutex
+	store := jobs.NewMemoryStore()
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
 
-	files := []string{
+	files := createTestFiles(t, t.TempDir(),
 		"audio1.wav", // Already processed
 		"audio2.mp3", // Already processed
 		"audio3.m4a", // New file
-	}
+	)
 
-	result := filterNewAudioFiles(files, &processedFiles, &mu)
+	first := filterNewAudioFiles(files[:2], store, 0, nil, &logBuffer, &logMutex)
+	assert.Len(t, first, 2)
+	assert.NoError(t, store.MarkDone(files[0]))
+	assert.NoError(t, store.MarkDone(files[1]))
+
+	result := filterNewAudioFiles(files, store, 0, nil, &logBuffer, &logMutex)
 
 	// Should only include new audio file
 	assert.Len(t, result, 1)
-	assert.Contains(t, result, "audio3.m4a")
+	assert.Contains(t, result, files[2])
+}
 
-	// New file should be marked as processed
-	assert.True(t, processedFiles["audio3.m4a"])
+func TestFilterNewAudioFiles_QueuedNotRequeued(t *testing.T) {
+	store := jobs.NewMemoryStore()
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	files := createTestFiles(t, t.TempDir(), "audio1.wav")
+
+	assert.Len(t, filterNewAudioFiles(files, store, 0, nil, &logBuffer, &logMutex), 1)
+	// A second scan while the file is still queued must not queue it twice
+	assert.Empty(t, filterNewAudioFiles(files, store, 0, nil, &logBuffer, &logMutex))
+}
+
+func TestFilterNewAudioFiles_InterruptedJobRecovered(t *testing.T) {
+	tempDir := t.TempDir()
+	storePath := filepath.Join(tempDir, "jobs.json")
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	files := createTestFiles(t, tempDir, "audio1.wav", "audio2.wav")
+
+	store, err := jobs.Open(storePath)
+	assert.NoError(t, err)
+	assert.Len(t, filterNewAudioFiles(files, store, 0, nil, &logBuffer, &logMutex), 2)
+	assert.NoError(t, store.MarkProcessing(files[0]))
+	assert.NoError(t, store.MarkDone(files[1]))
+
+	// Simulate a restart: the interrupted file is picked up again, the finished one is not
+	reopened, err := jobs.Open(storePath)
+	assert.NoError(t, err)
+	result := filterNewAudioFiles(files, reopened, 0, nil, &logBuffer, &logMutex)
+	assert.Equal(t, []string{files[0]}, result)
+}
+
+func TestFilterNewAudioFiles_WaitsForSettle(t *testing.T) {
+	store := jobs.NewMemoryStore()
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	files := createTestFiles(t, t.TempDir(), "copying.wav")
+
+	// Not settled yet: the file is listed as waiting instead of being queued
+	assert.Empty(t, filterNewAudioFiles(files, store, time.Hour, nil, &logBuffer, &logMutex))
+	assert.Equal(t, files, store.Waiting())
+	assert.Empty(t, store.List())
+
+	// Once settled it is claimed and no longer waiting
+	assert.Equal(t, files, filterNewAudioFiles(files, store, 0, nil, &logBuffer, &logMutex))
+	assert.Empty(t, store.Waiting())
+}
+
+func TestFileSettleWindow(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.FileSettleSeconds = 10
+	assert.Equal(t, 10*time.Second, fileSettleWindow(cfg))
+
+	cfg.FileSettleSeconds = -5
+	assert.Equal(t, time.Duration(0), fileSettleWindow(cfg))
 }
 
 func TestScanAndProcess_InvalidDirectory(t *testing.T) {
@@ -139,17 +224,17 @@ func TestScanAndProcess_InvalidDirectory(t *testing.T) {
 	var logMutex sync.RWMutex
 	var lastScanTime time.Time
 	var queuedFiles []string
-	var processingFile string
+	var processingFiles []string
 	var isProcessing bool
-	processedFiles := make(map[string]bool)
+	jobStore := jobs.NewMemoryStore()
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
 	logger := log.New(os.Stdout, "", log.LstdFlags)
 
 	// Should handle invalid directory gracefully
-	ScanAndProcess(cfg, logger, &logBuffer, &logMutex, &lastScanTime, &queuedFiles,
-		&processingFile, &isProcessing, &processedFiles, &mu, &wg, false)
+	ScanAndProcess(context.Background(), cfg, logger, &logBuffer, &logMutex, &lastScanTime, &queuedFiles,
+		&processingFiles, &isProcessing, &ScheduleState{}, jobStore, &mu, &wg, false)
 
 	// Check if function completed without panic (this is the main test)
 	// Note: filepath.Glob doesn't return errors for non-existent directories
@@ -169,22 +254,6 @@ func TestScanAndProcess_InvalidDirectory(t *testing.T) {
 	assert.True(t, hasInfoMessage, "Should log scanning info message")
 }
 
-func TestProcessedFilesCleanup_MapSize(t *testing.T) {
-	// Create a large processed files map to test cleanup
-	processedFiles := make(map[string]bool)
-
-	// Add more than 5000 files to trigger cleanup
-	for i := 0; i < 6000; i++ {
-		processedFiles[fmt.Sprintf("file_%d.wav", i)] = true
-	}
-
-	assert.Len(t, processedFiles, 6000)
-
-	// This would trigger cleanup in actual ScanAndProcess call
-	// We're testing the threshold condition
-	assert.Greater(t, len(processedFiles), 5000)
-}
-
 func TestConcurrentProcessing_StateManagement(t *testing.T) {
 	var mu sync.Mutex
 	var isProcessing bool
@@ -259,26 +328,312 @@ func TestSafeProcessingStart_RaceConditionFixed(t *testing.T) {
 	assert.False(t, isProcessing, "Processing should be false after all goroutines complete")
 }
 
-func TestCleanupProcessedFiles_SafeConcurrentAccess(t *testing.T) {
-	// Test the fixed concurrent map access in cleanup function
-	processedFiles := make(map[string]bool)
+func TestMaxConcurrentJobs(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	assert.Equal(t, 1, maxConcurrentJobs(cfg))
+
+	cfg.MaxConcurrentJobs = 4
+	assert.Equal(t, 4, maxConcurrentJobs(cfg))
+
+	// Invalid values fall back to a single worker
+	cfg.MaxConcurrentJobs = 0
+	assert.Equal(t, 1, maxConcurrentJobs(cfg))
+	cfg.MaxConcurrentJobs = -3
+	assert.Equal(t, 1, maxConcurrentJobs(cfg))
+}
+
+func TestStartWorkers_RespectsPoolLimit(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.MaxConcurrentJobs = 2
+
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
 	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var isProcessing bool
 
-	// Add more than 5000 files to trigger cleanup
-	for i := 0; i < 6000; i++ {
-		processedFiles[fmt.Sprintf("file_%d.wav", i)] = true
+	// Two workers are already busy
+	processingFiles := []string{"/input/a.wav", "/input/b.wav"}
+	queuedFiles := []string{"/input/c.wav"}
+
+	startWorkers(context.Background(), cfg, nil, &logBuffer, &logMutex, &queuedFiles, &processingFiles,
+		&isProcessing, &ScheduleState{}, jobs.NewMemoryStore(), &mu, &wg, false)
+	wg.Wait()
+
+	assert.Equal(t, []string{"/input/c.wav"}, queuedFiles, "queued file must wait for a free worker")
+	assert.Len(t, processingFiles, 2)
+	assert.True(t, isProcessing)
+}
+
+func TestStartWorkers_EmptyQueue(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.MaxConcurrentJobs = 3
+
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	isProcessing := true
+	var queuedFiles, processingFiles []string
+
+	startWorkers(context.Background(), cfg, nil, &logBuffer, &logMutex, &queuedFiles, &processingFiles,
+		&isProcessing, &ScheduleState{}, jobs.NewMemoryStore(), &mu, &wg, false)
+	wg.Wait()
+
+	assert.Empty(t, processingFiles)
+	assert.False(t, isProcessing)
+}
+
+func TestStartWorkers_NothingStartedAfterShutdown(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var isProcessing bool
+	var processingFiles []string
+	queuedFiles := []string{"/input/a.wav"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	startWorkers(ctx, cfg, nil, &logBuffer, &logMutex, &queuedFiles, &processingFiles,
+		&isProcessing, &ScheduleState{}, jobs.NewMemoryStore(), &mu, &wg, false)
+	wg.Wait()
+
+	assert.Equal(t, []string{"/input/a.wav"}, queuedFiles, "queued jobs are resumed on the next start")
+	assert.Empty(t, processingFiles)
+	assert.False(t, isProcessing)
+}
+
+func TestRemoveFromQueue(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	tempDir := t.TempDir()
+	audio := filepath.Join(tempDir, "b.wav")
+	require.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
+
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	var mu sync.Mutex
+	store := jobs.NewMemoryStore()
+	_, err := store.Claim(audio)
+	require.NoError(t, err)
+
+	testLogger := log.New(os.Stdout, "", log.LstdFlags)
+	queuedFiles := []string{"/input/a.wav", audio, "/input/c.wav"}
+	assert.True(t, RemoveFromQueue(cfg, testLogger, &logBuffer, &logMutex,
+		&queuedFiles, store, &mu, audio))
+	assert.Equal(t, []string{"/input/a.wav", "/input/c.wav"}, queuedFiles)
+
+	job, _ := store.Get(audio)
+	assert.Equal(t, jobs.StatusCancelled, job.Status)
+
+	// Removing again reports that the file is no longer queued
+	assert.False(t, RemoveFromQueue(cfg, testLogger, &logBuffer, &logMutex,
+		&queuedFiles, store, &mu, audio))
+}
+
+func TestCancelFile_NotRunning(t *testing.T) {
+	assert.False(t, CancelFile(jobs.NewMemoryStore(), "/input/a.wav"))
+}
+
+func TestRemoveFile(t *testing.T) {
+	files := []string{"a.wav", "b.wav", "c.wav"}
+
+	result := removeFile(files, "b.wav")
+	assert.Equal(t, []string{"a.wav", "c.wav"}, result)
+	// The original slice must not be modified (workers share it under mu)
+	assert.Equal(t, []string{"a.wav", "b.wav", "c.wav"}, files)
+
+	assert.Equal(t, files, removeFile(files, "missing.wav"))
+}
+
+// setupRecursiveInput creates input/top.wav, input/clientA/a.wav and input/.hidden/b.wav
+func setupRecursiveInput(t *testing.T) *config.Config {
+	tempDir := t.TempDir()
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(tempDir, "input")
+	cfg.OutputDir = filepath.Join(tempDir, "output")
+	cfg.ArchiveDir = filepath.Join(tempDir, "archive")
+	cfg.FailedDir = filepath.Join(tempDir, "failed")
+
+	for _, rel := range []string{"top.wav", filepath.Join("clientA", "a.wav"), filepath.Join(".hidden", "b.wav")} {
+		path := filepath.Join(cfg.InputDir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte("audio"), 0644))
 	}
+	return cfg
+}
+
+func TestListInputFiles_TopLevelOnly(t *testing.T) {
+	cfg := setupRecursiveInput(t)
+
+	files, err := listInputFiles(cfg)
+	require.NoError(t, err)
+	// Subfolders are listed but not descended into (and not audio files)
+	assert.Contains(t, files, filepath.Join(cfg.InputDir, "top.wav"))
+	assert.NotContains(t, files, filepath.Join(cfg.InputDir, "clientA", "a.wav"))
+}
+
+func TestListInputFiles_Recursive(t *testing.T) {
+	cfg := setupRecursiveInput(t)
+	cfg.RecursiveScan = true
+
+	// An output folder inside the input folder must not be scanned
+	cfg.OutputDir = filepath.Join(cfg.InputDir, "output")
+	nested := filepath.Join(cfg.OutputDir, "old.wav")
+	require.NoError(t, os.MkdirAll(cfg.OutputDir, 0755))
+	require.NoError(t, os.WriteFile(nested, []byte("audio"), 0644))
+
+	files, err := listInputFiles(cfg)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(cfg.InputDir, "top.wav"),
+		filepath.Join(cfg.InputDir, "clientA", "a.wav"),
+	}, files)
+}
+
+func TestListInputFiles_MissingInputDir(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(t.TempDir(), "missing")
+	cfg.RecursiveScan = true
+
+	_, err := listInputFiles(cfg)
+	assert.Error(t, err)
+}
+
+func TestMoveToArchive_MirrorsSubfolder(t *testing.T) {
+	cfg := setupRecursiveInput(t)
+	source := filepath.Join(cfg.InputDir, "clientA", "a.wav")
+
+	require.NoError(t, moveToArchive(cfg, source))
+	assert.FileExists(t, filepath.Join(cfg.ArchiveDir, "clientA", "a.wav"))
+	assert.NoFileExists(t, source)
+
+	// The same name from another subfolder does not collide
+	other := filepath.Join(cfg.InputDir, "clientB", "a.wav")
+	require.NoError(t, os.MkdirAll(filepath.Dir(other), 0755))
+	require.NoError(t, os.WriteFile(other, []byte("audio"), 0644))
+	require.NoError(t, moveToArchive(cfg, other))
+	assert.FileExists(t, filepath.Join(cfg.ArchiveDir, "clientB", "a.wav"))
+}
+
+func TestProcessFile_InvalidProfileKeepsFile(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(tempDir, "input")
+	cfg.OutputDir = filepath.Join(tempDir, "output")
+	cfg.ArchiveDir = filepath.Join(tempDir, "archive")
+	cfg.FailedDir = filepath.Join(tempDir, "failed")
+
+	subDir := filepath.Join(cfg.InputDir, "calls")
+	require.NoError(t, os.MkdirAll(subDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(subDir, config.ProfileFileName), []byte(`{"language": `), 0644))
+	audio := filepath.Join(subDir, "a.wav")
+	require.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
 
-	log := log.New(os.Stdout, "", log.LstdFlags)
 	var logBuffer []logger.LogEntry
 	var logMutex sync.RWMutex
+	store := jobs.NewMemoryStore()
+	_, err := store.Claim(audio)
+	require.NoError(t, err)
 
-	// Test that cleanup works without concurrent map access issues
-	mu.Lock()
-	cleanupProcessedFiles(&processedFiles, &mu, log, &logBuffer, &logMutex)
-	mu.Unlock()
+	processFile(context.Background(), cfg, log.New(os.Stdout, "", log.LstdFlags), &logBuffer, &logMutex, store, false, audio)
+
+	job, _ := store.Get(audio)
+	assert.Equal(t, jobs.StatusFailed, job.Status)
+	assert.FileExists(t, audio, "a broken profile is a setup problem; the file stays in place")
+	assert.NoDirExists(t, cfg.FailedDir)
+}
+
+func TestPauseForRecording(t *testing.T) {
+	t.Cleanup(func() { whisper.SetPaused(false) })
+	cfg := config.GetDefaultConfig()
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	PauseForRecording(cfg, nil, &logBuffer, &logMutex, true)
+	assert.True(t, whisper.Paused())
+	PauseForRecording(cfg, nil, &logBuffer, &logMutex, true)
+	PauseForRecording(cfg, nil, &logBuffer, &logMutex, false)
+	assert.False(t, whisper.Paused())
+	assert.Len(t, logBuffer, 2, "only changes are logged")
+
+	cfg.PauseWhileRecording = false
+	PauseForRecording(cfg, nil, &logBuffer, &logMutex, true)
+	assert.False(t, whisper.Paused())
+}
+
+func TestProcessFile_MockBackend(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(tempDir, "input")
+	cfg.OutputDir = filepath.Join(tempDir, "output")
+	cfg.ArchiveDir = filepath.Join(tempDir, "archive")
+	cfg.FailedDir = filepath.Join(tempDir, "failed")
+	cfg.TranscriptionBackend = config.BackendMock
+
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	audio := filepath.Join(cfg.InputDir, "a.wav")
+	require.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
+
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	store := jobs.NewMemoryStore()
+	_, err := store.Claim(audio)
+	require.NoError(t, err)
+
+	processFile(context.Background(), cfg, log.New(os.Stdout, "", log.LstdFlags), &logBuffer, &logMutex, store, false, audio)
+
+	job, _ := store.Get(audio)
+	assert.Equal(t, jobs.StatusDone, job.Status)
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "テスト用の文字起こし 1\n", string(data))
+	assert.FileExists(t, filepath.Join(cfg.ArchiveDir, "a.wav"))
+}
+
+func TestProcessFile_SeveralFormats(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(tempDir, "input")
+	cfg.OutputDir = filepath.Join(tempDir, "output")
+	cfg.ArchiveDir = filepath.Join(tempDir, "archive")
+	cfg.FailedDir = filepath.Join(tempDir, "failed")
+	cfg.TranscriptionBackend = config.BackendMock
+	cfg.OutputFormats = []string{"srt", "txt"}
+
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	audio := filepath.Join(cfg.InputDir, "a.wav")
+	require.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
+
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	store := jobs.NewMemoryStore()
+	_, err := store.Claim(audio)
+	require.NoError(t, err)
+
+	processFile(context.Background(), cfg, log.New(os.Stdout, "", log.LstdFlags), &logBuffer, &logMutex, store, false, audio)
+
+	job, _ := store.Get(audio)
+	assert.Equal(t, jobs.StatusDone, job.Status)
+	assert.Equal(t, []string{filepath.Join(cfg.OutputDir, "a.srt"), filepath.Join(cfg.OutputDir, "a.txt")}, job.Outputs)
+	assert.NoFileExists(t, filepath.Join(cfg.OutputDir, "a.vtt"))
+}
+
+func TestSummarySource(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.OutputFormats = []string{"srt", "txt"}
+	assert.Equal(t, filepath.Join("out", "a.txt"), summarySource(cfg, "out", "a"), "the plain text is preferred")
+
+	cfg.OutputFormats = []string{"vtt", "srt"}
+	assert.Equal(t, filepath.Join("out", "a.vtt"), summarySource(cfg, "out", "a"))
+
+	cfg.CleanTranscript = true
+	assert.True(t, cfg.SummaryFromClean())
+	cfg.SummarySource = config.SummarySourceVerbatim
+	assert.False(t, cfg.SummaryFromClean())
 
-	// Should have cleaned up to approximately half
-	assert.LessOrEqual(t, len(processedFiles), 2500, "Should cleanup to 2500 or fewer entries")
-	assert.Greater(t, len(processedFiles), 0, "Should keep some entries")
+	segments := []whisper.Segment{{Text: "こんにちは"}, {Text: "本日の議題です"}}
+	assert.Equal(t, "こんにちは\n本日の議題です\n", segmentsText(segments))
 }
@@ -0,0 +1,129 @@
+package processor
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/ui"
+	"github.com/infoHiroki/KoeMoji-Go/internal/whisper"
+)
+
+// maxRetryDelay caps the exponential backoff between retries
+const maxRetryDelay = time.Hour
+
+// retryDelay returns the wait before the retry following the given attempt
+// (1 = first attempt failed). The base delay doubles on each further retry.
+func retryDelay(config *config.Config, attempt int) time.Duration {
+	if config.RetryBackoffSeconds <= 0 {
+		return 0
+	}
+	delay := time.Duration(config.RetryBackoffSeconds) * time.Second
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// handleFailure decides what happens to a file after a failed transcription.
+// Transient failures are retried with backoff until max_retries is used up.
+// Files that cannot succeed are moved to failed_dir with an .error.txt report.
+// Setup problems (e.g. whisper missing) leave the file in place since the file itself is fine.
+func handleFailure(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	jobStore *jobs.Store, filePath string, jobErr error) {
+
+	fileName := filepath.Base(filePath)
+	msg := ui.GetMessages(config)
+	kind := whisper.ClassifyError(jobErr)
+
+	attempts := 1
+	if job, ok := jobStore.Get(filePath); ok && job.Attempts > 0 {
+		attempts = job.Attempts
+	}
+
+	var storeErr error
+	switch {
+	case kind.Retryable() && attempts <= config.MaxRetries:
+		delay := retryDelay(config, attempts)
+		logger.LogInfo(log, logBuffer, logMutex, msg.RetryScheduled, fileName, formatDuration(delay), attempts+1, config.MaxRetries+1)
+		storeErr = jobStore.ScheduleRetry(filePath, jobErr, time.Now().Add(delay))
+
+	case kind == whisper.ErrorKindSetup || config.FailedDir == "":
+		storeErr = jobStore.MarkFailed(filePath, jobErr)
+
+	default:
+		destPath, err := moveToFailed(config, filePath)
+		if err != nil {
+			logger.LogError(log, logBuffer, logMutex, "Failed to move %s to failed folder: %v", fileName, err)
+			storeErr = jobStore.MarkFailed(filePath, jobErr)
+			break
+		}
+		if err := writeFailureReport(config, destPath, kind, attempts, jobErr); err != nil {
+			logger.LogError(log, logBuffer, logMutex, "Failed to write failure report for %s: %v", fileName, err)
+		}
+		logger.LogError(log, logBuffer, logMutex, msg.MovedToFailed, fileName, destPath)
+		storeErr = jobStore.MarkMovedToFailed(filePath, jobErr, destPath)
+	}
+
+	if storeErr != nil {
+		logger.LogError(log, logBuffer, logMutex, "Failed to update job store: %v", storeErr)
+	}
+}
+
+// moveToFailed moves the file to failed_dir, keeping its subfolder, and returns its new path
+func moveToFailed(config *config.Config, sourcePath string) (string, error) {
+	failedDir := config.MirrorDir(config.FailedDir, sourcePath)
+	if err := os.MkdirAll(failedDir, 0755); err != nil {
+		return "", err
+	}
+
+	destPath := uniqueDestPath(failedDir, filepath.Base(sourcePath))
+	if err := os.Rename(sourcePath, destPath); err != nil {
+		return "", err
+	}
+	return destPath, nil
+}
+
+// writeFailureReport writes <file>.error.txt next to a file moved to failed_dir
+func writeFailureReport(config *config.Config, filePath string, kind whisper.ErrorKind, attempts int, jobErr error) error {
+	report := failureReport(config, filePath, kind, attempts, jobErr)
+	return os.WriteFile(filePath+".error.txt", []byte(report), 0644)
+}
+
+// failureReport builds the .error.txt content in the UI language
+func failureReport(config *config.Config, filePath string, kind whisper.ErrorKind, attempts int, jobErr error) string {
+	msg := ui.GetMessages(config)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", msg.FailureReportTitle)
+	fmt.Fprintf(&b, "%s: %s\n", msg.FailureReportFile, filepath.Base(filePath))
+	fmt.Fprintf(&b, "%s: %s\n", msg.FailureReportTime, time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "%s: %d\n", msg.FailureReportAttempts, attempts)
+	fmt.Fprintf(&b, "%s: %s\n\n", msg.FailureReportReason, failureKindText(msg, kind))
+	fmt.Fprintf(&b, "%s:\n%v\n\n", msg.FailureReportDetails, jobErr)
+	fmt.Fprintf(&b, "%s\n", msg.FailureReportRetry)
+	return b.String()
+}
+
+func failureKindText(msg *ui.Messages, kind whisper.ErrorKind) string {
+	switch kind {
+	case whisper.ErrorKindGPU:
+		return msg.FailureKindGPU
+	case whisper.ErrorKindOutput:
+		return msg.FailureKindOutput
+	case whisper.ErrorKindSetup:
+		return msg.FailureKindSetup
+	default:
+		return msg.FailureKindTransient
+	}
+}
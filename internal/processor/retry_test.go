@@ -0,0 +1,112 @@
+package processor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/whisper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryDelay(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.RetryBackoffSeconds = 30
+
+	assert.Equal(t, 30*time.Second, retryDelay(cfg, 1))
+	assert.Equal(t, 60*time.Second, retryDelay(cfg, 2))
+	assert.Equal(t, 120*time.Second, retryDelay(cfg, 3))
+	assert.Equal(t, maxRetryDelay, retryDelay(cfg, 20))
+
+	cfg.RetryBackoffSeconds = 0
+	assert.Equal(t, time.Duration(0), retryDelay(cfg, 3))
+}
+
+// setupFailureTest creates input/failed dirs and a claimed job that just failed once
+func setupFailureTest(t *testing.T) (*config.Config, *jobs.Store, string) {
+	tempDir := t.TempDir()
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(tempDir, "input")
+	cfg.FailedDir = filepath.Join(tempDir, "failed")
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+
+	files := createTestFiles(t, cfg.InputDir, "meeting.wav")
+	store := jobs.NewMemoryStore()
+	_, err := store.Claim(files[0])
+	require.NoError(t, err)
+	require.NoError(t, store.MarkProcessing(files[0]))
+	return cfg, store, files[0]
+}
+
+func TestHandleFailure_TransientIsRetried(t *testing.T) {
+	cfg, store, file := setupFailureTest(t)
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	handleFailure(cfg, nil, &logBuffer, &logMutex, store, file, errors.New("exit status 1"))
+
+	job, _ := store.Get(file)
+	assert.Equal(t, jobs.StatusQueued, job.Status)
+	assert.True(t, job.RetryAt.After(time.Now()))
+	assert.FileExists(t, file, "file stays in input while waiting for a retry")
+}
+
+func TestHandleFailure_RetriesExhaustedMovesToFailed(t *testing.T) {
+	cfg, store, file := setupFailureTest(t)
+	cfg.MaxRetries = 0
+	cfg.UILanguage = "ja"
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	handleFailure(cfg, nil, &logBuffer, &logMutex, store, file, errors.New("exit status 1"))
+
+	moved := filepath.Join(cfg.FailedDir, "meeting.wav")
+	assert.NoFileExists(t, file)
+	assert.FileExists(t, moved)
+
+	report, err := os.ReadFile(moved + ".error.txt")
+	require.NoError(t, err)
+	assert.Contains(t, string(report), "文字起こしできませんでした")
+	assert.Contains(t, string(report), "exit status 1")
+
+	job, _ := store.Get(file)
+	assert.Equal(t, jobs.StatusFailed, job.Status)
+	assert.Equal(t, moved, job.MovedTo)
+}
+
+func TestHandleFailure_GPUErrorIsNotRetried(t *testing.T) {
+	cfg, store, file := setupFailureTest(t)
+	cfg.UILanguage = "en"
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	gpuErr := &whisper.TranscribeError{Kind: whisper.ErrorKindGPU, Err: errors.New("CUDA out of memory")}
+	handleFailure(cfg, nil, &logBuffer, &logMutex, store, file, gpuErr)
+
+	moved := filepath.Join(cfg.FailedDir, "meeting.wav")
+	assert.FileExists(t, moved)
+	report, err := os.ReadFile(moved + ".error.txt")
+	require.NoError(t, err)
+	assert.Contains(t, string(report), "GPU or memory problem")
+}
+
+func TestHandleFailure_SetupErrorKeepsFile(t *testing.T) {
+	cfg, store, file := setupFailureTest(t)
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+
+	setupErr := &whisper.TranscribeError{Kind: whisper.ErrorKindSetup, Err: errors.New("whisper-ctranslate2 not found")}
+	handleFailure(cfg, nil, &logBuffer, &logMutex, store, file, setupErr)
+
+	assert.FileExists(t, file)
+	assert.NoDirExists(t, cfg.FailedDir)
+	job, _ := store.Get(file)
+	assert.Equal(t, jobs.StatusFailed, job.Status)
+}
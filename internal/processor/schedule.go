@@ -0,0 +1,76 @@
+package processor
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/ui"
+)
+
+// ScheduleState holds the processing_schedule state of one application.
+// Like isProcessing it is owned by the caller and passed to the processor.
+type ScheduleState struct {
+	// processNow is set by ProcessNow and ignores processing_schedule until
+	// the queue is drained
+	processNow atomic.Bool
+	// deferred is set once waiting for the next window was logged
+	deferred atomic.Bool
+}
+
+// scheduleAllows reports whether queued files may be started at now
+func scheduleAllows(c *config.Config, schedule *ScheduleState, now time.Time) bool {
+	return schedule.processNow.Load() || c.InProcessingWindow(now)
+}
+
+// NextWindow returns when the next processing window starts while
+// processing_schedule holds queued files back. It reports false while
+// files may be processed.
+func NextWindow(c *config.Config, schedule *ScheduleState, now time.Time) (time.Time, bool) {
+	if scheduleAllows(c, schedule, now) {
+		return time.Time{}, false
+	}
+	return c.NextProcessingWindow(now)
+}
+
+// ProcessNow starts the queued files right away. processing_schedule is
+// ignored until the queue is empty again.
+func ProcessNow(ctx context.Context, config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	queuedFiles *[]string, processingFiles *[]string, isProcessing *bool, schedule *ScheduleState, jobStore *jobs.Store,
+	mu *sync.Mutex, wg *sync.WaitGroup, debugMode bool) {
+
+	mu.Lock()
+	queued := len(*queuedFiles)
+	mu.Unlock()
+
+	msg := ui.GetMessages(config)
+	if queued == 0 {
+		logger.LogInfo(log, logBuffer, logMutex, msg.ProcessNowEmpty)
+		return
+	}
+
+	schedule.processNow.Store(true)
+	logger.LogInfo(log, logBuffer, logMutex, msg.ProcessNowStarted, queued)
+	startWorkers(ctx, config, log, logBuffer, logMutex, queuedFiles, processingFiles,
+		isProcessing, schedule, jobStore, mu, wg, debugMode)
+}
+
+// logScheduleDeferred logs once that queued files wait for the next window
+func logScheduleDeferred(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
+	schedule *ScheduleState, queued int) {
+
+	if !schedule.deferred.CompareAndSwap(false, true) {
+		return
+	}
+	msg := ui.GetMessages(config)
+	next, ok := config.NextProcessingWindow(time.Now())
+	if !ok {
+		return
+	}
+	logger.LogInfo(log, logBuffer, logMutex, msg.ScheduleDeferred, queued, ui.FormatNextWindow(next))
+}
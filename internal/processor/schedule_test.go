@@ -0,0 +1,71 @@
+package processor
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+// closedScheduleConfig returns a config whose only processing window is tomorrow
+func closedScheduleConfig() *config.Config {
+	cfg := config.GetDefaultConfig()
+	tomorrow := time.Now().AddDate(0, 0, 1).Weekday()
+	cfg.ProcessingSchedule = []string{strings.ToLower(tomorrow.String()[:3])}
+	return cfg
+}
+
+func TestStartWorkers_WaitsForProcessingWindow(t *testing.T) {
+	cfg := closedScheduleConfig()
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	var mu sync.Mutex
+	queued := []string{"/input/a.wav", "/input/b.wav"}
+	var processing []string
+	var isProcessing bool
+	var schedule ScheduleState
+
+	startWorkers(context.Background(), cfg, nil, &logBuffer, &logMutex, &queued, &processing, &isProcessing,
+		&schedule, jobs.NewMemoryStore(), &mu, nil, false)
+	startWorkers(context.Background(), cfg, nil, &logBuffer, &logMutex, &queued, &processing, &isProcessing,
+		&schedule, jobs.NewMemoryStore(), &mu, nil, false)
+
+	assert.Len(t, queued, 2, "files stay queued outside the window")
+	assert.Empty(t, processing)
+	assert.False(t, isProcessing)
+	assert.Len(t, logBuffer, 1, "waiting for the window is logged once")
+
+	next, ok := NextWindow(cfg, &schedule, time.Now())
+	assert.True(t, ok)
+	assert.True(t, next.After(time.Now()))
+}
+
+func TestProcessNow_OverridesSchedule(t *testing.T) {
+	cfg := closedScheduleConfig()
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	var mu sync.Mutex
+	var queued, processing []string
+	var isProcessing bool
+	var schedule ScheduleState
+
+	// Nothing to do: the schedule stays in effect
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // keep workers from starting
+	ProcessNow(ctx, cfg, nil, &logBuffer, &logMutex, &queued, &processing, &isProcessing,
+		&schedule, jobs.NewMemoryStore(), &mu, nil, false)
+	assert.False(t, scheduleAllows(cfg, &schedule, time.Now()))
+
+	queued = []string{"/input/a.wav"}
+	ProcessNow(ctx, cfg, nil, &logBuffer, &logMutex, &queued, &processing, &isProcessing,
+		&schedule, jobs.NewMemoryStore(), &mu, nil, false)
+	assert.True(t, scheduleAllows(cfg, &schedule, time.Now()))
+	_, ok := NextWindow(cfg, &schedule, time.Now())
+	assert.False(t, ok, "no next window is shown while processing now")
+}
@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/ui"
+)
+
+// watchDebounce is how long the watcher waits after the last file event before
+// queuing, so a file that is still being copied is not picked up right away
+const watchDebounce = 2 * time.Second
+
+// watchEnabled reports whether the input directory should be watched for events
+func watchEnabled(c *config.Config) bool {
+	return c.WatchMode != config.WatchModePoll
+}
+
+// newInputWatcher starts an fsnotify watcher on the input folder, and on all
+// of its subfolders when recursive_scan is enabled
+func newInputWatcher(config *config.Config) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(config.InputDir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	if config.RecursiveScan {
+		watchSubdirs(watcher, config, config.InputDir)
+	}
+	return watcher, nil
+}
+
+// watchSubdirs adds dir and every folder below it to the watcher and returns
+// the files found on the way. A folder moved into the input folder only
+// produces one Create event, so the files inside it are collected here.
+func watchSubdirs(watcher *fsnotify.Watcher, config *config.Config, dir string) []string {
+	var files []string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != config.InputDir && skipInputDir(config, path) {
+				return filepath.SkipDir
+			}
+			watcher.Add(path)
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	return files
+}
+
+// isNewDirEvent reports whether ev is a folder created in (or moved into) a watched folder
+func isNewDirEvent(ev fsnotify.Event) bool {
+	if !ev.Has(fsnotify.Create) {
+		return false
+	}
+	info, err := os.Stat(ev.Name)
+	return err == nil && info.IsDir()
+}
+
+// isAudioEvent reports whether ev may mean an audio file appeared or was written.
+// Files moved into the directory are reported as Create.
+func isAudioEvent(ev fsnotify.Event) bool {
+	if !ev.Has(fsnotify.Create) && !ev.Has(fsnotify.Write) {
+		return false
+	}
+	return ui.IsAudioFile(ev.Name)
+}
@@ -0,0 +1,132 @@
+package processor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchEnabled(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	assert.True(t, watchEnabled(cfg))
+
+	cfg.WatchMode = config.WatchModePoll
+	assert.False(t, watchEnabled(cfg))
+
+	// Older config files without watch_mode keep the new default behaviour
+	cfg.WatchMode = ""
+	assert.True(t, watchEnabled(cfg))
+}
+
+func TestIsAudioEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    fsnotify.Event
+		expected bool
+	}{
+		{"Created audio file", fsnotify.Event{Name: "/in/a.wav", Op: fsnotify.Create}, true},
+		{"Written audio file", fsnotify.Event{Name: "/in/a.MP3", Op: fsnotify.Write}, true},
+		{"Created text file", fsnotify.Event{Name: "/in/a.txt", Op: fsnotify.Create}, false},
+		{"Removed audio file", fsnotify.Event{Name: "/in/a.wav", Op: fsnotify.Remove}, false},
+		{"Chmod audio file", fsnotify.Event{Name: "/in/a.wav", Op: fsnotify.Chmod}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isAudioEvent(tt.event))
+		})
+	}
+}
+
+func TestNewInputWatcher_ReportsNewFile(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = dir
+	watcher, err := newInputWatcher(cfg)
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	audio := filepath.Join(dir, "meeting.wav")
+	require.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-watcher.Events:
+			if isAudioEvent(ev) {
+				assert.Equal(t, audio, ev.Name)
+				return
+			}
+		case err := <-watcher.Errors:
+			t.Fatalf("watcher error: %v", err)
+		case <-timeout:
+			t.Fatal("no event received for new audio file")
+		}
+	}
+}
+
+func TestNewInputWatcher_MissingDirectory(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(t.TempDir(), "missing")
+	_, err := newInputWatcher(cfg)
+	assert.Error(t, err)
+}
+
+func TestNewInputWatcher_RecursiveWatchesSubfolders(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "clientA")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = dir
+	cfg.RecursiveScan = true
+	watcher, err := newInputWatcher(cfg)
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	audio := filepath.Join(sub, "meeting.wav")
+	require.NoError(t, os.WriteFile(audio, []byte("audio"), 0644))
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case ev := <-watcher.Events:
+			if isAudioEvent(ev) {
+				assert.Equal(t, audio, ev.Name)
+				return
+			}
+		case err := <-watcher.Errors:
+			t.Fatalf("watcher error: %v", err)
+		case <-timeout:
+			t.Fatal("no event received for audio file in subfolder")
+		}
+	}
+}
+
+func TestWatchSubdirs_CollectsFilesOfMovedFolder(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = dir
+	cfg.RecursiveScan = true
+	watcher, err := newInputWatcher(cfg)
+	require.NoError(t, err)
+	defer watcher.Close()
+
+	// A folder with recordings is moved into the input folder
+	staging := filepath.Join(t.TempDir(), "clientB")
+	require.NoError(t, os.MkdirAll(filepath.Join(staging, "day1"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(staging, "day1", "a.wav"), []byte("audio"), 0644))
+	moved := filepath.Join(dir, "clientB")
+	require.NoError(t, os.Rename(staging, moved))
+
+	files := watchSubdirs(watcher, cfg, moved)
+	assert.Equal(t, []string{filepath.Join(moved, "day1", "a.wav")}, files)
+	assert.True(t, isNewDirEvent(fsnotify.Event{Name: moved, Op: fsnotify.Create}))
+	assert.False(t, isNewDirEvent(fsnotify.Event{Name: files[0], Op: fsnotify.Create}))
+}
@@ -0,0 +1,214 @@
+package transcript
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// keepWidth are the full-width characters NFKC would turn into ASCII but
+// Japanese text is written with
+var keepWidth = map[rune]bool{
+	'！': true, '？': true, '（': true, '）': true, '：': true, '；': true, '～': true, '　': true,
+}
+
+// sentenceEnds may end a segment without adding "。"
+const sentenceEnds = "。！？!?…」』）)"
+
+// Cleaner removes fillers and normalizes the punctuation and width of
+// transcripts for a "clean" version next to the verbatim one
+type Cleaner struct {
+	fillers *regexp.Regexp // nil without fillers
+}
+
+// NewCleaner returns a Cleaner removing fillers, e.g. "えー" or "あのー".
+// A trailing "ー" also matches longer ones ("えーー").
+func NewCleaner(fillers []string) *Cleaner {
+	var words []string
+	for _, f := range fillers {
+		if f = strings.TrimSpace(norm.NFKC.String(f)); f != "" {
+			words = append(words, f)
+		}
+	}
+	if len(words) == 0 {
+		return &Cleaner{}
+	}
+	// Longest first, so that "えーと" is removed as a whole and not as "えー"
+	sort.SliceStable(words, func(i, j int) bool { return len(words[i]) > len(words[j]) })
+	alternatives := make([]string, len(words))
+	for i, f := range words {
+		alternatives[i] = regexp.QuoteMeta(f)
+		if strings.HasSuffix(f, "ー") {
+			alternatives[i] += "ー*"
+		}
+	}
+	return &Cleaner{fillers: regexp.MustCompile("(?i)(?:" + strings.Join(alternatives, "|") + ")")}
+}
+
+// Clean returns the cleaned segments. Segments left without text are
+// dropped; the others keep their times. Words are dropped from the changed
+// segments, as they no longer match the text. The segments passed in are not
+// modified.
+func (c *Cleaner) Clean(segments []Segment) []Segment {
+	cleaned := make([]Segment, 0, len(segments))
+	for _, s := range segments {
+		text := c.CleanText(s.Text)
+		if text == "" {
+			continue
+		}
+		if text != s.Text {
+			s.Text = text
+			s.Words = nil
+		}
+		cleaned = append(cleaned, s)
+	}
+	return cleaned
+}
+
+// CleanText cleans the text of one segment: width, fillers, then the
+// punctuation of Japanese text, ending every Japanese segment with a
+// sentence end
+func (c *Cleaner) CleanText(text string) string {
+	text = normalizeWidth(text)
+	text = c.removeFillers(text)
+	if !isJapanese(text) {
+		return strings.Join(strings.Fields(text), " ")
+	}
+	text = japanesePunctuation(text)
+	if text == "" {
+		return ""
+	}
+	last, _ := utf8.DecodeLastRuneInString(text)
+	if !strings.ContainsRune(sentenceEnds, last) {
+		text += "。"
+	}
+	return text
+}
+
+// normalizeWidth applies NFKC (full-width letters and digits to ASCII,
+// half-width katakana to full-width) except to the characters of keepWidth
+func normalizeWidth(text string) string {
+	var b strings.Builder
+	start := 0
+	for i, r := range text {
+		if keepWidth[r] {
+			b.WriteString(norm.NFKC.String(text[start:i]))
+			b.WriteRune(r)
+			start = i + utf8.RuneLen(r)
+		}
+	}
+	b.WriteString(norm.NFKC.String(text[start:]))
+	return b.String()
+}
+
+// removeFillers removes the fillers that stand on their own: at the start of
+// the text or after punctuation or a space, and followed by punctuation, a
+// space or the end. Fillers with "ー" or "っ", which are not words, are also
+// removed right before Japanese text ("えーと今日は" becomes "今日は"). A comma after a filler goes with it.
+func (c *Cleaner) removeFillers(text string) string {
+	if c.fillers == nil {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range c.fillers.FindAllStringIndex(text, -1) {
+		if !fillerBoundary(text, m[0], m[1]) {
+			continue
+		}
+		b.WriteString(text[last:m[0]])
+		end := m[1]
+		for end < len(text) {
+			r, size := utf8.DecodeRuneInString(text[end:])
+			if r != '、' && r != ',' && r != '，' && !unicode.IsSpace(r) {
+				break
+			}
+			end += size
+		}
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// fillerBoundary reports whether text[start:end] is a filler on its own
+func fillerBoundary(text string, start, end int) bool {
+	if start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		if !separator(before) {
+			return false
+		}
+	}
+	if end == len(text) {
+		return true
+	}
+	after, _ := utf8.DecodeRuneInString(text[end:])
+	if separator(after) {
+		return true
+	}
+	return strings.ContainsAny(text[start:end], "ーっ") && !isWordRune(after)
+}
+
+func separator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.IsPunct(r)
+}
+
+// isJapanese reports whether text has kana or kanji
+func isJapanese(text string) bool {
+	for _, r := range text {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+			return true
+		}
+	}
+	return false
+}
+
+// japanesePunctuation turns the commas, periods and marks written after
+// Japanese characters into "、。！？", drops spaces between Japanese
+// characters and punctuation left empty by the filler removal ("、、" or
+// "、。"). Numbers such as "3.5" are left as they are.
+func japanesePunctuation(text string) string {
+	runes := []rune(strings.TrimSpace(text))
+	out := make([]rune, 0, len(runes))
+	for i, r := range runes {
+		var prev rune
+		if len(out) > 0 {
+			prev = out[len(out)-1]
+		}
+		switch {
+		case r == '，' || (r == ',' && japaneseRune(prev)):
+			r = '、'
+		case r == '．' || (r == '.' && japaneseRune(prev)):
+			r = '。'
+		case r == '!' && japaneseRune(prev):
+			r = '！'
+		case r == '?' && japaneseRune(prev):
+			r = '？'
+		case unicode.IsSpace(r) && (japaneseRune(prev) || japanesePunct(prev)) &&
+			i+1 < len(runes) && (japaneseRune(runes[i+1]) || japanesePunct(runes[i+1])):
+			continue
+		}
+		if japanesePunct(r) {
+			if len(out) == 0 {
+				continue
+			}
+			if prev == '、' {
+				out = out[:len(out)-1]
+			}
+		}
+		out = append(out, r)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// japaneseRune reports whether r is kana, kanji or the long vowel mark
+func japaneseRune(r rune) bool {
+	return r == 'ー' || unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+func japanesePunct(r rune) bool {
+	return strings.ContainsRune("、。！？", r)
+}
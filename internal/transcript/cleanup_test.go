@@ -0,0 +1,55 @@
+package transcript
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testFillers = []string{"えー", "えーと", "えっと", "あのー", "あの", "まあ", "うーん", "um"}
+
+func TestCleaner_CleanText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"えー、今日は会議です", "今日は会議です。"},
+		{"えーと今日は、あのー、会議を始めます。", "今日は、会議を始めます。"},
+		{"えーー 本日は", "本日は。"},
+		{"あのー会議の件", "会議の件。"},
+		{"あの人は まあ、来ます", "あの人は来ます。"},
+		{"まあまあです", "まあまあです。"},
+		{"資料は、えー。", "資料は。"},
+		{"ＫｏｅＭｏｊｉは１２３円です", "KoeMojiは123円です。"},
+		{"ｶﾀｶﾅです", "カタカナです。"},
+		{"本当ですか？（笑）", "本当ですか？（笑）"},
+		{"はい,わかりました.", "はい、わかりました。"},
+		{"バージョン3.5です!", "バージョン3.5です！"},
+		{"よろしいですか", "よろしいですか。"},
+		{"えー、", ""},
+		{"Um, hello there", "hello there"},
+		{"Thanks umbrella", "Thanks umbrella"},
+	}
+	cleaner := NewCleaner(testFillers)
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, cleaner.CleanText(tt.input), tt.input)
+	}
+
+	assert.Equal(t, "えー、今日は。", NewCleaner(nil).CleanText("えー、今日は"), "without fillers only the punctuation is cleaned")
+}
+
+func TestCleaner_Clean(t *testing.T) {
+	segments := []Segment{
+		{Start: 0, End: 2 * time.Second, Text: "えー、", Words: []Word{{Text: "えー、"}}},
+		{Start: 2 * time.Second, End: 5 * time.Second, Text: "あのー会議を始めます", Words: []Word{{Text: "あのー"}, {Text: "会議を始めます"}}},
+		{Start: 5 * time.Second, End: 6 * time.Second, Text: "はい。", Words: []Word{{Text: "はい。"}}},
+	}
+
+	cleaned := NewCleaner(testFillers).Clean(segments)
+	assert.Equal(t, []Segment{
+		{Start: 2 * time.Second, End: 5 * time.Second, Text: "会議を始めます。"},
+		{Start: 5 * time.Second, End: 6 * time.Second, Text: "はい。", Words: []Word{{Text: "はい。"}}},
+	}, cleaned, "segments keep their times and lose words that no longer match")
+	assert.Equal(t, "えー、", segments[0].Text, "the input is not modified")
+}
@@ -0,0 +1,232 @@
+package transcript
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Why a segment looks like a hallucination (Segment.Suspect)
+const (
+	ReasonNoSpeech    = "no_speech"   // whisper itself thinks the stretch is silent
+	ReasonCompression = "compression" // the text compresses too well, i.e. it repeats itself
+	ReasonRepetition  = "repetition"  // a phrase repeated inside the segment or over many segments
+	ReasonPhrase      = "phrase"      // the text is nothing but phrases of the blacklist
+)
+
+// The thresholds whisper uses to retry a window (no_speech_threshold,
+// logprob_threshold and compression_ratio_threshold)
+const (
+	noSpeechThreshold    = 0.6
+	logprobThreshold     = -1.0
+	compressionThreshold = 2.4
+)
+
+// A unit repeated this many times in a row, covering at least
+// minRepeatedTokens tokens, is a repetition. The same holds for identical
+// consecutive segments, of which only the first is kept.
+const (
+	minRepeats        = 4
+	minRepeatedTokens = 8
+)
+
+// FilterOptions configure FilterHallucinations
+type FilterOptions struct {
+	Phrases []string // Blacklist of phrases whisper makes up on silence, e.g. "ご視聴ありがとうございました"
+	Flag    bool     // Keep the suspicious segments and set Segment.Suspect instead of removing them
+}
+
+// FilterReport tells what FilterHallucinations did
+type FilterReport struct {
+	Total   int            // Segments before filtering
+	Removed int            // Segments removed
+	Flagged int            // Segments kept with Suspect set
+	Reasons map[string]int // Removed or flagged segments per reason
+}
+
+// Changed reports whether any segment was removed or flagged
+func (r FilterReport) Changed() bool {
+	return r.Removed > 0 || r.Flagged > 0
+}
+
+// String returns the reasons with their counts, e.g. "no_speech 2, phrase 1"
+func (r FilterReport) String() string {
+	reasons := make([]string, 0, len(r.Reasons))
+	for reason := range r.Reasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	parts := make([]string, len(reasons))
+	for i, reason := range reasons {
+		parts[i] = fmt.Sprintf("%s %d", reason, r.Reasons[reason])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FilterHallucinations removes (or flags) the segments whisper most likely
+// made up: silent stretches, text that repeats itself and the phrases of the
+// blacklist. The segments passed in are not modified.
+func FilterHallucinations(segments []Segment, opts FilterOptions) ([]Segment, FilterReport) {
+	report := FilterReport{Total: len(segments), Reasons: map[string]int{}}
+	phrases := normalizePhrases(opts.Phrases)
+	repeated := repeatedSegments(segments)
+
+	kept := make([]Segment, 0, len(segments))
+	for i, s := range segments {
+		reason := ""
+		if repeated[i] {
+			reason = ReasonRepetition
+		} else {
+			reason = suspectReason(s, phrases)
+		}
+		if reason == "" {
+			kept = append(kept, s)
+			continue
+		}
+		report.Reasons[reason]++
+		if opts.Flag {
+			s.Suspect = reason
+			kept = append(kept, s)
+			report.Flagged++
+		} else {
+			report.Removed++
+		}
+	}
+	return kept, report
+}
+
+// suspectReason returns why s looks like a hallucination, or "" when it does not
+func suspectReason(s Segment, phrases []string) string {
+	// avg_logprob 0 means the backend did not report it
+	if s.NoSpeechProb > noSpeechThreshold && s.AvgLogprob != 0 && s.AvgLogprob < logprobThreshold {
+		return ReasonNoSpeech
+	}
+	text := normalize(s.Text)
+	if text == "" {
+		return ""
+	}
+	if onlyPhrases(text, phrases) {
+		return ReasonPhrase
+	}
+	if repeatsItself(tokens(s.Text)) {
+		return ReasonRepetition
+	}
+	if compressionRatio(s.Text) > compressionThreshold {
+		return ReasonCompression
+	}
+	return ""
+}
+
+// repeatedSegments marks the segments that repeat the text of the segment
+// before them, in runs of at least minRepeats identical segments. The first
+// segment of a run is kept.
+func repeatedSegments(segments []Segment) []bool {
+	repeated := make([]bool, len(segments))
+	for start := 0; start < len(segments); {
+		text := normalize(segments[start].Text)
+		end := start + 1
+		for end < len(segments) && text != "" && normalize(segments[end].Text) == text {
+			end++
+		}
+		if end-start >= minRepeats {
+			for i := start + 1; i < end; i++ {
+				repeated[i] = true
+			}
+		}
+		start = end
+	}
+	return repeated
+}
+
+// normalize returns text without spaces and punctuation, in lower case, so
+// that "ご視聴ありがとうございました。" and "ご視聴 ありがとうございました" match
+func normalize(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, text)
+}
+
+// normalizePhrases normalizes the blacklist, longest phrase first so that a
+// phrase containing a shorter one is removed as a whole
+func normalizePhrases(phrases []string) []string {
+	normalized := make([]string, 0, len(phrases))
+	for _, p := range phrases {
+		if n := normalize(p); n != "" {
+			normalized = append(normalized, n)
+		}
+	}
+	sort.SliceStable(normalized, func(i, j int) bool { return len(normalized[i]) > len(normalized[j]) })
+	return normalized
+}
+
+// onlyPhrases reports whether nothing but blacklisted phrases is left of the
+// normalized text
+func onlyPhrases(text string, phrases []string) bool {
+	if len(phrases) == 0 {
+		return false
+	}
+	for _, p := range phrases {
+		text = strings.ReplaceAll(text, p, "")
+	}
+	return text == ""
+}
+
+// tokens splits text into words, or into characters for languages written
+// without spaces such as Japanese
+func tokens(text string) []string {
+	text = strings.TrimSpace(text)
+	if strings.ContainsAny(text, " \t") {
+		return strings.Fields(text)
+	}
+	var chars []string
+	for _, r := range text {
+		chars = append(chars, string(r))
+	}
+	return chars
+}
+
+// repeatsItself reports whether some run of tokens is repeated at least
+// minRepeats times in a row, e.g. "ありがとうございます。" four times
+func repeatsItself(tokens []string) bool {
+	for n := 1; n*minRepeats <= len(tokens); n++ {
+		for start := 0; start+n*minRepeats <= len(tokens); start++ {
+			count := 1
+			for start+(count+1)*n <= len(tokens) &&
+				equalTokens(tokens[start:start+n], tokens[start+count*n:start+(count+1)*n]) {
+				count++
+			}
+			if count >= minRepeats && count*n >= minRepeatedTokens {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func equalTokens(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// compressionRatio is whisper's measure of repetitive text: the size of the
+// text divided by its zlib compressed size
+func compressionRatio(text string) float64 {
+	if text == "" {
+		return 0
+	}
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write([]byte(text))
+	w.Close()
+	return float64(len(text)) / float64(b.Len())
+}
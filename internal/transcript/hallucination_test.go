@@ -0,0 +1,78 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterHallucinations(t *testing.T) {
+	segment := func(sec int, text string) Segment {
+		return Segment{Start: time.Duration(sec) * time.Second, End: time.Duration(sec+1) * time.Second, Text: text}
+	}
+	silent := segment(1, "えーと")
+	silent.NoSpeechProb, silent.AvgLogprob = 0.92, -1.4
+	unsure := segment(2, "本日の議題です")
+	unsure.NoSpeechProb = 0.8 // avg_logprob not reported: kept
+
+	segments := []Segment{
+		segment(0, "それでは始めます。"),
+		silent,
+		unsure,
+		segment(3, "ご視聴ありがとうございました。"),
+		segment(4, "ご視聴 ありがとうございました!チャンネル登録よろしくお願いします"),
+		segment(5, strings.Repeat("ありがとうございます。", 6)),
+		segment(6, "はい"), segment(7, "はい"), segment(8, "はい"), segment(9, "はい"),
+		segment(10, "はい、ありがとうございます。ご視聴ありがとうございましたと言っていました"),
+	}
+	phrases := []string{"ご視聴ありがとうございました", "チャンネル登録よろしくお願いします"}
+
+	kept, report := FilterHallucinations(segments, FilterOptions{Phrases: phrases})
+	texts := make([]string, len(kept))
+	for i, s := range kept {
+		texts[i] = s.Text
+	}
+	assert.Equal(t, []string{"それでは始めます。", "本日の議題です", "はい",
+		"はい、ありがとうございます。ご視聴ありがとうございましたと言っていました"}, texts)
+	assert.Equal(t, FilterReport{Total: 11, Removed: 7,
+		Reasons: map[string]int{ReasonNoSpeech: 1, ReasonPhrase: 2, ReasonRepetition: 4}}, report)
+	assert.Equal(t, "no_speech 1, phrase 2, repetition 4", report.String())
+	assert.Equal(t, "ご視聴ありがとうございました。", segments[3].Text, "the input is not modified")
+
+	flagged, report := FilterHallucinations(segments, FilterOptions{Phrases: phrases, Flag: true})
+	assert.Len(t, flagged, len(segments))
+	assert.Equal(t, 7, report.Flagged)
+	assert.Zero(t, report.Removed)
+	assert.Equal(t, ReasonPhrase, flagged[3].Suspect)
+	assert.Empty(t, flagged[0].Suspect)
+	assert.Empty(t, segments[3].Suspect)
+
+	_, report = FilterHallucinations([]Segment{segment(0, "hello")}, FilterOptions{})
+	assert.False(t, report.Changed())
+}
+
+func TestRepeatsItself(t *testing.T) {
+	assert.True(t, repeatsItself(tokens(strings.Repeat("ありがとうございます", 4))))
+	assert.True(t, repeatsItself(tokens("I'm sorry. I'm sorry. I'm sorry. I'm sorry.")))
+	assert.False(t, repeatsItself(tokens("ははははは")), "short laughter")
+	assert.False(t, repeatsItself(tokens("no no no no")))
+	assert.False(t, repeatsItself(tokens("今日はいい天気ですね。明日も晴れるといいですね。")))
+	assert.Greater(t, compressionRatio(strings.Repeat("the same words again ", 12)), compressionThreshold)
+	assert.Less(t, compressionRatio("それでは来週の予定について確認していきます。"), compressionThreshold)
+}
+
+func TestRender_Suspect(t *testing.T) {
+	doc := &Transcript{Segments: []Segment{
+		{Start: 0, End: time.Second, Text: "hello"},
+		{Start: time.Second, End: 2 * time.Second, Text: "Thanks for watching", Suspect: ReasonPhrase},
+	}}
+	data, err := Render(doc, "txt", Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n[?] Thanks for watching\n", string(data))
+
+	data, err = Render(doc, "json", Options{})
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"suspect": "phrase"`)
+}
@@ -0,0 +1,164 @@
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Formats are the output formats Render supports. The file extension is the
+// format itself.
+var Formats = []string{"txt", "vtt", "srt", "tsv", "json", "md"}
+
+// Options change how the formats are rendered
+type Options struct {
+	// WordTimestamps highlights the words of VTT cues one after another
+	// (karaoke style) when the segments have words
+	WordTimestamps bool
+	// LowConfidenceThreshold marks the words with a lower probability in txt
+	// and md, e.g. "[maybe this?]" (0 = off)
+	LowConfidenceThreshold float64
+}
+
+// Render returns t in format
+func Render(t *Transcript, format string, opts Options) ([]byte, error) {
+	var b strings.Builder
+	switch format {
+	case "txt":
+		for _, s := range t.Segments {
+			b.WriteString(suspectMark(s) + markLowConfidence(s, opts.LowConfidenceThreshold, "[", "?]") + "\n")
+		}
+	case "srt":
+		for i, s := range t.Segments {
+			fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1,
+				formatTimestamp(s.Start, true, ","), formatTimestamp(s.End, true, ","), suspectMark(s)+s.Text)
+		}
+	case "vtt":
+		b.WriteString("WEBVTT\n\n")
+		for _, s := range t.Segments {
+			hours := s.End >= time.Hour
+			text := s.Text
+			if opts.WordTimestamps && len(s.Words) > 0 {
+				text = vttWords(s.Words, hours)
+			}
+			text = suspectMark(s) + text
+			fmt.Fprintf(&b, "%s --> %s\n%s\n\n",
+				formatTimestamp(s.Start, hours, "."), formatTimestamp(s.End, hours, "."), text)
+		}
+	case "tsv":
+		b.WriteString("start\tend\ttext\n")
+		for _, s := range t.Segments {
+			fmt.Fprintf(&b, "%d\t%d\t%s\n", s.Start.Milliseconds(), s.End.Milliseconds(), suspectMark(s)+s.Text)
+		}
+	case "json":
+		data, err := json.MarshalIndent(t, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		b.Write(data)
+	case "md":
+		renderMarkdown(&b, t, opts)
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+	return []byte(b.String()), nil
+}
+
+// WriteFile writes t to path in format
+func WriteFile(path, format string, t *Transcript, opts Options) error {
+	data, err := Render(t, format, opts)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// renderMarkdown writes a heading with the file name and one paragraph per
+// segment, each starting with its time
+func renderMarkdown(b *strings.Builder, t *Transcript, opts Options) {
+	title := "Transcript"
+	if t.Source != "" {
+		title = strings.TrimSuffix(filepath.Base(t.Source), filepath.Ext(t.Source))
+	}
+	fmt.Fprintf(b, "# %s\n\n", title)
+	for _, s := range t.Segments {
+		fmt.Fprintf(b, "**[%s]** %s\n\n", formatClock(s.Start), suspectMark(s)+markLowConfidence(s, opts.LowConfidenceThreshold, "*[", "?]*"))
+	}
+}
+
+// suspectMark is put before the text of segments the hallucination filter
+// flagged
+func suspectMark(s Segment) string {
+	if s.Suspect == "" {
+		return ""
+	}
+	return "[?] "
+}
+
+// markLowConfidence returns the text of s with every run of words below
+// threshold between open and close. Segments without words are returned as
+// they are.
+func markLowConfidence(s Segment, threshold float64, open, close string) string {
+	if threshold <= 0 || len(s.Words) == 0 {
+		return s.Text
+	}
+	var b strings.Builder
+	marking := false
+	for _, w := range s.Words {
+		text := w.Text
+		low := w.Probability < threshold
+		if low && !marking {
+			// The space before the word stays outside the mark
+			word := strings.TrimLeft(text, " ")
+			b.WriteString(text[:len(text)-len(word)] + open)
+			text = word
+		} else if !low && marking {
+			b.WriteString(close)
+		}
+		marking = low
+		b.WriteString(text)
+	}
+	if marking {
+		b.WriteString(close)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// vttWords returns the cue text with a timestamp tag before every word, so
+// that players highlight the word being spoken
+func vttWords(words []Word, hours bool) string {
+	var b strings.Builder
+	for i, w := range words {
+		text := w.Text
+		if i == 0 {
+			text = strings.TrimLeft(text, " ")
+		} else {
+			fmt.Fprintf(&b, "<%s>", formatTimestamp(w.Start, hours, "."))
+		}
+		b.WriteString("<c>" + text + "</c>")
+	}
+	return b.String()
+}
+
+// formatTimestamp formats d as [HH:]MM:SS<sep>mmm like whisper's subtitle writers
+func formatTimestamp(d time.Duration, hours bool, sep string) string {
+	ms := d.Milliseconds()
+	h, m, s := ms/3600000, ms/60000%60, ms/1000%60
+	if hours {
+		return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, sep, ms%1000)
+	}
+	return fmt.Sprintf("%02d:%02d%s%03d", m+h*60, s, sep, ms%1000)
+}
+
+// formatClock formats d as [H:]MM:SS for reading
+func formatClock(d time.Duration) string {
+	total := int64(d / time.Second)
+	h, m, s := total/3600, total/60%60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
@@ -0,0 +1,83 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender(t *testing.T) {
+	doc := &Transcript{
+		Source:   "/data/input/weekly.wav",
+		Language: "en",
+		Segments: []Segment{
+			{Start: 1500 * time.Millisecond, End: 4 * time.Second, Text: "hello"},
+			{Start: time.Hour + 2*time.Second, End: time.Hour + 3250*time.Millisecond, Text: "world"},
+		},
+	}
+
+	tests := map[string]string{
+		"txt": "hello\nworld\n",
+		"srt": "1\n00:00:01,500 --> 00:00:04,000\nhello\n\n2\n01:00:02,000 --> 01:00:03,250\nworld\n\n",
+		"vtt": "WEBVTT\n\n00:01.500 --> 00:04.000\nhello\n\n01:00:02.000 --> 01:00:03.250\nworld\n\n",
+		"tsv": "start\tend\ttext\n1500\t4000\thello\n3602000\t3603250\tworld\n",
+		"md":  "# weekly\n\n**[00:01]** hello\n\n**[1:00:02]** world\n\n",
+	}
+	for format, expected := range tests {
+		data, err := Render(doc, format, Options{})
+		require.NoError(t, err, format)
+		assert.Equal(t, expected, string(data), format)
+	}
+
+	_, err := Render(doc, "docx", Options{})
+	assert.Error(t, err)
+}
+
+func TestRender_Words(t *testing.T) {
+	doc := &Transcript{Source: "lesson.mp4", Segments: []Segment{{
+		Start: time.Second, End: 3 * time.Second, Text: "Press the red button.",
+		Words: []Word{
+			{Start: time.Second, End: 1400 * time.Millisecond, Text: " Press", Probability: 0.98},
+			{Start: 1400 * time.Millisecond, End: 1600 * time.Millisecond, Text: " the", Probability: 0.42},
+			{Start: 1600 * time.Millisecond, End: 2 * time.Second, Text: " red", Probability: 0.31},
+			{Start: 2 * time.Second, End: 3 * time.Second, Text: " button.", Probability: 0.9},
+		},
+	}}}
+
+	data, err := Render(doc, "vtt", Options{WordTimestamps: true})
+	require.NoError(t, err)
+	assert.Equal(t, "WEBVTT\n\n00:01.000 --> 00:03.000\n"+
+		"<c>Press</c><00:01.400><c> the</c><00:01.600><c> red</c><00:02.000><c> button.</c>\n\n", string(data))
+
+	data, err = Render(doc, "vtt", Options{})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "\nPress the red button.\n", "words are highlighted only with word_timestamps")
+
+	opts := Options{LowConfidenceThreshold: 0.5}
+	data, err = Render(doc, "txt", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "Press [the red?] button.\n", string(data), "a run of uncertain words is marked once")
+
+	data, err = Render(doc, "md", opts)
+	require.NoError(t, err)
+	assert.Equal(t, "# lesson\n\n**[00:01]** Press *[the red?]* button.\n\n", string(data))
+
+	data, err = Render(doc, "srt", opts)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "\nPress the red button.\n", "subtitles are not marked")
+}
+
+func TestWriteFile(t *testing.T) {
+	doc := &Transcript{Segments: []Segment{{End: time.Second, Text: "こんにちは"}}}
+	path := filepath.Join(t.TempDir(), "out.txt")
+	require.NoError(t, WriteFile(path, "txt", doc, Options{}))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "こんにちは\n", string(data))
+
+	assert.Error(t, WriteFile(filepath.Join(t.TempDir(), "out.docx"), "docx", doc, Options{}))
+}
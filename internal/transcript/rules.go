@@ -0,0 +1,209 @@
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rule replaces text whisper keeps getting wrong, e.g. "コエモジ" with
+// "KoeMoji". The rules file is a JSON array of rules.
+type Rule struct {
+	From       string `json:"from"`                  // Literal text, or a regular expression with Regex
+	To         string `json:"to"`                    // Replacement; with Regex $1 etc. insert the groups
+	Regex      bool   `json:"regex,omitempty"`       // From is a regular expression (Go syntax)
+	IgnoreCase bool   `json:"ignore_case,omitempty"` // Match upper and lower case alike
+	WholeWord  bool   `json:"whole_word,omitempty"`  // Do not match inside a longer word (Latin letters and digits)
+}
+
+// Replacer applies rules to transcripts
+type Replacer struct {
+	rules    []Rule
+	patterns []*regexp.Regexp
+}
+
+// NewReplacer compiles rules. It returns an error naming the first invalid rule.
+func NewReplacer(rules []Rule) (*Replacer, error) {
+	r := &Replacer{rules: rules, patterns: make([]*regexp.Regexp, len(rules))}
+	for i, rule := range rules {
+		pattern, err := rule.compile()
+		if err != nil {
+			return nil, fmt.Errorf("rule %d (%q): %w", i+1, rule.From, err)
+		}
+		r.patterns[i] = pattern
+	}
+	return r, nil
+}
+
+func (rule Rule) compile() (*regexp.Regexp, error) {
+	if rule.From == "" {
+		return nil, fmt.Errorf("empty from")
+	}
+	expr := rule.From
+	if !rule.Regex {
+		expr = regexp.QuoteMeta(expr)
+	}
+	if rule.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	return regexp.Compile(expr)
+}
+
+// Replace applies every rule to text, in the order of the rules
+func (r *Replacer) Replace(text string) string {
+	for i, rule := range r.rules {
+		text = replaceMatches(text, r.patterns[i], rule)
+	}
+	return text
+}
+
+// Apply returns segments with the rules applied and how many segments
+// changed. Times stay as they are. The words are replaced one by one; when a
+// rule spans several words they no longer add up to the text and are
+// dropped from that segment. The segments passed in are not modified.
+func (r *Replacer) Apply(segments []Segment) ([]Segment, int) {
+	out := make([]Segment, len(segments))
+	changed := 0
+	for i, s := range segments {
+		text := r.Replace(s.Text)
+		if text != s.Text {
+			changed++
+			s.Text = text
+			s.Words = r.replaceWords(s.Words, text)
+		}
+		out[i] = s
+	}
+	return out, changed
+}
+
+// replaceWords applies the rules to every word, or returns nil when the
+// words do not make up text afterwards
+func (r *Replacer) replaceWords(words []Word, text string) []Word {
+	if len(words) == 0 {
+		return nil
+	}
+	replaced := make([]Word, len(words))
+	var joined strings.Builder
+	for i, w := range words {
+		w.Text = r.Replace(w.Text)
+		replaced[i] = w
+		joined.WriteString(w.Text)
+	}
+	if strings.TrimSpace(joined.String()) != text {
+		return nil
+	}
+	return replaced
+}
+
+// replaceMatches replaces the matches of pattern in text with rule.To
+func replaceMatches(text string, pattern *regexp.Regexp, rule Rule) string {
+	matches := pattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if m[0] == m[1] || (rule.WholeWord && !wholeWord(text, m[0], m[1])) {
+			continue
+		}
+		b.WriteString(text[last:m[0]])
+		if rule.Regex {
+			b.Write(pattern.ExpandString(nil, rule.To, text, m))
+		} else {
+			b.WriteString(rule.To)
+		}
+		last = m[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// wholeWord reports whether text[start:end] is not part of a longer word.
+// Only Latin-like letters and digits form words: Japanese has no spaces, so
+// a match next to kana or kanji always counts.
+func wholeWord(text string, start, end int) bool {
+	first, _ := utf8.DecodeRuneInString(text[start:end])
+	lastRune, _ := utf8.DecodeLastRuneInString(text[start:end])
+	if start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		if isWordRune(before) && isWordRune(first) {
+			return false
+		}
+	}
+	if end < len(text) {
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if isWordRune(after) && isWordRune(lastRune) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// LoadRules reads the rules file at path. A missing file has no rules.
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// SaveRules writes rules to path, through a temporary file
+func SaveRules(path string, rules []Rule) error {
+	if rules == nil {
+		rules = []Rule{}
+	}
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// AddRule adds rule to the rules file at path (creating it). A rule with
+// the same From and options is replaced, so fixing a rule does not add a
+// second one.
+func AddRule(path string, rule Rule) error {
+	if _, err := rule.compile(); err != nil {
+		return err
+	}
+	rules, err := LoadRules(path)
+	if err != nil {
+		return err
+	}
+	for i, existing := range rules {
+		if existing.From == rule.From && existing.Regex == rule.Regex &&
+			existing.IgnoreCase == rule.IgnoreCase && existing.WholeWord == rule.WholeWord {
+			rules[i] = rule
+			return SaveRules(path, rules)
+		}
+	}
+	return SaveRules(path, append(rules, rule))
+}
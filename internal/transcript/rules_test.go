@@ -0,0 +1,91 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplacer_Replace(t *testing.T) {
+	tests := []struct {
+		rule     Rule
+		input    string
+		expected string
+	}{
+		{Rule{From: "コエモジ", To: "KoeMoji"}, "コエモジの設定", "KoeMojiの設定"},
+		{Rule{From: "a.b", To: "x"}, "a.b axb", "x axb"},
+		{Rule{From: "koemoji", To: "KoeMoji", IgnoreCase: true}, "KOEMOJI and koeMoji", "KoeMoji and KoeMoji"},
+		{Rule{From: "AI", To: "A.I.", WholeWord: true}, "AI said hi to MAIL AI", "A.I. said hi to MAIL A.I."},
+		{Rule{From: "AI", To: "エーアイ", WholeWord: true}, "生成AIの話", "生成エーアイの話"},
+		{Rule{From: `(\d+)時(\d+)分`, To: "$1:$2", Regex: true}, "10時30分から", "10:30から"},
+		{Rule{From: "$1", To: "$2"}, "cost $1", "cost $2"},
+		{Rule{From: "x*", To: "y", Regex: true}, "abc", "abc"},
+	}
+	for _, tt := range tests {
+		replacer, err := NewReplacer([]Rule{tt.rule})
+		require.NoError(t, err, tt.rule.From)
+		assert.Equal(t, tt.expected, replacer.Replace(tt.input), tt.rule.From)
+	}
+
+	_, err := NewReplacer([]Rule{{From: "ok", To: "OK"}, {From: "(", Regex: true}})
+	assert.ErrorContains(t, err, "rule 2")
+	_, err = NewReplacer([]Rule{{To: "nothing"}})
+	assert.Error(t, err)
+}
+
+func TestReplacer_Apply(t *testing.T) {
+	replacer, err := NewReplacer([]Rule{
+		{From: "cube", To: "Kube", WholeWord: true},
+		{From: "koe moji", To: "KoeMoji", IgnoreCase: true},
+	})
+	require.NoError(t, err)
+
+	segments := []Segment{
+		{Start: time.Second, End: 2 * time.Second, Text: "Deploy cube now", Words: []Word{
+			{Start: time.Second, End: 1300 * time.Millisecond, Text: " Deploy"},
+			{Start: 1300 * time.Millisecond, End: 1600 * time.Millisecond, Text: " cube"},
+			{Start: 1600 * time.Millisecond, End: 2 * time.Second, Text: " now"},
+		}},
+		{Start: 2 * time.Second, End: 3 * time.Second, Text: "Koe moji works", Words: []Word{
+			{Text: " Koe"}, {Text: " moji"}, {Text: " works"},
+		}},
+		{Start: 3 * time.Second, End: 4 * time.Second, Text: "unchanged"},
+	}
+
+	out, changed := replacer.Apply(segments)
+	assert.Equal(t, 2, changed)
+	assert.Equal(t, "Deploy Kube now", out[0].Text)
+	assert.Equal(t, " Kube", out[0].Words[1].Text, "words are replaced one by one")
+	assert.Equal(t, 1300*time.Millisecond, out[0].Words[1].Start)
+	assert.Equal(t, "KoeMoji works", out[1].Text)
+	assert.Nil(t, out[1].Words, "a rule spanning words drops the word timing")
+	assert.Equal(t, 2*time.Second, out[1].Start, "segment times stay")
+	assert.Equal(t, "Deploy cube now", segments[0].Text, "the input is not modified")
+	assert.Equal(t, " cube", segments[0].Words[1].Text)
+}
+
+func TestAddRule(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules", "replacements.json")
+
+	rules, err := LoadRules(path)
+	require.NoError(t, err, "no file, no rules")
+	assert.Empty(t, rules)
+
+	require.NoError(t, AddRule(path, Rule{From: "コエモジ", To: "こえもじ"}))
+	require.NoError(t, AddRule(path, Rule{From: "議事禄", To: "議事録"}))
+	require.NoError(t, AddRule(path, Rule{From: "コエモジ", To: "KoeMoji"}))
+	assert.Error(t, AddRule(path, Rule{From: "[", Regex: true}))
+
+	rules, err = LoadRules(path)
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{{From: "コエモジ", To: "KoeMoji"}, {From: "議事禄", To: "議事録"}}, rules,
+		"the same rule is updated instead of added twice")
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0644))
+	_, err = LoadRules(path)
+	assert.Error(t, err)
+}
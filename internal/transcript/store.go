@@ -0,0 +1,48 @@
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Path returns where the transcript of the job with the given content hash
+// is stored in dir
+func Path(dir, hash string) string {
+	return filepath.Join(dir, hash+".json")
+}
+
+// Save stores t in dir under the content hash of its input. The file is
+// written through a temporary file so that a crash never leaves half of it.
+func Save(dir, hash string, t *Transcript) error {
+	if hash == "" {
+		return fmt.Errorf("no content hash for %s", filepath.Base(t.Source))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := Path(dir, hash)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load reads the transcript stored under hash in dir
+func Load(dir, hash string) (*Transcript, error) {
+	data, err := os.ReadFile(Path(dir, hash))
+	if err != nil {
+		return nil, err
+	}
+	var t Transcript
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid transcript %s: %w", Path(dir, hash), err)
+	}
+	return &t, nil
+}
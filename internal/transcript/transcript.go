@@ -0,0 +1,179 @@
+// Package transcript is the canonical form of a transcription: the segments
+// with their timings, words and confidence, independent of the backend that
+// produced them. The output formats are rendered from it, and it is stored
+// per job so that any format can be exported again later.
+package transcript
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+)
+
+// Word is one word of a segment with its own timing (word timestamps)
+type Word struct {
+	Start       time.Duration
+	End         time.Duration
+	Text        string // As whisper returns it, usually with a leading space
+	Probability float64
+}
+
+// Segment is one stretch of recognized speech. AvgLogprob and NoSpeechProb
+// are zero when the backend does not report them.
+type Segment struct {
+	Start        time.Duration
+	End          time.Duration
+	Text         string
+	Words        []Word
+	AvgLogprob   float64
+	NoSpeechProb float64
+	Suspect      string // Why the hallucination filter flagged the segment (Reason*); empty = not flagged
+}
+
+// Transcript is the transcription of one input file
+type Transcript struct {
+	Source    string // Input file the transcript was made from
+	Language  string
+	Backend   string
+	Model     string
+	CreatedAt time.Time
+	Segments  []Segment
+}
+
+// Text returns the text of all segments
+func (t *Transcript) Text() string {
+	texts := make([]string, len(t.Segments))
+	for i, s := range t.Segments {
+		texts[i] = s.Text
+	}
+	return strings.Join(texts, " ")
+}
+
+// jsonWord, jsonSegment and jsonTranscript are the JSON form, the same as
+// whisper's JSON output (times in seconds) with the metadata added
+type jsonWord struct {
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	Word        string  `json:"word"`
+	Probability float64 `json:"probability"`
+}
+
+type jsonSegment struct {
+	Start        float64    `json:"start"`
+	End          float64    `json:"end"`
+	Text         string     `json:"text"`
+	AvgLogprob   float64    `json:"avg_logprob,omitempty"`
+	NoSpeechProb float64    `json:"no_speech_prob,omitempty"`
+	Suspect      string     `json:"suspect,omitempty"`
+	Words        []jsonWord `json:"words,omitempty"`
+}
+
+type numberedSegment struct {
+	ID int `json:"id"`
+	jsonSegment
+}
+
+type jsonTranscript struct {
+	Text      string            `json:"text"`
+	Segments  []numberedSegment `json:"segments"`
+	Language  string            `json:"language"`
+	Source    string            `json:"source,omitempty"`
+	Backend   string            `json:"backend,omitempty"`
+	Model     string            `json:"model,omitempty"`
+	CreatedAt *time.Time        `json:"created_at,omitempty"`
+}
+
+// seconds converts d to the seconds used in JSON
+func seconds(d time.Duration) float64 {
+	return d.Seconds()
+}
+
+// duration converts seconds from JSON, rounded to milliseconds
+func duration(s float64) time.Duration {
+	return time.Duration(math.Round(s*1000)) * time.Millisecond
+}
+
+func (s Segment) toJSON() jsonSegment {
+	out := jsonSegment{
+		Start:        seconds(s.Start),
+		End:          seconds(s.End),
+		Text:         s.Text,
+		AvgLogprob:   s.AvgLogprob,
+		NoSpeechProb: s.NoSpeechProb,
+		Suspect:      s.Suspect,
+	}
+	for _, w := range s.Words {
+		out.Words = append(out.Words, jsonWord{Start: seconds(w.Start), End: seconds(w.End), Word: w.Text, Probability: w.Probability})
+	}
+	return out
+}
+
+func (s *Segment) fromJSON(in jsonSegment) {
+	*s = Segment{
+		Start:        duration(in.Start),
+		End:          duration(in.End),
+		Text:         strings.TrimSpace(in.Text),
+		AvgLogprob:   in.AvgLogprob,
+		NoSpeechProb: in.NoSpeechProb,
+		Suspect:      in.Suspect,
+	}
+	for _, w := range in.Words {
+		s.Words = append(s.Words, Word{Start: duration(w.Start), End: duration(w.End), Text: w.Word, Probability: w.Probability})
+	}
+}
+
+// MarshalJSON writes the segment like a whisper JSON segment
+func (s Segment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.toJSON())
+}
+
+// UnmarshalJSON reads a whisper JSON segment
+func (s *Segment) UnmarshalJSON(data []byte) error {
+	var in jsonSegment
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	s.fromJSON(in)
+	return nil
+}
+
+// MarshalJSON writes the transcript like whisper's JSON output
+func (t Transcript) MarshalJSON() ([]byte, error) {
+	out := jsonTranscript{
+		Text:     t.Text(),
+		Segments: make([]numberedSegment, len(t.Segments)),
+		Language: t.Language,
+		Source:   t.Source,
+		Backend:  t.Backend,
+		Model:    t.Model,
+	}
+	if !t.CreatedAt.IsZero() {
+		out.CreatedAt = &t.CreatedAt
+	}
+	for i, s := range t.Segments {
+		out.Segments[i] = numberedSegment{ID: i, jsonSegment: s.toJSON()}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON reads a stored transcript or the JSON output of whisper
+// (whisper-ctranslate2 --output_format json)
+func (t *Transcript) UnmarshalJSON(data []byte) error {
+	var in jsonTranscript
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	*t = Transcript{Language: in.Language, Source: in.Source, Backend: in.Backend, Model: in.Model}
+	if in.CreatedAt != nil {
+		t.CreatedAt = *in.CreatedAt
+	}
+	for _, s := range in.Segments {
+		var segment Segment
+		segment.fromJSON(s.jsonSegment)
+		if segment.Text != "" {
+			t.Segments = append(t.Segments, segment)
+		}
+	}
+	return nil
+}
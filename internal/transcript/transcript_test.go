@@ -0,0 +1,59 @@
+package transcript
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranscript_JSON(t *testing.T) {
+	doc := &Transcript{
+		Source:    "/data/input/call.wav",
+		Language:  "ja",
+		Backend:   "whisper-ctranslate2",
+		Model:     "large-v3",
+		CreatedAt: time.Date(2026, 4, 1, 9, 30, 0, 0, time.UTC),
+		Segments: []Segment{{
+			Start: 250 * time.Millisecond, End: 2 * time.Second, Text: "お疲れ様です",
+			Words: []Word{
+				{Start: 250 * time.Millisecond, End: time.Second, Text: "お疲れ", Probability: 0.88},
+				{Start: time.Second, End: 2 * time.Second, Text: "様です", Probability: 0.64},
+			},
+			AvgLogprob: -0.31, NoSpeechProb: 0.02,
+		}},
+	}
+
+	data, err := Render(doc, "json", Options{})
+	require.NoError(t, err)
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, "お疲れ様です", raw["text"])
+	segment := raw["segments"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, 0.0, segment["id"])
+	assert.Equal(t, 0.25, segment["start"], "times are in seconds like whisper's JSON")
+	assert.Equal(t, -0.31, segment["avg_logprob"])
+	assert.Len(t, segment["words"], 2)
+
+	var loaded Transcript
+	require.NoError(t, json.Unmarshal(data, &loaded))
+	assert.Equal(t, *doc, loaded)
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	doc := &Transcript{Source: "memo.wav", Language: "ja", Segments: []Segment{{End: time.Second, Text: "メモ"}}}
+
+	assert.Error(t, Save(dir, "", doc), "a transcript needs the content hash of its input")
+	require.NoError(t, Save(dir, "abc123", doc))
+	assert.FileExists(t, Path(dir, "abc123"))
+
+	loaded, err := Load(dir, "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, doc, loaded)
+
+	_, err = Load(dir, "missing")
+	assert.Error(t, err)
+}
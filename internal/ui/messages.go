@@ -8,6 +8,7 @@ type Messages struct {
 	Active     string
 	Processing string
 	Queue      string
+	Waiting    string
 	None       string
 	Input      string
 	Output     string
@@ -26,6 +27,10 @@ type Messages struct {
 	InputDirCmd  string
 	OutputDirCmd string
 	RecordCmd    string
+	CancelJobCmd string
+	DequeueCmd   string
+	ProcessCmd   string
+	RuleCmd      string
 
 	// Log levels
 	LogInfo  string
@@ -45,6 +50,68 @@ type Messages struct {
 	ProcessComplete string
 	ProcessFailed   string
 	MovingToArchive string
+	WatchingDir     string
+	WatcherFailed   string
+	RetryScheduled  string
+	MovedToFailed   string
+
+	// Cancellation messages
+	ProcessCancelled   string
+	ProcessInterrupted string
+	RemovedFromQueue   string
+
+	// Processing profile messages
+	ProfileApplied string
+	ProfileInvalid string
+
+	// Duplicate content messages
+	DuplicateReused      string
+	DuplicateSkipped     string
+	DuplicateReuseFailed string
+	DuplicateHeld        string
+
+	// Post-processing messages (hallucination filter, replacement rules, clean transcript)
+	HallucinationsRemoved string
+	HallucinationsFlagged string
+	ReplacementsApplied   string
+	ReplacementsInvalid   string
+	OutputRewriteFailed   string
+	CleanTranscriptSaved  string
+	CleanTranscriptFailed string
+
+	// Pause while recording messages
+	ProcessingPaused   string
+	ProcessingResumed  string
+	PausedForRecording string
+
+	// Processing schedule messages
+	NextWindow        string
+	ScheduleDeferred  string
+	ScheduleInvalid   string
+	ProcessNowStarted string
+	ProcessNowEmpty   string
+
+	// Queue ETA
+	ETA        string
+	ETACurrent string
+	ETATotal   string
+	ETAUnknown string
+
+	// Transcription progress
+	LastHeard string
+
+	// Failure report (.error.txt next to files moved to the failed folder)
+	FailureReportTitle    string
+	FailureReportFile     string
+	FailureReportTime     string
+	FailureReportAttempts string
+	FailureReportReason   string
+	FailureReportDetails  string
+	FailureReportRetry    string
+	FailureKindTransient  string
+	FailureKindGPU        string
+	FailureKindOutput     string
+	FailureKindSetup      string
 
 	// Error messages
 	LogFileError    string
@@ -71,25 +138,58 @@ type Messages struct {
 	DirectoriesTab string
 	LLMTab         string
 	RecordingTab   string
+	ProcessingTab  string
 	SaveBtn        string
 	CancelBtn      string
 
 	// Settings form labels
-	LanguageLabel        string
-	WhisperModelLabel    string
-	SpeechLanguageLabel  string
-	ScanIntervalLabel    string
-	UseColorsLabel       string
-	InputDirLabel        string
-	OutputDirLabel       string
-	ArchiveDirLabel      string
-	LLMEnabledLabel      string
-	APIKeyLabel          string
-	ModelLabel           string
-	PromptTemplateLabel  string
-	RecordingDeviceLabel string
-	DualRecordingLabel   string
-	BrowseBtn            string
+	LanguageLabel          string
+	WhisperModelLabel      string
+	SpeechLanguageLabel    string
+	ScanIntervalLabel      string
+	BackendLabel           string
+	OutputFormatsLabel     string
+	UseColorsLabel         string
+	InputDirLabel          string
+	OutputDirLabel         string
+	ArchiveDirLabel        string
+	FailedDirLabel         string
+	LLMEnabledLabel        string
+	APIKeyLabel            string
+	ModelLabel             string
+	PromptTemplateLabel    string
+	RecordingDeviceLabel   string
+	DualRecordingLabel     string
+	MaxConcurrentJobsLabel string
+	MaxCPUPercentLabel     string
+	WatchModeLabel         string
+	FileSettleLabel        string
+	MaxRetriesLabel        string
+	RecursiveScanLabel     string
+	PauseRecordingLabel    string
+	ScheduleLabel          string
+	ChunkMinutesLabel      string
+	WordTimestampsLabel    string
+	LowConfidenceLabel     string
+	WatchModeWatchOption   string
+	WatchModePollOption    string
+	DuplicateActionLabel   string
+	DuplicateReuseOption   string
+	DuplicateSkipOption    string
+	DuplicateProcessOption string
+	HallucinationLabel     string
+	HallucinationDrop      string
+	HallucinationFlag      string
+	HallucinationOff       string
+	VocabularyLabel        string
+	VocabularyModeLabel    string
+	VocabPromptOption      string
+	VocabHotwordsOption    string
+	CleanTranscriptLabel   string
+	SummarySourceLabel     string
+	SummaryCleanOption     string
+	SummaryVerbatimOption  string
+	BrowseBtn              string
 
 	// Additional GUI messages
 	LogPlaceholder           string
@@ -108,6 +208,21 @@ type Messages struct {
 	DependencyError          string
 	ConfigError              string
 	ConfigLoadErrorDialog    string
+	JobStoreLoadError        string
+	SelectFileToCancel       string
+	SelectFileToDequeue      string
+	NoProcessingFiles        string
+	NoQueuedFiles            string
+	NotProcessing            string
+	NotQueued                string
+	AddRuleTitle             string
+	RuleSourceLabel          string
+	RuleFromLabel            string
+	RuleToLabel              string
+	RuleRegexOption          string
+	RuleIgnoreCaseOption     string
+	RuleWholeWordOption      string
+	RuleAdded                string
 }
 
 var messagesEN = Messages{
@@ -115,6 +230,7 @@ var messagesEN = Messages{
 	Active:     "Active",
 	Processing: "Processing",
 	Queue:      "Queue",
+	Waiting:    "Waiting",
 	None:       "None",
 	Input:      "Input",
 	Output:     "Output",
@@ -133,6 +249,10 @@ var messagesEN = Messages{
 	InputDirCmd:  "input",
 	OutputDirCmd: "output",
 	RecordCmd:    "record",
+	CancelJobCmd: "stop",
+	DequeueCmd:   "dequeue",
+	ProcessCmd:   "process now",
+	RuleCmd:      "add rule",
 
 	// Log levels
 	LogInfo:  "INFO",
@@ -152,6 +272,68 @@ var messagesEN = Messages{
 	ProcessComplete: "Completed %s in %s",
 	ProcessFailed:   "Failed to process %s: %v",
 	MovingToArchive: "Moving %s to archive",
+	WatchingDir:     "Watching %s for new files (full scan every %d minutes)",
+	WatcherFailed:   "File watcher unavailable, using periodic scan only: %v",
+	RetryScheduled:  "Retrying %s in %s (attempt %d of %d)",
+	MovedToFailed:   "Gave up on %s, moved to %s",
+
+	// Cancellation messages
+	ProcessCancelled:   "Stopped %s (the file stays in the input folder)",
+	ProcessInterrupted: "Interrupted %s on shutdown, it will be processed again on next start",
+	RemovedFromQueue:   "Removed %s from the queue (the file stays in the input folder)",
+
+	// Processing profile messages
+	ProfileApplied: "Profile %s for %s (model: %s, language: %s)",
+	ProfileInvalid: "Invalid profile for %s: %v",
+
+	// Duplicate content messages
+	DuplicateReused:      "%s has the same content as %s, reused its transcript",
+	DuplicateSkipped:     "%s has the same content as %s, moved to archive without transcribing",
+	DuplicateReuseFailed: "Could not reuse earlier outputs for %s, transcribing it: %v",
+	DuplicateHeld:        "%s has the same content as a file being transcribed, waiting for it",
+
+	// Post-processing messages (hallucination filter, replacement rules, clean transcript)
+	HallucinationsRemoved: "%s: removed %d of %d segments as likely hallucinations (%s)",
+	HallucinationsFlagged: "%s: flagged %d of %d segments as likely hallucinations (%s)",
+	ReplacementsApplied:   "%s: replacement rules changed %d segments",
+	ReplacementsInvalid:   "Replacement rules not applied: %v",
+	OutputRewriteFailed:   "Failed to rewrite the outputs of %s: %v",
+	CleanTranscriptSaved:  "%s: clean transcript saved (%s)",
+	CleanTranscriptFailed: "Failed to write the clean transcript of %s: %v",
+
+	// Pause while recording messages
+	ProcessingPaused:   "Recording started, transcription paused",
+	ProcessingResumed:  "Recording finished, transcription resumed",
+	PausedForRecording: "Paused while recording",
+
+	// Processing schedule messages
+	NextWindow:        "Next processing window",
+	ScheduleDeferred:  "Outside the processing schedule, %d queued files wait until %s",
+	ScheduleInvalid:   "Invalid processing_schedule, files are processed at any time: %v",
+	ProcessNowStarted: "Processing %d queued files now (schedule ignored until the queue is empty)",
+	ProcessNowEmpty:   "No queued files to process",
+
+	// Queue ETA
+	ETA:        "ETA",
+	ETACurrent: "current",
+	ETATotal:   "queue",
+	ETAUnknown: "unknown",
+
+	// Transcription progress
+	LastHeard: "Last heard",
+
+	// Failure report
+	FailureReportTitle:    "KoeMoji-Go could not transcribe this file.",
+	FailureReportFile:     "File",
+	FailureReportTime:     "Failed at",
+	FailureReportAttempts: "Attempts",
+	FailureReportReason:   "Reason",
+	FailureReportDetails:  "Error details",
+	FailureReportRetry:    "To try again, fix the cause and move the file back into the input folder.",
+	FailureKindTransient:  "The transcription process kept failing",
+	FailureKindGPU:        "GPU or memory problem",
+	FailureKindOutput:     "No transcription was produced (the file may be corrupted or silent)",
+	FailureKindSetup:      "Environment problem (speech recognition engine or folders)",
 
 	// Error messages
 	LogFileError:    "Failed to open log file: %v",
@@ -178,25 +360,58 @@ var messagesEN = Messages{
 	DirectoriesTab: "Directories",
 	LLMTab:         "AI Summary",
 	RecordingTab:   "Recording",
+	ProcessingTab:  "Processing",
 	SaveBtn:        "Save",
 	CancelBtn:      "Cancel",
 
 	// Settings form labels
-	LanguageLabel:        "Language",
-	WhisperModelLabel:    "Whisper Model",
-	SpeechLanguageLabel:  "Speech Recognition Language",
-	ScanIntervalLabel:    "Scan Interval (min)",
-	UseColorsLabel:       "Use Colors",
-	InputDirLabel:        "Input Folder",
-	OutputDirLabel:       "Output Folder",
-	ArchiveDirLabel:      "Archive Folder",
-	LLMEnabledLabel:      "Enable AI Summary",
-	APIKeyLabel:          "API Key",
-	ModelLabel:           "Model",
-	PromptTemplateLabel:  "Prompt Template",
-	RecordingDeviceLabel: "Recording Device",
-	DualRecordingLabel:   "Dual Recording (System Audio + Mic)",
-	BrowseBtn:            "Browse...",
+	LanguageLabel:          "Language",
+	WhisperModelLabel:      "Whisper Model",
+	SpeechLanguageLabel:    "Speech Recognition Language",
+	ScanIntervalLabel:      "Scan Interval (min)",
+	BackendLabel:           "Transcription Backend",
+	OutputFormatsLabel:     "Output Formats",
+	UseColorsLabel:         "Use Colors",
+	InputDirLabel:          "Input Folder",
+	OutputDirLabel:         "Output Folder",
+	ArchiveDirLabel:        "Archive Folder",
+	FailedDirLabel:         "Failed Folder",
+	LLMEnabledLabel:        "Enable AI Summary",
+	APIKeyLabel:            "API Key",
+	ModelLabel:             "Model",
+	PromptTemplateLabel:    "Prompt Template",
+	RecordingDeviceLabel:   "Recording Device",
+	DualRecordingLabel:     "Dual Recording (System Audio + Mic)",
+	MaxConcurrentJobsLabel: "Parallel Transcriptions",
+	MaxCPUPercentLabel:     "Max CPU Usage (%)",
+	WatchModeLabel:         "Input Watch Mode",
+	FileSettleLabel:        "File Settle Time (sec)",
+	MaxRetriesLabel:        "Retries on Failure",
+	RecursiveScanLabel:     "Include Subfolders (outputs keep folder structure)",
+	PauseRecordingLabel:    "Pause transcription while recording",
+	ScheduleLabel:          "Processing Schedule (e.g. mon-fri 18:00-08:00; weekends, empty = any time)",
+	ChunkMinutesLabel:      "Split Long Audio Every (minutes, 0 = off)",
+	WordTimestampsLabel:    "Word Timestamps",
+	LowConfidenceLabel:     "Mark Words Below Probability (0 = off)",
+	WatchModeWatchOption:   "Detect immediately (file watcher)",
+	WatchModePollOption:    "Periodic scan only (network shares)",
+	DuplicateActionLabel:   "Already Transcribed Content",
+	DuplicateReuseOption:   "Reuse earlier transcript",
+	DuplicateSkipOption:    "Move to archive",
+	DuplicateProcessOption: "Transcribe again",
+	HallucinationLabel:     "Likely Hallucinations (phantom phrases, repeats)",
+	HallucinationDrop:      "Remove",
+	HallucinationFlag:      "Keep, marked with [?]",
+	HallucinationOff:       "Keep everything",
+	VocabularyLabel:        "Custom Vocabulary (names and terms, one per line)",
+	VocabularyModeLabel:    "Give Vocabulary As",
+	VocabPromptOption:      "Initial prompt",
+	VocabHotwordsOption:    "Hotwords (whisper-ctranslate2 only)",
+	CleanTranscriptLabel:   "Also Write Clean Transcript (no fillers, punctuation, width)",
+	SummarySourceLabel:     "Summarize",
+	SummaryCleanOption:     "Clean transcript (when written)",
+	SummaryVerbatimOption:  "Verbatim transcript",
+	BrowseBtn:              "Browse...",
 
 	// Additional GUI messages
 	LogPlaceholder:           "**Waiting for log entries...**",
@@ -215,6 +430,21 @@ var messagesEN = Messages{
 	DependencyError:          "Audio recognition engine (Whisper) automatic installation failed: %v\n\nPossible causes:\n• Python 3.12 not installed\n• Network connection issue\n• Insufficient permissions\n\nSolution:\n1. Install Python 3.12\n2. Restart the application (automatic installation will retry)\n\nIf the problem persists, manually install:\n  pip install faster-whisper whisper-ctranslate2",
 	ConfigError:              "Configuration Error",
 	ConfigLoadErrorDialog:    "Failed to load configuration: %v\n\nUsing default configuration.",
+	JobStoreLoadError:        "Failed to load job store: %v",
+	SelectFileToCancel:       "Stop Transcription",
+	SelectFileToDequeue:      "Remove from Queue",
+	NoProcessingFiles:        "No file is being processed",
+	NoQueuedFiles:            "The queue is empty",
+	NotProcessing:            "%s is no longer being processed",
+	NotQueued:                "%s is no longer in the queue",
+	AddRuleTitle:             "Add replacement rule",
+	RuleSourceLabel:          "Start from line",
+	RuleFromLabel:            "Replace",
+	RuleToLabel:              "With",
+	RuleRegexOption:          "Regular expression",
+	RuleIgnoreCaseOption:     "Ignore case",
+	RuleWholeWordOption:      "Whole words only",
+	RuleAdded:                "Added to %s. It applies from the next transcription.",
 }
 
 var messagesJA = Messages{
@@ -222,6 +452,7 @@ var messagesJA = Messages{
 	Active:     "稼働中",
 	Processing: "処理中",
 	Queue:      "待機",
+	Waiting:    "書込待ち",
 	None:       "なし",
 	Input:      "入力",
 	Output:     "出力",
@@ -240,6 +471,10 @@ var messagesJA = Messages{
 	InputDirCmd:  "入力",
 	OutputDirCmd: "出力",
 	RecordCmd:    "録音",
+	CancelJobCmd: "処理中止",
+	DequeueCmd:   "キュー削除",
+	ProcessCmd:   "今すぐ処理",
+	RuleCmd:      "置換ルール",
 
 	// Log levels
 	LogInfo:  "情報",
@@ -259,6 +494,68 @@ var messagesJA = Messages{
 	ProcessComplete: "%sの処理を完了 (処理時間: %s)",
 	ProcessFailed:   "%sの処理に失敗: %v",
 	MovingToArchive: "%sをアーカイブに移動",
+	WatchingDir:     "%s を監視しています（%d分ごとに全体スキャン）",
+	WatcherFailed:   "ファイル監視を開始できません。定期スキャンのみで継続します: %v",
+	RetryScheduled:  "%sを%s後に再試行します（%d/%d回目）",
+	MovedToFailed:   "%sの処理を断念し、%sに移動しました",
+
+	// Cancellation messages
+	ProcessCancelled:   "%sの処理を中止しました（ファイルは入力フォルダに残ります）",
+	ProcessInterrupted: "終了のため%sの処理を中断しました。次回起動時に再処理します",
+	RemovedFromQueue:   "%sをキューから削除しました（ファイルは入力フォルダに残ります）",
+
+	// Processing profile messages
+	ProfileApplied: "プロファイル%sを%sに適用（モデル: %s、言語: %s）",
+	ProfileInvalid: "%sのプロファイルが不正です: %v",
+
+	// Duplicate content messages
+	DuplicateReused:      "%sは%sと同じ内容のため、文字起こし結果を再利用しました",
+	DuplicateSkipped:     "%sは%sと同じ内容のため、文字起こしせずアーカイブに移動しました",
+	DuplicateReuseFailed: "%sで以前の結果を再利用できないため、文字起こしします: %v",
+	DuplicateHeld:        "%sは処理中のファイルと同じ内容のため、完了を待ちます",
+
+	// Post-processing messages (hallucination filter, replacement rules, clean transcript)
+	HallucinationsRemoved: "%s: %d/%dセグメントを幻覚（誤認識）の可能性が高いため削除しました (%s)",
+	HallucinationsFlagged: "%s: %d/%dセグメントに幻覚（誤認識）の可能性があるため印を付けました (%s)",
+	ReplacementsApplied:   "%s: 置換ルールで%dセグメントを修正しました",
+	ReplacementsInvalid:   "置換ルールを適用できませんでした: %v",
+	OutputRewriteFailed:   "%sの出力を書き直せませんでした: %v",
+	CleanTranscriptSaved:  "%s: 整文版を保存しました (%s)",
+	CleanTranscriptFailed: "%sの整文版を書き込めませんでした: %v",
+
+	// Pause while recording messages
+	ProcessingPaused:   "録音を開始したため、文字起こしを一時停止しました",
+	ProcessingResumed:  "録音が終了したため、文字起こしを再開しました",
+	PausedForRecording: "録音中のため一時停止",
+
+	// Processing schedule messages
+	NextWindow:        "次の処理時間帯",
+	ScheduleDeferred:  "処理時間帯外のため、キューの%d件は%sから処理します",
+	ScheduleInvalid:   "processing_scheduleが不正なため、時間帯を制限せずに処理します: %v",
+	ProcessNowStarted: "キューの%d件を今すぐ処理します（キューが空になるまで時間帯を無視）",
+	ProcessNowEmpty:   "処理待ちのファイルはありません",
+
+	// Queue ETA
+	ETA:        "残り時間",
+	ETACurrent: "処理中",
+	ETATotal:   "全体",
+	ETAUnknown: "不明",
+
+	// Transcription progress
+	LastHeard: "直近の認識",
+
+	// Failure report
+	FailureReportTitle:    "KoeMoji-Goはこのファイルを文字起こしできませんでした。",
+	FailureReportFile:     "ファイル",
+	FailureReportTime:     "失敗日時",
+	FailureReportAttempts: "試行回数",
+	FailureReportReason:   "原因",
+	FailureReportDetails:  "エラー詳細",
+	FailureReportRetry:    "再処理するには、原因を解消してからファイルをinputフォルダに戻してください。",
+	FailureKindTransient:  "文字起こし処理が繰り返し失敗しました",
+	FailureKindGPU:        "GPUまたはメモリの問題",
+	FailureKindOutput:     "文字起こし結果が生成されませんでした（ファイル破損または無音の可能性）",
+	FailureKindSetup:      "実行環境の問題（音声認識エンジンまたはフォルダ）",
 
 	// Error messages
 	LogFileError:    "ログファイルを開けません: %v",
@@ -285,25 +582,58 @@ var messagesJA = Messages{
 	DirectoriesTab: "フォルダ設定",
 	LLMTab:         "AI要約",
 	RecordingTab:   "録音設定",
+	ProcessingTab:  "処理設定",
 	SaveBtn:        "保存",
 	CancelBtn:      "キャンセル",
 
 	// Settings form labels
-	LanguageLabel:        "言語",
-	WhisperModelLabel:    "Whisperモデル",
-	SpeechLanguageLabel:  "音声認識言語",
-	ScanIntervalLabel:    "スキャン間隔（分）",
-	UseColorsLabel:       "色を使用",
-	InputDirLabel:        "入力フォルダ",
-	OutputDirLabel:       "出力フォルダ",
-	ArchiveDirLabel:      "アーカイブフォルダ",
-	LLMEnabledLabel:      "AI要約を有効化",
-	APIKeyLabel:          "APIキー",
-	ModelLabel:           "モデル",
-	PromptTemplateLabel:  "プロンプトテンプレート",
-	RecordingDeviceLabel: "録音デバイス",
-	DualRecordingLabel:   "デュアル録音（システム音声+マイク）",
-	BrowseBtn:            "参照...",
+	LanguageLabel:          "言語",
+	WhisperModelLabel:      "Whisperモデル",
+	SpeechLanguageLabel:    "音声認識言語",
+	ScanIntervalLabel:      "スキャン間隔（分）",
+	BackendLabel:           "文字起こしエンジン",
+	OutputFormatsLabel:     "出力形式",
+	UseColorsLabel:         "色を使用",
+	InputDirLabel:          "入力フォルダ",
+	OutputDirLabel:         "出力フォルダ",
+	ArchiveDirLabel:        "アーカイブフォルダ",
+	FailedDirLabel:         "失敗ファイルフォルダ",
+	LLMEnabledLabel:        "AI要約を有効化",
+	APIKeyLabel:            "APIキー",
+	ModelLabel:             "モデル",
+	PromptTemplateLabel:    "プロンプトテンプレート",
+	RecordingDeviceLabel:   "録音デバイス",
+	DualRecordingLabel:     "デュアル録音（システム音声+マイク）",
+	MaxConcurrentJobsLabel: "同時文字起こし数",
+	MaxCPUPercentLabel:     "CPU使用率上限（%）",
+	WatchModeLabel:         "入力フォルダ監視モード",
+	FileSettleLabel:        "ファイル安定待ち時間（秒）",
+	MaxRetriesLabel:        "失敗時のリトライ回数",
+	RecursiveScanLabel:     "サブフォルダも処理（出力は同じフォルダ構成）",
+	PauseRecordingLabel:    "録音中は文字起こしを一時停止",
+	ScheduleLabel:          "処理時間帯（例: mon-fri 18:00-08:00; weekends、空欄で常時）",
+	ChunkMinutesLabel:      "長時間音声の分割（分、0でオフ）",
+	WordTimestampsLabel:    "単語ごとのタイムスタンプ",
+	LowConfidenceLabel:     "信頼度の低い単語をマーク（確率、0でオフ）",
+	WatchModeWatchOption:   "即時検出（ファイル監視）",
+	WatchModePollOption:    "定期スキャンのみ（ネットワークフォルダ向け）",
+	DuplicateActionLabel:   "処理済みと同じ内容のファイル",
+	DuplicateReuseOption:   "以前の結果を再利用",
+	DuplicateSkipOption:    "アーカイブへ移動",
+	DuplicateProcessOption: "再度文字起こし",
+	HallucinationLabel:     "誤認識と思われる部分（無音時の定型文・繰り返し）",
+	HallucinationDrop:      "削除",
+	HallucinationFlag:      "[?]を付けて残す",
+	HallucinationOff:       "すべて残す",
+	VocabularyLabel:        "カスタム語彙（名前・用語を1行に1つ）",
+	VocabularyModeLabel:    "語彙の渡し方",
+	VocabPromptOption:      "初期プロンプト",
+	VocabHotwordsOption:    "ホットワード（whisper-ctranslate2のみ）",
+	CleanTranscriptLabel:   "整文版も出力（フィラー除去・句読点・全角半角）",
+	SummarySourceLabel:     "要約の元",
+	SummaryCleanOption:     "整文版（出力している場合）",
+	SummaryVerbatimOption:  "Whisperの出力そのまま",
+	BrowseBtn:              "参照...",
 
 	// Additional GUI messages
 	LogPlaceholder:           "**ログをここに表示します...**",
@@ -322,6 +652,21 @@ var messagesJA = Messages{
 	DependencyError:          "音声認識エンジン（Whisper）の自動インストールに失敗しました: %v\n\n考えられる原因:\n• Python 3.12がインストールされていない\n• ネットワーク接続の問題\n• 権限不足\n\n解決方法:\n1. Python 3.12をインストール\n2. アプリケーションを再起動（自動インストールが再試行されます）\n\n問題が解決しない場合、手動でインストール:\n  pip install faster-whisper whisper-ctranslate2",
 	ConfigError:              "設定エラー",
 	ConfigLoadErrorDialog:    "設定の読み込みに失敗しました: %v\n\nデフォルト設定を使用します。",
+	JobStoreLoadError:        "ジョブ履歴の読み込みに失敗しました: %v",
+	SelectFileToCancel:       "文字起こしの中止",
+	SelectFileToDequeue:      "キューから削除",
+	NoProcessingFiles:        "処理中のファイルはありません",
+	NoQueuedFiles:            "キューは空です",
+	NotProcessing:            "%sは既に処理中ではありません",
+	NotQueued:                "%sは既にキューにありません",
+	AddRuleTitle:             "置換ルールを追加",
+	RuleSourceLabel:          "元にする行",
+	RuleFromLabel:            "置換前",
+	RuleToLabel:              "置換後",
+	RuleRegexOption:          "正規表現",
+	RuleIgnoreCaseOption:     "大文字・小文字を区別しない",
+	RuleWholeWordOption:      "単語単位（英数字の単語の一部には一致しない）",
+	RuleAdded:                "%sに追加しました。次の文字起こしから適用されます。",
 }
 
 // GetMessages returns the messages for the current UI language
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/transcript"
+	"github.com/rivo/tview"
+)
+
+// maxRuleSourceLines limits the lines offered when adding a replacement rule
+const maxRuleSourceLines = 100
+
+// RuleSourceLines returns the lines a replacement rule can be started from,
+// without duplicates: the given texts (e.g. what is being recognized right
+// now), the lines of the latest transcript in the output folder and the log
+// messages, newest first
+func RuleSourceLines(c *config.Config, current []string, logBuffer []logger.LogEntry) []string {
+	seen := make(map[string]bool)
+	var lines []string
+	add := func(line string) {
+		line = strings.TrimSpace(line)
+		if line == "" || seen[line] || len(lines) >= maxRuleSourceLines {
+			return
+		}
+		seen[line] = true
+		lines = append(lines, line)
+	}
+	for _, text := range current {
+		add(text)
+	}
+	for _, line := range latestTranscriptLines(c.OutputDir) {
+		add(line)
+	}
+	for i := len(logBuffer) - 1; i >= 0; i-- {
+		add(logBuffer[i].Message)
+	}
+	return lines
+}
+
+// latestTranscriptLines returns the lines of the most recently written txt
+// transcript below outputDir (summaries are skipped)
+func latestTranscriptLines(outputDir string) []string {
+	var latest string
+	var latestTime int64
+	filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != ".txt" || strings.HasSuffix(path, "_summary.txt") {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().UnixNano() > latestTime {
+			latest, latestTime = path, info.ModTime().UnixNano()
+		}
+		return nil
+	})
+	if latest == "" {
+		return nil
+	}
+
+	file, err := os.Open(latest)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() && len(lines) < maxRuleSourceLines {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// showAddRuleDialog lets the user pick a log or transcript line and turn the
+// misrecognized part of it into a replacement rule
+func (t *TUI) showAddRuleDialog() {
+	var current []string
+	if t.callbacks != nil && t.callbacks.Progress != nil {
+		t.mu.RLock()
+		files := append([]string(nil), t.processingFiles...)
+		t.mu.RUnlock()
+		for _, path := range files {
+			if _, lastText, ok := t.callbacks.Progress(path); ok {
+				current = append(current, lastText)
+			}
+		}
+	}
+	t.mu.RLock()
+	logs := append([]logger.LogEntry(nil), t.recentLogs...)
+	t.mu.RUnlock()
+
+	list := tview.NewList().ShowSecondaryText(false)
+	list.AddItem("（空欄から入力）", "", 0, func() { t.showRuleForm("") })
+	for _, line := range RuleSourceLines(t.config, current, logs) {
+		line := line
+		list.AddItem(tview.Escape(line), "", 0, func() { t.showRuleForm(line) })
+	}
+	list.SetBorder(true).
+		SetTitle(" 置換ルールを追加: 元にする行を選択 (Enter:選択 Esc:戻る) ").
+		SetTitleAlign(tview.AlignCenter)
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			t.app.SetRoot(t.mainFlex, true)
+			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'j', 'J':
+				return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+			case 'k', 'K':
+				return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+			case 'q', 'Q':
+				t.app.SetRoot(t.mainFlex, true)
+				return nil
+			}
+		}
+		return event
+	})
+
+	t.app.SetRoot(list, true)
+}
+
+// showRuleForm edits a new replacement rule starting from line; the user
+// cuts "from" down to the wrong part and types the correct text
+func (t *TUI) showRuleForm(line string) {
+	var rule transcript.Rule
+	form := tview.NewForm().
+		AddInputField("置換前", line, 60, nil, func(text string) { rule.From = text }).
+		AddInputField("置換後", "", 60, nil, func(text string) { rule.To = text }).
+		AddCheckbox("正規表現", false, func(checked bool) { rule.Regex = checked }).
+		AddCheckbox("大文字・小文字を区別しない", false, func(checked bool) { rule.IgnoreCase = checked }).
+		AddCheckbox("単語単位（英数字の単語の一部には一致しない）", false, func(checked bool) { rule.WholeWord = checked })
+	rule.From = line
+
+	form.AddButton("追加", func() {
+		if err := transcript.AddRule(t.config.ReplacementRulesFile, rule); err != nil {
+			t.showMessageDialog(fmt.Sprintf("[red]エラー:[white] %v", err))
+			return
+		}
+		t.showMessageDialog(fmt.Sprintf("置換ルールを追加しました: %s → %s\n（次の文字起こしから適用されます）",
+			tview.Escape(rule.From), tview.Escape(rule.To)))
+	})
+	form.AddButton("キャンセル", func() { t.app.SetRoot(t.mainFlex, true) })
+	form.SetCancelFunc(func() { t.app.SetRoot(t.mainFlex, true) })
+
+	form.SetBorder(true).
+		SetTitle(" 置換ルールを追加（" + filepath.Base(t.config.ReplacementRulesFile) + "） ").
+		SetTitleAlign(tview.AlignCenter)
+
+	t.app.SetRoot(form, true)
+}
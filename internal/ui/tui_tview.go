@@ -2,13 +2,16 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
 	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
 	"github.com/infoHiroki/KoeMoji-Go/internal/recorder"
 	"github.com/rivo/tview"
@@ -59,6 +62,140 @@ func volumeIndexToMicFloat(idx int) float64 {
 	return 1.6 // Default
 }
 
+// watchModeDisplay returns the short label shown in the processing settings list
+func watchModeDisplay(mode string) string {
+	if mode == config.WatchModePoll {
+		return "定期スキャンのみ"
+	}
+	return "即時検出"
+}
+
+// duplicateActions lists the duplicate_action values with their labels for the processing settings
+var duplicateActions = []struct {
+	value string
+	label string
+}{
+	{config.DuplicateActionReuse, "結果を再利用"},
+	{config.DuplicateActionSkip, "アーカイブへ移動"},
+	{config.DuplicateActionProcess, "再度文字起こし"},
+}
+
+// duplicateActionDisplay returns the short label shown in the processing settings list
+func duplicateActionDisplay(action string) string {
+	for _, a := range duplicateActions {
+		if a.value == action {
+			return a.label
+		}
+	}
+	return action
+}
+
+// hallucinationFilters lists the hallucination_filter values with their labels for the processing settings
+var hallucinationFilters = []struct {
+	value string
+	label string
+}{
+	{config.HallucinationFilterDrop, "削除"},
+	{config.HallucinationFilterFlag, "[?]を付けて残す"},
+	{config.HallucinationFilterOff, "オフ"},
+}
+
+// hallucinationFilterDisplay returns the short label shown in the processing settings list
+func hallucinationFilterDisplay(mode string) string {
+	for _, f := range hallucinationFilters {
+		if f.value == mode {
+			return f.label
+		}
+	}
+	return mode
+}
+
+// vocabularyModes lists the vocabulary_mode values with their labels for the processing settings
+var vocabularyModes = []struct {
+	value string
+	label string
+}{
+	{config.VocabularyModePrompt, "初期プロンプト"},
+	{config.VocabularyModeHotwords, "ホットワード"},
+}
+
+// vocabularyModeDisplay returns the short label shown in the processing settings list
+func vocabularyModeDisplay(mode string) string {
+	for _, m := range vocabularyModes {
+		if m.value == mode {
+			return m.label
+		}
+	}
+	return mode
+}
+
+// summarySources lists the summary_source values with their labels for the processing settings
+var summarySources = []struct {
+	value string
+	label string
+}{
+	{config.SummarySourceClean, "整文版"},
+	{config.SummarySourceVerbatim, "そのまま"},
+}
+
+// summarySourceDisplay returns the short label shown in the processing settings list
+func summarySourceDisplay(source string) string {
+	for _, s := range summarySources {
+		if s.value == source {
+			return s.label
+		}
+	}
+	return source
+}
+
+// vocabularyDisplay returns the custom vocabulary shown in the processing settings list
+func vocabularyDisplay(c *config.Config) string {
+	if len(c.Vocabulary) == 0 {
+		return "なし"
+	}
+	return fmt.Sprintf("%d語: %s", len(c.Vocabulary), c.VocabularyDisplay())
+}
+
+// enabledDisplay returns 有効/無効 for on/off settings
+func enabledDisplay(enabled bool) string {
+	if enabled {
+		return "有効"
+	}
+	return "無効"
+}
+
+// backendDisplayName returns the transcription backend shown in the basic settings list
+func backendDisplayName(backend string) string {
+	if backend == "" {
+		return config.BackendWhisperCTranslate2
+	}
+	return backend
+}
+
+// scheduleDisplay returns the processing schedule shown in the processing settings list
+func scheduleDisplay(schedule []string) string {
+	if len(schedule) == 0 {
+		return "常時"
+	}
+	return strings.Join(schedule, "; ")
+}
+
+// chunkMinutesDisplay returns the label for chunk_minutes
+func chunkMinutesDisplay(minutes int) string {
+	if minutes <= 0 {
+		return "オフ"
+	}
+	return fmt.Sprintf("%d分ごと", minutes)
+}
+
+// lowConfidenceDisplay returns the label for low_confidence_threshold
+func lowConfidenceDisplay(threshold float64) string {
+	if threshold <= 0 {
+		return "オフ"
+	}
+	return fmt.Sprintf("確率%s未満", strconv.FormatFloat(threshold, 'f', -1, 64))
+}
+
 // TUICallbacks contains callback functions for TUI actions (Phase 11)
 type TUICallbacks struct {
 	OnRecordingToggle func() error        // 録音開始/停止
@@ -66,6 +203,15 @@ type TUICallbacks struct {
 	OnOpenLogFile     func() error        // ログファイルを開く
 	OnOpenDirectory   func(dir string) error // フォルダを開く
 	OnRefreshFileList func() error        // ファイルリスト更新
+	OnCancelProcessing func(path string) error // 処理中のファイルを中止
+	OnRemoveFromQueue  func(path string) error // キューからファイルを削除
+	ProfileOf          func(path string) string // ファイルに適用中のプロファイル名
+	OnProcessNow       func() error             // 処理時間帯を無視して今すぐ処理
+	NextWindow         func() (time.Time, bool) // 処理時間帯外のとき次の時間帯の開始時刻
+	QueueETA           func() jobs.ETA          // 処理中・キュー全体の残り時間の見積もり
+
+	// 文字起こしの進捗率（不明なら負の値）と直近の認識テキスト
+	Progress func(path string) (percent float64, lastText string, ok bool)
 }
 
 // TUI represents a rich terminal UI (LazyGit/k9s style)
@@ -91,15 +237,18 @@ type TUI struct {
 	archivePage   *tview.List // Phase 9: Archive folder file display
 
 	// Status tracking (Phase 7)
-	startTime      time.Time
-	inputCount     int
-	outputCount    int
-	archiveCount   int
-	isProcessing   bool
-	processingFile string
-	isRecording    bool
-	recordingStart time.Time
-	mu             sync.RWMutex
+	startTime       time.Time
+	inputCount      int
+	outputCount     int
+	archiveCount    int
+	isProcessing    bool
+	processingFiles []string
+	queuedFiles     []string // Files waiting for a free worker
+	waitingFiles    []string // Files still being written, not queued yet
+	isRecording     bool
+	recordingStart  time.Time
+	recentLogs      []logger.LogEntry // Shown on the dashboard, offered when adding a replacement rule
+	mu              sync.RWMutex
 }
 
 // NewTUI creates a new rich TUI (Phase 11: with callbacks)
@@ -182,7 +331,7 @@ func NewTUI(cfg *config.Config, callbacks *TUICallbacks) *TUI {
 	// Create help bar (bottom, 1 line)
 	helpBar := tview.NewTextView().
 		SetDynamicColors(true).
-		SetText("[yellow]↑↓/j/k[white]:移動 [yellow]Enter[white]:選択 [yellow]q[white]:終了 [yellow]?[white]:ヘルプ")
+		SetText("[yellow]↑↓/j/k[white]:移動 [yellow]Enter[white]:選択 [yellow]x[white]:処理中止 [yellow]d[white]:キュー削除 [yellow]p[white]:今すぐ処理 [yellow]g[white]:置換ルール [yellow]q[white]:終了 [yellow]?[white]:ヘルプ")
 	helpBar.SetBorder(false)
 
 	// Create left-right split layout
@@ -250,6 +399,24 @@ func NewTUI(cfg *config.Config, callbacks *TUICallbacks) *TUI {
 				// ?: Show help dialog
 				showRichHelpDialog(app, mainFlex)
 				return nil
+			case 'x', 'X':
+				// x: Cancel a running transcription
+				tui.showCancelProcessingDialog()
+				return nil
+			case 'd', 'D':
+				// d: Remove a file from the queue
+				tui.showRemoveFromQueueDialog()
+				return nil
+			case 'p', 'P':
+				// p: Process the queue now, ignoring the processing schedule
+				if tui.callbacks != nil && tui.callbacks.OnProcessNow != nil {
+					tui.callbacks.OnProcessNow()
+				}
+				return nil
+			case 'g', 'G':
+				// g: Add a replacement rule from a log or transcript line
+				tui.showAddRuleDialog()
+				return nil
 			}
 		}
 		// Return event for default behavior (arrow keys, Enter, etc.)
@@ -322,6 +489,10 @@ func showRichHelpDialog(app *tview.Application, mainFlex *tview.Flex) {
   ↓ / j     : 下に移動
   Enter     : 選択 / フォルダを開く
   r         : ファイルリスト再読み込み
+  x         : 処理中のファイルを中止
+  d         : キューからファイルを削除
+  p         : 処理時間帯外でもキューを今すぐ処理
+  g         : ログ・文字起こしの行から置換ルールを追加
   q         : 終了
   ?         : このヘルプを表示
 
@@ -377,14 +548,27 @@ func (t *TUI) updateStatusBar() {
 		statusText = fmt.Sprintf("録音中 (%s)", formatDuration(elapsed))
 	} else if t.isProcessing {
 		statusIcon = "[yellow]●[white]"
-		if t.processingFile != "" {
-			statusText = fmt.Sprintf("処理中: %s", t.processingFile)
+		if len(t.processingFiles) > 0 {
+			statusText = fmt.Sprintf("処理中(%d): %s", len(t.processingFiles), JoinFileNamesWithProfiles(t.processingFiles, t.profileOf()))
 		} else {
 			statusText = "処理中"
 		}
 	}
 
 	line1 := fmt.Sprintf("%s %s | Phase 7", statusIcon, statusText)
+	if progressText := t.progressText(); progressText != "" {
+		line1 += " | [green]" + progressText + "[white]"
+	}
+	if t.isRecording && t.isProcessing && t.config.PauseWhileRecording {
+		// 録音中は文字起こしを一時停止している (pause_while_recording)
+		line1 += fmt.Sprintf(" | [yellow]文字起こし一時停止中(%d)[white]", len(t.processingFiles))
+	}
+	if len(t.queuedFiles) > 0 {
+		line1 += fmt.Sprintf(" | キュー(%d)", len(t.queuedFiles))
+	}
+	if len(t.waitingFiles) > 0 {
+		line1 += fmt.Sprintf(" | [gray]書込待ち(%d): %s[white]", len(t.waitingFiles), JoinFileNames(t.waitingFiles))
+	}
 
 	// Line 2: File counts
 	line2 := fmt.Sprintf("[blue]入力:[white]%d → [green]出力:[white]%d → [gray]保存:[white]%d",
@@ -393,6 +577,16 @@ func (t *TUI) updateStatusBar() {
 	// Line 3: Timing info
 	uptime := time.Since(t.startTime)
 	line3 := fmt.Sprintf("[yellow]起動時間:[white] %s", formatDuration(uptime))
+	if t.callbacks != nil && t.callbacks.NextWindow != nil {
+		if next, ok := t.callbacks.NextWindow(); ok {
+			line3 += fmt.Sprintf(" | [yellow]次の処理時間帯:[white] %s ([yellow]p[white]:今すぐ処理)", FormatNextWindow(next))
+		}
+	}
+	if t.callbacks != nil && t.callbacks.QueueETA != nil {
+		if etaText := FormatETA(t.callbacks.QueueETA(), GetMessages(t.config)); etaText != "" {
+			line3 += " | [yellow]" + etaText + "[white]"
+		}
+	}
 
 	// Update status bar
 	t.statusBar.SetText(fmt.Sprintf("%s\n%s\n%s", line1, line2, line3))
@@ -400,13 +594,15 @@ func (t *TUI) updateStatusBar() {
 
 // UpdateStatus updates status information from main goroutine (Phase 7)
 func (t *TUI) UpdateStatus(inputCount, outputCount, archiveCount int,
-	processingFile string, isProcessing bool, isRecording bool, recordingStart time.Time) {
+	processingFiles, queuedFiles, waitingFiles []string, isProcessing bool, isRecording bool, recordingStart time.Time) {
 
 	t.mu.Lock()
 	t.inputCount = inputCount
 	t.outputCount = outputCount
 	t.archiveCount = archiveCount
-	t.processingFile = processingFile
+	t.processingFiles = processingFiles
+	t.queuedFiles = queuedFiles
+	t.waitingFiles = waitingFiles
 	t.isProcessing = isProcessing
 	t.isRecording = isRecording
 	t.recordingStart = recordingStart
@@ -418,6 +614,126 @@ func (t *TUI) UpdateStatus(inputCount, outputCount, archiveCount int,
 	})
 }
 
+// progressText returns the progress of the running files, e.g. "memo.wav 42%".
+// Caller must hold t.mu.
+func (t *TUI) progressText() string {
+	if t.callbacks == nil || t.callbacks.Progress == nil {
+		return ""
+	}
+	var parts []string
+	for _, path := range t.processingFiles {
+		if percent, _, ok := t.callbacks.Progress(path); ok && percent >= 0 {
+			parts = append(parts, fmt.Sprintf("%s %.0f%%", filepath.Base(path), percent))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// lastHeardText returns the latest recognized text of each running file for
+// the dashboard preview (Phase 12)
+func (t *TUI) lastHeardText() string {
+	if t.callbacks == nil || t.callbacks.Progress == nil {
+		return ""
+	}
+	t.mu.RLock()
+	files := append([]string(nil), t.processingFiles...)
+	t.mu.RUnlock()
+
+	var text string
+	for _, path := range files {
+		if _, lastText, ok := t.callbacks.Progress(path); ok && lastText != "" {
+			text += fmt.Sprintf("[green]♪ %s:[white] %s\n", filepath.Base(path), tview.Escape(lastText))
+		}
+	}
+	return text
+}
+
+// profileOf returns the profile lookup callback, or nil when none is set
+func (t *TUI) profileOf() func(path string) string {
+	if t.callbacks == nil {
+		return nil
+	}
+	return t.callbacks.ProfileOf
+}
+
+// showCancelProcessingDialog lets the user pick a running transcription to cancel
+func (t *TUI) showCancelProcessingDialog() {
+	t.mu.RLock()
+	files := append([]string(nil), t.processingFiles...)
+	t.mu.RUnlock()
+
+	if t.callbacks == nil || t.callbacks.OnCancelProcessing == nil {
+		return
+	}
+	t.showFileActionDialog(" 処理中止 ", "処理中のファイルはありません", files, t.callbacks.OnCancelProcessing)
+}
+
+// showRemoveFromQueueDialog lets the user pick a queued file to remove
+func (t *TUI) showRemoveFromQueueDialog() {
+	t.mu.RLock()
+	files := append([]string(nil), t.queuedFiles...)
+	t.mu.RUnlock()
+
+	if t.callbacks == nil || t.callbacks.OnRemoveFromQueue == nil {
+		return
+	}
+	t.showFileActionDialog(" キュー削除 ", "キューは空です", files, t.callbacks.OnRemoveFromQueue)
+}
+
+// showFileActionDialog shows files in a list and runs action on the one chosen with Enter
+func (t *TUI) showFileActionDialog(title, emptyText string, files []string, action func(path string) error) {
+	if len(files) == 0 {
+		t.showMessageDialog(emptyText)
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(false)
+	for _, file := range files {
+		path := file
+		list.AddItem(filepath.Base(path), "", 0, func() {
+			t.app.SetRoot(t.mainFlex, true)
+			if err := action(path); err != nil {
+				t.showMessageDialog(fmt.Sprintf("[red]エラー:[white] %v", err))
+			}
+		})
+	}
+	list.SetBorder(true).
+		SetTitle(title + "(Enter:実行 Esc:戻る) ").
+		SetTitleAlign(tview.AlignCenter)
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			t.app.SetRoot(t.mainFlex, true)
+			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'j', 'J':
+				return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+			case 'k', 'K':
+				return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+			case 'q', 'Q':
+				t.app.SetRoot(t.mainFlex, true)
+				return nil
+			}
+		}
+		return event
+	})
+
+	t.app.SetRoot(list, true)
+}
+
+// showMessageDialog shows a short message with a close button
+func (t *TUI) showMessageDialog(text string) {
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"閉じる"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			t.app.SetRoot(t.mainFlex, true)
+		})
+	t.app.SetRoot(modal, true)
+}
+
 // UpdateFileLists updates the file lists for input/output/archive directories (Phase 11)
 func (t *TUI) UpdateFileLists() {
 	t.app.QueueUpdateDraw(func() {
@@ -455,9 +771,18 @@ func (t *TUI) UpdateFileLists() {
 
 // UpdateDashboard updates the dashboard page with real-time logs (Phase 12)
 func (t *TUI) UpdateDashboard(logBuffer []logger.LogEntry) {
+	t.mu.Lock()
+	t.recentLogs = append(t.recentLogs[:0], logBuffer...)
+	t.mu.Unlock()
+
 	t.app.QueueUpdateDraw(func() {
 		// Build log text with colors
-		logText := "[yellow]リアルタイムログ（最新12件）[white]\n\n"
+		logText := ""
+		if lastHeard := t.lastHeardText(); lastHeard != "" {
+			// 認識中のテキストのプレビュー
+			logText += "[yellow]認識中[white]\n" + lastHeard + "\n"
+		}
+		logText += "[yellow]リアルタイムログ（最新12件）[white]\n\n"
 
 		if len(logBuffer) == 0 {
 			logText += "[gray]ログがありません[white]"
@@ -576,13 +901,14 @@ func (t *TUI) showConfigDialog() {
 		}
 	}
 
-	// Create category menu (left side) - Phase 14: 4 categories
+	// Create category menu (left side) - Phase 14: 4 categories + processing
 	categoryList := tview.NewList().ShowSecondaryText(false)
 	categoryList.AddItem("1. 基本設定", "", 0, nil)
 	categoryList.AddItem("2. ディレクトリ", "", 0, nil)
 	categoryList.AddItem("3. LLM設定", "", 0, nil)
 	categoryList.AddItem("4. 録音設定", "", 0, nil) // Phase 14: NEW
-	categoryList.AddItem("", "", 0, nil)            // Separator
+	categoryList.AddItem("5. 処理設定", "", 0, nil)
+	categoryList.AddItem("", "", 0, nil) // Separator
 	categoryList.AddItem("保存", "", 's', nil)
 	categoryList.AddItem("キャンセル", "", 'q', nil)
 	categoryList.SetBorder(true).
@@ -623,6 +949,8 @@ func (t *TUI) showConfigDialog() {
 	basicList.AddItem("Whisperモデル", t.config.WhisperModel, 0, nil)
 	basicList.AddItem("認識言語", langDisplay, 0, nil)
 	basicList.AddItem("スキャン間隔", fmt.Sprintf("%d分", t.config.ScanIntervalMinutes), 0, nil)
+	basicList.AddItem("文字起こしエンジン", backendDisplayName(t.config.TranscriptionBackend), 0, nil)
+	basicList.AddItem("出力形式", t.config.FormatsDisplay(), 0, nil)
 	basicList.SetBorder(true).
 		SetTitle(" 基本設定 (Enterで編集) ").
 		SetTitleAlign(tview.AlignCenter)
@@ -632,6 +960,7 @@ func (t *TUI) showConfigDialog() {
 	dirList.AddItem("入力フォルダ", t.config.InputDir, 0, nil)
 	dirList.AddItem("出力フォルダ", t.config.OutputDir, 0, nil)
 	dirList.AddItem("保存フォルダ", t.config.ArchiveDir, 0, nil)
+	dirList.AddItem("失敗フォルダ", t.config.FailedDir, 0, nil)
 	dirList.SetBorder(true).
 		SetTitle(" ディレクトリ設定 (Enterで編集) ").
 		SetTitleAlign(tview.AlignCenter)
@@ -699,15 +1028,39 @@ func (t *TUI) showConfigDialog() {
 		SetTitle(" 録音設定 (Enterで編集) ").
 		SetTitleAlign(tview.AlignCenter)
 
+	// === Page 5: Processing Settings List ===
+	processingList := tview.NewList().ShowSecondaryText(true)
+	processingList.AddItem("同時処理数", fmt.Sprintf("%d", t.config.MaxConcurrentJobs), 0, nil)
+	processingList.AddItem("監視モード", watchModeDisplay(t.config.WatchMode), 0, nil)
+	processingList.AddItem("安定待ち時間", fmt.Sprintf("%d秒", t.config.FileSettleSeconds), 0, nil)
+	processingList.AddItem("リトライ回数", fmt.Sprintf("%d回", t.config.MaxRetries), 0, nil)
+	processingList.AddItem("サブフォルダ", enabledDisplay(t.config.RecursiveScan), 0, nil)
+	processingList.AddItem("重複ファイル", duplicateActionDisplay(t.config.DuplicateAction), 0, nil)
+	processingList.AddItem("CPU使用率上限", fmt.Sprintf("%d%%", t.config.MaxCpuPercent), 0, nil)
+	processingList.AddItem("録音中の一時停止", enabledDisplay(t.config.PauseWhileRecording), 0, nil)
+	processingList.AddItem("処理時間帯", scheduleDisplay(t.config.ProcessingSchedule), 0, nil)
+	processingList.AddItem("長時間音声の分割", chunkMinutesDisplay(t.config.ChunkMinutes), 0, nil)
+	processingList.AddItem("単語ごとのタイムスタンプ", enabledDisplay(t.config.WordTimestamps), 0, nil)
+	processingList.AddItem("信頼度の低い単語をマーク", lowConfidenceDisplay(t.config.LowConfidenceThreshold), 0, nil)
+	processingList.AddItem("幻覚フィルタ", hallucinationFilterDisplay(t.config.HallucinationFilter), 0, nil)
+	processingList.AddItem("カスタム語彙", vocabularyDisplay(t.config), 0, nil)
+	processingList.AddItem("語彙の渡し方", vocabularyModeDisplay(t.config.VocabularyMode), 0, nil)
+	processingList.AddItem("整文版の出力", enabledDisplay(t.config.CleanTranscript), 0, nil)
+	processingList.AddItem("要約の元", summarySourceDisplay(t.config.SummarySource), 0, nil)
+	processingList.SetBorder(true).
+		SetTitle(" 処理設定 (Enterで編集) ").
+		SetTitleAlign(tview.AlignCenter)
+
 	// Add pages
 	contentArea.AddPage("basic", basicList, true, true)
 	contentArea.AddPage("directories", dirList, true, false)
 	contentArea.AddPage("llm", llmList, true, false)
 	contentArea.AddPage("recording", recordingList, true, false)
+	contentArea.AddPage("processing", processingList, true, false)
 
 	// Handle category selection (cursor movement)
 	categoryList.SetChangedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
-		pageNames := []string{"basic", "directories", "llm", "recording"}
+		pageNames := []string{"basic", "directories", "llm", "recording", "processing"}
 		if index >= 0 && index < len(pageNames) {
 			contentArea.SwitchToPage(pageNames[index])
 		}
@@ -861,6 +1214,75 @@ func (t *TUI) showConfigDialog() {
 			})
 
 			showEditDialog("スキャン間隔", field)
+
+		case 4: // Transcription Backend
+			currentBackendIndex := 0
+			for i, b := range config.TranscriptionBackends {
+				if b == backendDisplayName(t.config.TranscriptionBackend) {
+					currentBackendIndex = i
+				}
+			}
+			dropdown := tview.NewDropDown().
+				SetLabel("文字起こしエンジン: ").
+				SetOptions(config.TranscriptionBackends, nil).
+				SetCurrentOption(currentBackendIndex)
+
+			dropdown.SetBorder(true).
+				SetTitle(" 文字起こしエンジンを選択 ").
+				SetTitleAlign(tview.AlignCenter)
+
+			// SetSelectedFunc for when selection is confirmed
+			dropdown.SetSelectedFunc(func(backend string, _ int) {
+				t.config.TranscriptionBackend = backend
+				basicList.SetItemText(4, "文字起こしエンジン", backend)
+				closeEditDialog()
+			})
+
+			// SetInputCapture only for Escape
+			dropdown.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEscape {
+					closeEditDialog()
+					return nil
+				}
+				return event
+			})
+
+			showEditDialog("文字起こしエンジン", dropdown)
+
+		case 5: // Output formats (multi-select)
+			selected := make(map[string]bool)
+			for _, format := range t.config.Formats() {
+				selected[format] = true
+			}
+			form := tview.NewForm()
+			for _, format := range config.OutputFormatChoices {
+				format := format
+				form.AddCheckbox(format, selected[format], func(checked bool) {
+					selected[format] = checked
+				})
+			}
+			form.AddButton("OK", func() {
+				var formats []string
+				for _, format := range config.OutputFormatChoices {
+					if selected[format] {
+						formats = append(formats, format)
+					}
+				}
+				// 何も選ばれていない場合は変更しない
+				if len(formats) > 0 {
+					t.config.SetFormats(formats)
+					basicList.SetItemText(5, "出力形式", t.config.FormatsDisplay())
+				}
+				closeEditDialog()
+			})
+			form.AddButton("キャンセル", closeEditDialog)
+			form.SetCancelFunc(closeEditDialog)
+
+			form.SetBorder(true).
+				SetTitle(" 出力形式を選択（複数可、Spaceで切替） ").
+				SetTitleAlign(tview.AlignCenter)
+
+			showEditDialog("出力形式", form)
 		}
 	})
 
@@ -889,6 +1311,12 @@ func (t *TUI) showConfigDialog() {
 				SetText(t.config.ArchiveDir).
 				SetFieldWidth(70)
 			targetConfig = &t.config.ArchiveDir
+		case 3: // Failed Dir
+			field = tview.NewInputField().
+				SetLabel("失敗フォルダ: ").
+				SetText(t.config.FailedDir).
+				SetFieldWidth(70)
+			targetConfig = &t.config.FailedDir
 		}
 
 		field.SetBorder(true).
@@ -1233,6 +1661,504 @@ func (t *TUI) showConfigDialog() {
 		}
 	})
 
+	// Edit handlers for Processing Settings
+	processingList.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		switch index {
+		case 0: // Max concurrent jobs
+			field := tview.NewInputField().
+				SetLabel(fmt.Sprintf("同時処理数 (1-%d): ", runtime.NumCPU())).
+				SetText(fmt.Sprintf("%d", t.config.MaxConcurrentJobs)).
+				SetFieldWidth(10)
+
+			field.SetBorder(true).
+				SetTitle(" 同時処理数を編集 ").
+				SetTitleAlign(tview.AlignCenter)
+
+			field.SetDoneFunc(func(key tcell.Key) {
+				if key == tcell.KeyEscape {
+					closeEditDialog()
+				} else if key == tcell.KeyEnter {
+					text := field.GetText()
+					if jobs, err := strconv.Atoi(text); err == nil && jobs >= 1 && jobs <= runtime.NumCPU() {
+						t.config.MaxConcurrentJobs = jobs
+						processingList.SetItemText(0, "同時処理数", fmt.Sprintf("%d", jobs))
+					}
+					closeEditDialog()
+				}
+			})
+
+			showEditDialog("同時処理数", field)
+
+		case 1: // Watch mode
+			modes := []string{config.WatchModeWatch, config.WatchModePoll}
+			modeOptions := []string{"即時検出（ファイル監視）", "定期スキャンのみ（ネットワークフォルダ向け）"}
+
+			dropdown := tview.NewDropDown().
+				SetLabel("監視モード: ").
+				SetOptions(modeOptions, nil)
+
+			if t.config.WatchMode == config.WatchModePoll {
+				dropdown.SetCurrentOption(1)
+			} else {
+				dropdown.SetCurrentOption(0)
+			}
+
+			dropdown.SetBorder(true).
+				SetTitle(" 監視モードを選択 ").
+				SetTitleAlign(tview.AlignCenter)
+
+			dropdown.SetSelectedFunc(func(text string, index int) {
+				t.config.WatchMode = modes[index]
+				processingList.SetItemText(1, "監視モード", watchModeDisplay(t.config.WatchMode))
+				closeEditDialog()
+			})
+
+			dropdown.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEscape {
+					closeEditDialog()
+					return nil
+				}
+				return event
+			})
+
+			showEditDialog("監視モード", dropdown)
+
+		case 2: // File settle time
+			field := tview.NewInputField().
+				SetLabel("安定待ち時間 (0-600秒): ").
+				SetText(fmt.Sprintf("%d", t.config.FileSettleSeconds)).
+				SetFieldWidth(10)
+
+			field.SetBorder(true).
+				SetTitle(" 安定待ち時間を編集 ").
+				SetTitleAlign(tview.AlignCenter)
+
+			field.SetDoneFunc(func(key tcell.Key) {
+				if key == tcell.KeyEscape {
+					closeEditDialog()
+				} else if key == tcell.KeyEnter {
+					text := field.GetText()
+					if seconds, err := strconv.Atoi(text); err == nil && seconds >= 0 && seconds <= 600 {
+						t.config.FileSettleSeconds = seconds
+						processingList.SetItemText(2, "安定待ち時間", fmt.Sprintf("%d秒", seconds))
+					}
+					closeEditDialog()
+				}
+			})
+
+			showEditDialog("安定待ち時間", field)
+
+		case 3: // Max retries
+			field := tview.NewInputField().
+				SetLabel("失敗時のリトライ回数 (0-10): ").
+				SetText(fmt.Sprintf("%d", t.config.MaxRetries)).
+				SetFieldWidth(10)
+
+			field.SetBorder(true).
+				SetTitle(" リトライ回数を編集 ").
+				SetTitleAlign(tview.AlignCenter)
+
+			field.SetDoneFunc(func(key tcell.Key) {
+				if key == tcell.KeyEscape {
+					closeEditDialog()
+				} else if key == tcell.KeyEnter {
+					text := field.GetText()
+					if retries, err := strconv.Atoi(text); err == nil && retries >= 0 && retries <= 10 {
+						t.config.MaxRetries = retries
+						processingList.SetItemText(3, "リトライ回数", fmt.Sprintf("%d回", retries))
+					}
+					closeEditDialog()
+				}
+			})
+
+			showEditDialog("リトライ回数", field)
+
+		case 4: // Recursive scan toggle
+			list := tview.NewList().ShowSecondaryText(false)
+			list.AddItem("有効（サブフォルダも処理、出力は同じフォルダ構成）", "", '1', nil)
+			list.AddItem("無効（入力フォルダ直下のみ）", "", '2', nil)
+
+			if t.config.RecursiveScan {
+				list.SetCurrentItem(0)
+			} else {
+				list.SetCurrentItem(1)
+			}
+
+			list.SetBorder(true).
+				SetTitle(" サブフォルダのスキャン ").
+				SetTitleAlign(tview.AlignCenter)
+
+			list.SetSelectedFunc(func(idx int, text, secondary string, r rune) {
+				t.config.RecursiveScan = (idx == 0)
+				processingList.SetItemText(4, "サブフォルダ", enabledDisplay(t.config.RecursiveScan))
+				closeEditDialog()
+			})
+
+			list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEscape {
+					closeEditDialog()
+					return nil
+				}
+				return event
+			})
+
+			showEditDialog("サブフォルダ", list)
+
+		case 5: // Duplicate files
+			options := make([]string, len(duplicateActions))
+			current := 0
+			for i, a := range duplicateActions {
+				options[i] = a.label
+				if a.value == t.config.DuplicateAction {
+					current = i
+				}
+			}
+
+			dropdown := tview.NewDropDown().
+				SetLabel("同じ内容のファイル: ").
+				SetOptions(options, nil).
+				SetCurrentOption(current)
+
+			dropdown.SetBorder(true).
+				SetTitle(" 重複ファイルの扱いを選択 ").
+				SetTitleAlign(tview.AlignCenter)
+
+			dropdown.SetSelectedFunc(func(text string, index int) {
+				t.config.DuplicateAction = duplicateActions[index].value
+				processingList.SetItemText(5, "重複ファイル", duplicateActionDisplay(t.config.DuplicateAction))
+				closeEditDialog()
+			})
+
+			dropdown.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEscape {
+					closeEditDialog()
+					return nil
+				}
+				return event
+			})
+
+			showEditDialog("重複ファイル", dropdown)
+
+		case 6: // Max CPU percent
+			field := tview.NewInputField().
+				SetLabel("CPU使用率上限 (1-100%): ").
+				SetText(fmt.Sprintf("%d", t.config.MaxCpuPercent)).
+				SetFieldWidth(10)
+
+			field.SetBorder(true).
+				SetTitle(" CPU使用率上限を編集 ").
+				SetTitleAlign(tview.AlignCenter)
+
+			field.SetDoneFunc(func(key tcell.Key) {
+				if key == tcell.KeyEscape {
+					closeEditDialog()
+				} else if key == tcell.KeyEnter {
+					text := field.GetText()
+					if percent, err := strconv.Atoi(text); err == nil && percent >= 1 && percent <= 100 {
+						t.config.MaxCpuPercent = percent
+						processingList.SetItemText(6, "CPU使用率上限", fmt.Sprintf("%d%%", percent))
+					}
+					closeEditDialog()
+				}
+			})
+
+			showEditDialog("CPU使用率上限", field)
+
+		case 7: // Pause while recording toggle
+			list := tview.NewList().ShowSecondaryText(false)
+			list.AddItem("有効（録音中は文字起こしを一時停止）", "", '1', nil)
+			list.AddItem("無効（録音中も処理を続ける）", "", '2', nil)
+
+			if t.config.PauseWhileRecording {
+				list.SetCurrentItem(0)
+			} else {
+				list.SetCurrentItem(1)
+			}
+
+			list.SetBorder(true).
+				SetTitle(" 録音中の一時停止 ").
+				SetTitleAlign(tview.AlignCenter)
+
+			list.SetSelectedFunc(func(idx int, text, secondary string, r rune) {
+				t.config.PauseWhileRecording = (idx == 0)
+				processingList.SetItemText(7, "録音中の一時停止", enabledDisplay(t.config.PauseWhileRecording))
+				closeEditDialog()
+			})
+
+			list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEscape {
+					closeEditDialog()
+					return nil
+				}
+				return event
+			})
+
+			showEditDialog("録音中の一時停止", list)
+
+		case 8: // Processing schedule
+			field := tview.NewInputField().
+				SetLabel("処理時間帯 (;区切り、空欄で常時): ").
+				SetText(strings.Join(t.config.ProcessingSchedule, "; ")).
+				SetFieldWidth(40)
+
+			field.SetBorder(true).
+				SetTitle(" 処理時間帯を編集 (例: mon-fri 18:00-08:00; weekends) ").
+				SetTitleAlign(tview.AlignCenter)
+
+			field.SetDoneFunc(func(key tcell.Key) {
+				if key == tcell.KeyEscape {
+					closeEditDialog()
+				} else if key == tcell.KeyEnter {
+					if schedule, err := config.ParseScheduleList(field.GetText()); err == nil {
+						t.config.ProcessingSchedule = schedule
+						processingList.SetItemText(8, "処理時間帯", scheduleDisplay(schedule))
+					}
+					closeEditDialog()
+				}
+			})
+
+			showEditDialog("処理時間帯", field)
+
+		case 9: // Chunk minutes
+			field := tview.NewInputField().
+				SetLabel("分割の長さ (0-240分、0でオフ): ").
+				SetText(fmt.Sprintf("%d", t.config.ChunkMinutes)).
+				SetFieldWidth(10)
+
+			field.SetBorder(true).
+				SetTitle(" 長時間音声の分割を編集 ").
+				SetTitleAlign(tview.AlignCenter)
+
+			field.SetDoneFunc(func(key tcell.Key) {
+				if key == tcell.KeyEscape {
+					closeEditDialog()
+				} else if key == tcell.KeyEnter {
+					text := field.GetText()
+					if minutes, err := strconv.Atoi(text); err == nil && minutes >= 0 && minutes <= 240 {
+						t.config.ChunkMinutes = minutes
+						processingList.SetItemText(9, "長時間音声の分割", chunkMinutesDisplay(minutes))
+					}
+					closeEditDialog()
+				}
+			})
+
+			showEditDialog("長時間音声の分割", field)
+
+		case 10: // Word timestamps toggle
+			list := tview.NewList().ShowSecondaryText(false)
+			list.AddItem("有効（単語ごとの時刻と確率、VTTで単語をハイライト）", "", '1', nil)
+			list.AddItem("無効", "", '2', nil)
+
+			if t.config.WordTimestamps {
+				list.SetCurrentItem(0)
+			} else {
+				list.SetCurrentItem(1)
+			}
+
+			list.SetBorder(true).
+				SetTitle(" 単語ごとのタイムスタンプ ").
+				SetTitleAlign(tview.AlignCenter)
+
+			list.SetSelectedFunc(func(idx int, text, secondary string, r rune) {
+				t.config.WordTimestamps = (idx == 0)
+				processingList.SetItemText(10, "単語ごとのタイムスタンプ", enabledDisplay(t.config.WordTimestamps))
+				closeEditDialog()
+			})
+
+			list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEscape {
+					closeEditDialog()
+					return nil
+				}
+				return event
+			})
+
+			showEditDialog("単語ごとのタイムスタンプ", list)
+
+		case 11: // Low confidence threshold
+			field := tview.NewInputField().
+				SetLabel("しきい値 (0-1、0でオフ): ").
+				SetText(strconv.FormatFloat(t.config.LowConfidenceThreshold, 'f', -1, 64)).
+				SetFieldWidth(10)
+
+			field.SetBorder(true).
+				SetTitle(" 信頼度の低い単語をマーク (例: 0.5) ").
+				SetTitleAlign(tview.AlignCenter)
+
+			field.SetDoneFunc(func(key tcell.Key) {
+				if key == tcell.KeyEscape {
+					closeEditDialog()
+				} else if key == tcell.KeyEnter {
+					if threshold, err := strconv.ParseFloat(field.GetText(), 64); err == nil && threshold >= 0 && threshold < 1 {
+						t.config.LowConfidenceThreshold = threshold
+						processingList.SetItemText(11, "信頼度の低い単語をマーク", lowConfidenceDisplay(threshold))
+					}
+					closeEditDialog()
+				}
+			})
+
+			showEditDialog("信頼度の低い単語をマーク", field)
+
+		case 12: // Hallucination filter
+			options := make([]string, len(hallucinationFilters))
+			current := 0
+			for i, f := range hallucinationFilters {
+				options[i] = f.label
+				if f.value == t.config.HallucinationFilter {
+					current = i
+				}
+			}
+
+			dropdown := tview.NewDropDown().
+				SetLabel("誤認識と思われる部分: ").
+				SetOptions(options, nil).
+				SetCurrentOption(current)
+
+			dropdown.SetBorder(true).
+				SetTitle(" 幻覚フィルタ（無音部分の定型文・繰り返し） ").
+				SetTitleAlign(tview.AlignCenter)
+
+			dropdown.SetSelectedFunc(func(text string, index int) {
+				t.config.HallucinationFilter = hallucinationFilters[index].value
+				processingList.SetItemText(12, "幻覚フィルタ", hallucinationFilterDisplay(t.config.HallucinationFilter))
+				closeEditDialog()
+			})
+
+			dropdown.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEscape {
+					closeEditDialog()
+					return nil
+				}
+				return event
+			})
+
+			showEditDialog("幻覚フィルタ", dropdown)
+
+		case 13: // Custom vocabulary
+			field := tview.NewInputField().
+				SetLabel("名前・用語（カンマ区切り）: ").
+				SetText(t.config.VocabularyDisplay()).
+				SetFieldWidth(50)
+
+			field.SetBorder(true).
+				SetTitle(" カスタム語彙 (例: KoeMoji, 山田, Kubernetes) ").
+				SetTitleAlign(tview.AlignCenter)
+
+			field.SetDoneFunc(func(key tcell.Key) {
+				if key == tcell.KeyEscape {
+					closeEditDialog()
+				} else if key == tcell.KeyEnter {
+					t.config.Vocabulary = config.ParseVocabulary(field.GetText())
+					processingList.SetItemText(13, "カスタム語彙", vocabularyDisplay(t.config))
+					closeEditDialog()
+				}
+			})
+
+			showEditDialog("カスタム語彙", field)
+
+		case 14: // Vocabulary mode
+			options := make([]string, len(vocabularyModes))
+			current := 0
+			for i, m := range vocabularyModes {
+				options[i] = m.label
+				if m.value == t.config.VocabularyMode {
+					current = i
+				}
+			}
+
+			dropdown := tview.NewDropDown().
+				SetLabel("Whisperへの渡し方: ").
+				SetOptions(options, nil).
+				SetCurrentOption(current)
+
+			dropdown.SetBorder(true).
+				SetTitle(" 語彙の渡し方（ホットワードはwhisper-ctranslate2のみ） ").
+				SetTitleAlign(tview.AlignCenter)
+
+			dropdown.SetSelectedFunc(func(text string, index int) {
+				t.config.VocabularyMode = vocabularyModes[index].value
+				processingList.SetItemText(14, "語彙の渡し方", vocabularyModeDisplay(t.config.VocabularyMode))
+				closeEditDialog()
+			})
+
+			dropdown.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEscape {
+					closeEditDialog()
+					return nil
+				}
+				return event
+			})
+
+			showEditDialog("語彙の渡し方", dropdown)
+
+		case 15: // Clean transcript toggle
+			list := tview.NewList().ShowSecondaryText(false)
+			list.AddItem("有効（フィラー除去・句読点・全角半角を整えた_cleanファイルも出力）", "", '1', nil)
+			list.AddItem("無効", "", '2', nil)
+
+			if t.config.CleanTranscript {
+				list.SetCurrentItem(0)
+			} else {
+				list.SetCurrentItem(1)
+			}
+
+			list.SetBorder(true).
+				SetTitle(" 整文版の出力 ").
+				SetTitleAlign(tview.AlignCenter)
+
+			list.SetSelectedFunc(func(idx int, text, secondary string, r rune) {
+				t.config.CleanTranscript = (idx == 0)
+				processingList.SetItemText(15, "整文版の出力", enabledDisplay(t.config.CleanTranscript))
+				closeEditDialog()
+			})
+
+			list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEscape {
+					closeEditDialog()
+					return nil
+				}
+				return event
+			})
+
+			showEditDialog("整文版の出力", list)
+
+		case 16: // Summary source
+			options := make([]string, len(summarySources))
+			current := 0
+			for i, s := range summarySources {
+				options[i] = s.label
+				if s.value == t.config.SummarySource {
+					current = i
+				}
+			}
+
+			dropdown := tview.NewDropDown().
+				SetLabel("要約に使う文字起こし: ").
+				SetOptions(options, nil).
+				SetCurrentOption(current)
+
+			dropdown.SetBorder(true).
+				SetTitle(" 要約の元（整文版は出力している場合のみ） ").
+				SetTitleAlign(tview.AlignCenter)
+
+			dropdown.SetSelectedFunc(func(text string, index int) {
+				t.config.SummarySource = summarySources[index].value
+				processingList.SetItemText(16, "要約の元", summarySourceDisplay(t.config.SummarySource))
+				closeEditDialog()
+			})
+
+			dropdown.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+				if event.Key() == tcell.KeyEscape {
+					closeEditDialog()
+					return nil
+				}
+				return event
+			})
+
+			showEditDialog("要約の元", dropdown)
+		}
+	})
+
 	// Save function
 	saveConfig := func() {
 		// Configuration is already saved in real-time during editing
@@ -1272,11 +2198,13 @@ func (t *TUI) showConfigDialog() {
 		case 3:
 			t.app.SetFocus(recordingList)
 		case 4:
-			// Separator - do nothing
+			t.app.SetFocus(processingList)
 		case 5:
+			// Separator - do nothing
+		case 6:
 			// Save
 			saveConfig()
-		case 6:
+		case 7:
 			// Cancel
 			t.app.SetRoot(t.mainFlex, true)
 		}
@@ -1331,6 +2259,8 @@ func (t *TUI) showConfigDialog() {
 				t.app.SetFocus(llmList)
 			case 3:
 				t.app.SetFocus(recordingList)
+			case 4:
+				t.app.SetFocus(processingList)
 			}
 			return nil
 		}
@@ -1370,6 +2300,7 @@ func (t *TUI) showConfigDialog() {
 	dirList.SetInputCapture(listInputCapture)
 	llmList.SetInputCapture(listInputCapture)
 	recordingList.SetInputCapture(listInputCapture)
+	processingList.SetInputCapture(listInputCapture)
 
 	// Show dialog
 	t.app.SetRoot(mainContainer, true).SetFocus(categoryList)
@@ -1,11 +1,15 @@
 package ui
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestTUICallbacks_Initialization tests TUICallbacks struct initialization
@@ -198,7 +202,7 @@ func TestTUI_StatusFields(t *testing.T) {
 	assert.Equal(t, 0, tui.inputCount)
 	assert.Equal(t, 0, tui.outputCount)
 	assert.Equal(t, 0, tui.archiveCount)
-	assert.Empty(t, tui.processingFile)
+	assert.Empty(t, tui.processingFiles)
 	assert.False(t, tui.isProcessing)
 	assert.False(t, tui.isRecording)
 	assert.True(t, tui.recordingStart.IsZero())
@@ -223,6 +227,44 @@ func TestTUI_TimeTracking(t *testing.T) {
 	assert.WithinDuration(t, time.Now(), tui.startTime, time.Second)
 }
 
+// TestTUI_ProgressText tests the progress shown for running transcriptions
+func TestTUI_ProgressText(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	callbacks := &TUICallbacks{
+		Progress: func(path string) (float64, string, bool) {
+			switch path {
+			case "/input/a.wav":
+				return 42, "こんにちは", true
+			case "/input/b.mp3":
+				return -1, "hello", true
+			}
+			return 0, "", false
+		},
+	}
+
+	tui := NewTUI(cfg, callbacks)
+	tui.processingFiles = []string{"/input/a.wav", "/input/b.mp3", "/input/c.wav"}
+
+	assert.Equal(t, "a.wav 42%", tui.progressText(), "files without a known percentage are left out")
+	assert.Equal(t, "[green]♪ a.wav:[white] こんにちは\n[green]♪ b.mp3:[white] hello\n", tui.lastHeardText())
+}
+
+// TestRuleSourceLines tests the lines offered when adding a replacement rule
+func TestRuleSourceLines(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.OutputDir = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.OutputDir, "old.txt"), []byte("古い行\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.OutputDir, "meeting.txt"), []byte("コエモジの説明\n\n議事禄です\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.OutputDir, "meeting_summary.txt"), []byte("要約\n"), 0644))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(cfg.OutputDir, "old.txt"), old, old))
+
+	logs := []logger.LogEntry{{Message: "古いログ"}, {Message: "議事禄です"}, {Message: "新しいログ"}}
+	assert.Equal(t, []string{"認識中", "コエモジの説明", "議事禄です", "新しいログ", "古いログ"},
+		RuleSourceLines(cfg, []string{"認識中", ""}, logs),
+		"current text, the latest transcript, then the log newest first, without duplicates")
+}
+
 // Benchmark tests
 func BenchmarkVolumeFloatToIndex(b *testing.B) {
 	b.ResetTimer()
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/jobs"
 	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
 )
 
@@ -27,8 +28,8 @@ const (
 // UI functions
 func RefreshDisplay(config *config.Config, startTime, lastScanTime time.Time, logBuffer *[]logger.LogEntry,
 	logMutex *sync.RWMutex, inputCount, outputCount, archiveCount int, queuedFiles *[]string,
-	processingFile string, isProcessing bool, mu *sync.Mutex,
-	isRecording bool, recordingStartTime time.Time) {
+	processingFiles *[]string, waitingFiles []string, isProcessing bool, mu *sync.Mutex,
+	isRecording bool, recordingStartTime time.Time, eta jobs.ETA, nextWindow func() (time.Time, bool)) {
 
 	if config == nil {
 		return
@@ -38,14 +39,14 @@ func RefreshDisplay(config *config.Config, startTime, lastScanTime time.Time, lo
 	fmt.Print("\033[2J\033[H")
 
 	displayHeader(config, startTime, lastScanTime, inputCount, outputCount, archiveCount,
-		queuedFiles, processingFile, isProcessing, mu, isRecording, recordingStartTime)
+		queuedFiles, processingFiles, waitingFiles, isProcessing, mu, isRecording, recordingStartTime, eta, nextWindow)
 	displayRealtimeLogs(config, logBuffer, logMutex)
 	displayCommands(config)
 }
 
 func displayHeader(config *config.Config, startTime, lastScanTime time.Time, inputCount, outputCount, archiveCount int,
-	queuedFiles *[]string, processingFile string, isProcessing bool, mu *sync.Mutex,
-	isRecording bool, recordingStartTime time.Time) {
+	queuedFiles *[]string, processingFiles *[]string, waitingFiles []string, isProcessing bool, mu *sync.Mutex,
+	isRecording bool, recordingStartTime time.Time, eta jobs.ETA, nextWindow func() (time.Time, bool)) {
 
 	updateFileCounts(config, &inputCount, &outputCount, &archiveCount)
 	msg := GetMessages(config)
@@ -62,16 +63,31 @@ func displayHeader(config *config.Config, startTime, lastScanTime time.Time, inp
 	mu.Lock()
 	queueCount := len(*queuedFiles)
 	processingDisplay := msg.None
-	if processingFile != "" {
-		processingDisplay = processingFile
+	if len(*processingFiles) > 0 {
+		processingDisplay = JoinFileNames(*processingFiles)
 	}
 	mu.Unlock()
 
 	fmt.Printf("%s | %s: %d | %s: %s\n",
 		status, msg.Queue, queueCount, msg.Processing, processingDisplay)
+	if etaText := FormatETA(eta, msg); etaText != "" {
+		fmt.Printf("⌛ %s\n", etaText)
+	}
 	fmt.Printf("📁 %s: %d → %s: %d → %s: %d\n",
 		msg.Input, inputCount, msg.Output, outputCount, msg.Archive, archiveCount)
 
+	// Files still being copied/written into the input directory
+	if len(waitingFiles) > 0 {
+		fmt.Printf("⏳ %s(%d): %s\n", msg.Waiting, len(waitingFiles), JoinFileNames(waitingFiles))
+	}
+
+	// Queued files held back by processing_schedule (not while "process now" runs)
+	if nextWindow != nil {
+		if next, ok := nextWindow(); ok {
+			fmt.Printf("🕒 %s: %s\n", msg.NextWindow, FormatNextWindow(next))
+		}
+	}
+
 	// Recording status
 	if isRecording {
 		elapsed := time.Since(recordingStartTime)
@@ -309,3 +325,55 @@ func IsAudioFile(filename string) bool {
 	}
 	return false
 }
+
+// FormatNextWindow formats the start of the next processing window for status displays
+func FormatNextWindow(t time.Time) string {
+	return t.Format("01/02(Mon) 15:04")
+}
+
+// FormatETA formats the estimated remaining time of the running files and
+// the whole queue, e.g. "ETA: current 3m12s / queue 15m0s".
+// It returns "" when nothing is running or queued.
+func FormatETA(eta jobs.ETA, msg *Messages) string {
+	if eta.Running == 0 && eta.Queued == 0 {
+		return ""
+	}
+	estimate := func(d time.Duration, known bool) string {
+		if !known {
+			return msg.ETAUnknown
+		}
+		return formatDuration(d)
+	}
+
+	text := msg.ETA + ": "
+	if eta.Running > 0 {
+		text += fmt.Sprintf("%s %s / ", msg.ETACurrent, estimate(eta.Current, eta.CurrentKnown))
+	}
+	return text + fmt.Sprintf("%s %s", msg.ETATotal, estimate(eta.Total, eta.TotalKnown))
+}
+
+// JoinFileNames returns the base names of paths separated by commas (for status displays)
+func JoinFileNames(paths []string) string {
+	names := make([]string, len(paths))
+	for i, p := range paths {
+		names[i] = filepath.Base(p)
+	}
+	return strings.Join(names, ", ")
+}
+
+// JoinFileNamesWithProfiles is JoinFileNames with the processing profile of each
+// file appended in parentheses, e.g. "call.m4a (english), memo.m4a".
+// profileOf may be nil.
+func JoinFileNamesWithProfiles(paths []string, profileOf func(path string) string) string {
+	names := make([]string, len(paths))
+	for i, p := range paths {
+		names[i] = filepath.Base(p)
+		if profileOf == nil {
+			continue
+		}
+		if profile := profileOf(p); profile != "" {
+			names[i] += " (" + profile + ")"
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,256 @@
+package whisper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/transcript"
+	"github.com/infoHiroki/KoeMoji-Go/internal/ui"
+)
+
+// Segment is one stretch of recognized speech
+type Segment = transcript.Segment
+
+// Result is what a backend produced for one input file
+type Result struct {
+	Segments    []Segment
+	OutputFiles []string // Written into the (mirrored) output directory
+}
+
+// Capabilities describes what a backend supports
+type Capabilities struct {
+	OutputFormats []string // Values of output_format the backend can write
+	Progress      bool     // Reports progress while transcribing (CurrentProgress)
+	Local         bool     // Runs on this machine; audio is not uploaded anywhere
+}
+
+// SupportsFormat reports whether format is one of OutputFormats
+func (c Capabilities) SupportsFormat(format string) bool {
+	for _, f := range c.OutputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Transcriber is a transcription backend, selected by transcription_backend.
+// A Transcriber is created for one configuration (after processing profiles)
+// by NewTranscriber.
+type Transcriber interface {
+	// Name returns the transcription_backend value of the backend
+	Name() string
+	Capabilities() Capabilities
+	// Available returns nil when the backend can transcribe, otherwise what is missing
+	Available() error
+	// Transcribe writes the outputs for inputFile. Cancelling ctx stops the
+	// transcription and returns an error that wraps ctx.Err().
+	Transcribe(ctx context.Context, log *log.Logger, logBuffer *[]logger.LogEntry,
+		logMutex *sync.RWMutex, debugMode bool, inputFile string) (*Result, error)
+}
+
+// Installer is implemented by backends that EnsureDependencies can install
+type Installer interface {
+	Install(log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex) error
+}
+
+// whisperFormats are the output formats the backends write. Every backend
+// returns segments and the files are rendered from them (transcript.Render).
+var whisperFormats = transcript.Formats
+
+// NewTranscriber returns the backend selected by c.TranscriptionBackend.
+// An empty value selects whisper-ctranslate2. With chunk_minutes set, long
+// inputs are transcribed in resumable chunks.
+func NewTranscriber(c *config.Config) (Transcriber, error) {
+	backend, err := newBackend(c)
+	if err != nil {
+		return nil, err
+	}
+	if c.ChunkMinutes > 0 {
+		return &chunkedTranscriber{Transcriber: backend, config: c}, nil
+	}
+	return backend, nil
+}
+
+// newBackend returns the backend itself, without chunking
+func newBackend(c *config.Config) (Transcriber, error) {
+	switch c.TranscriptionBackend {
+	case config.BackendWhisperCTranslate2, "":
+		return &ctranslate2Backend{config: c}, nil
+	case config.BackendWhisperCpp:
+		return &whisperCppBackend{config: c}, nil
+	case config.BackendOpenAI:
+		return &remoteBackend{config: c}, nil
+	case config.BackendMock:
+		return &mockBackend{config: c}, nil
+	}
+	return nil, newTranscribeError(ErrorKindSetup, fmt.Errorf("unknown transcription_backend %q (available: %s)",
+		c.TranscriptionBackend, strings.Join(config.TranscriptionBackends, ", ")))
+}
+
+// EnsureDependencies checks that the configured backend can run and installs
+// it when the backend supports that
+func EnsureDependencies(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry,
+	logMutex *sync.RWMutex, debugMode bool) error {
+
+	transcriber, err := newBackend(config)
+	if err != nil {
+		return err
+	}
+
+	availErr := transcriber.Available()
+	if availErr == nil {
+		logger.LogDebug(log, logBuffer, logMutex, debugMode, "Transcription backend %s is available", transcriber.Name())
+		return nil
+	}
+
+	installer, ok := transcriber.(Installer)
+	if !ok {
+		return fmt.Errorf("transcription backend %s is not available: %w", transcriber.Name(), availErr)
+	}
+	return installer.Install(log, logBuffer, logMutex)
+}
+
+// prepareTranscription checks inputFile and creates its output directory.
+// Every backend calls it before transcribing.
+func prepareTranscription(config *config.Config, inputFile string) (string, error) {
+	// セキュリティチェック: inputディレクトリ内のファイルのみ許可
+	absPath, err := filepath.Abs(inputFile)
+	if err != nil {
+		msg := ui.GetMessages(config)
+		return "", newTranscribeError(ErrorKindSetup, fmt.Errorf(msg.InvalidPath, err))
+	}
+	inputDir, err := filepath.Abs(config.InputDir)
+	if err != nil {
+		msg := ui.GetMessages(config)
+		return "", newTranscribeError(ErrorKindSetup, fmt.Errorf(msg.InvalidPath, err))
+	}
+	if !strings.HasPrefix(absPath, inputDir+string(os.PathSeparator)) {
+		msg := ui.GetMessages(config)
+		return "", newTranscribeError(ErrorKindSetup, fmt.Errorf(msg.InvalidPath, inputFile))
+	}
+
+	// 入力ファイルの存在チェック
+	if _, err := os.Stat(inputFile); os.IsNotExist(err) {
+		return "", newTranscribeError(ErrorKindSetup, fmt.Errorf("input file does not exist: %s", inputFile))
+	}
+
+	// 入力ディレクトリの存在チェック
+	if _, err := os.Stat(config.InputDir); os.IsNotExist(err) {
+		return "", newTranscribeError(ErrorKindSetup, fmt.Errorf("input directory does not exist: %s", config.InputDir))
+	}
+
+	// 出力ディレクトリの作成確認（サブフォルダの入力は同じ構成で出力）
+	outputDir := config.MirrorDir(config.OutputDir, inputFile)
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", newTranscribeError(ErrorKindSetup, fmt.Errorf("failed to create output directory: %w", err))
+	}
+	return outputDir, nil
+}
+
+// checkFormats returns an error when the backend cannot write one of the
+// configured output formats
+func checkFormats(caps Capabilities, c *config.Config) error {
+	for _, format := range c.Formats() {
+		if !caps.SupportsFormat(format) {
+			return newTranscribeError(ErrorKindSetup, fmt.Errorf("unsupported output format: %s", format))
+		}
+	}
+	return nil
+}
+
+// writeOutputs writes segments in every configured output format and
+// validates each file. A file that cannot be written (full disk, missing
+// permissions) is a setup error: the recording itself is fine.
+func writeOutputs(outputDir, inputFile string, c *config.Config, segments []Segment) ([]string, error) {
+	doc := NewTranscript(c, inputFile, segments)
+	var outputs []string
+	for _, format := range c.Formats() {
+		outputFile := outputPath(outputDir, inputFile, format)
+		if err := transcript.WriteFile(outputFile, format, doc, RenderOptions(c)); err != nil {
+			return nil, newTranscribeError(ErrorKindSetup, err)
+		}
+		if err := validateOutputFile(outputFile, c); err != nil {
+			return nil, newTranscribeError(ErrorKindOutput, err)
+		}
+		outputs = append(outputs, outputFile)
+	}
+	return outputs, nil
+}
+
+// CleanOutputSuffix is added to the base name of the clean transcript
+// (meeting.txt and meeting_clean.txt)
+const CleanOutputSuffix = "_clean"
+
+// RewriteOutputs writes the outputs of inputFile again from segments, after
+// the post-processing changed them. Unlike writeOutputs it accepts empty
+// files: an input that was nothing but silence has no text.
+func RewriteOutputs(c *config.Config, inputFile string, segments []Segment) error {
+	_, err := rewriteOutputs(c, inputFile, "", segments)
+	return err
+}
+
+// WriteCleanOutputs writes the clean transcript of inputFile in every output
+// format, next to the verbatim one, and returns the files written
+func WriteCleanOutputs(c *config.Config, inputFile string, segments []Segment) ([]string, error) {
+	return rewriteOutputs(c, inputFile, CleanOutputSuffix, segments)
+}
+
+func rewriteOutputs(c *config.Config, inputFile, suffix string, segments []Segment) ([]string, error) {
+	outputDir := c.MirrorDir(c.OutputDir, inputFile)
+	doc := NewTranscript(c, inputFile, segments)
+	var outputs []string
+	for _, format := range c.Formats() {
+		outputFile := outputPath(outputDir, inputFile, format)
+		if suffix != "" {
+			basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+			outputFile = filepath.Join(outputDir, basename+suffix+"."+format)
+		}
+		if err := transcript.WriteFile(outputFile, format, doc, RenderOptions(c)); err != nil {
+			return outputs, err
+		}
+		outputs = append(outputs, outputFile)
+	}
+	return outputs, nil
+}
+
+// NewTranscript returns the transcript of inputFile with the backend and
+// model of c
+func NewTranscript(c *config.Config, inputFile string, segments []Segment) *transcript.Transcript {
+	doc := &transcript.Transcript{
+		Source:    inputFile,
+		Language:  c.Language,
+		Backend:   c.TranscriptionBackend,
+		Model:     c.WhisperModel,
+		CreatedAt: time.Now(),
+		Segments:  segments,
+	}
+	switch c.TranscriptionBackend {
+	case "":
+		doc.Backend = config.BackendWhisperCTranslate2
+	case config.BackendOpenAI:
+		doc.Model = c.RemoteModel
+	case config.BackendMock:
+		doc.Model = ""
+	}
+	return doc
+}
+
+// RenderOptions returns how the output formats are rendered with c
+func RenderOptions(c *config.Config) transcript.Options {
+	return transcript.Options{WordTimestamps: c.WordTimestamps, LowConfidenceThreshold: c.LowConfidenceThreshold}
+}
+
+// outputPath returns where the output of inputFile in format is written
+func outputPath(outputDir, inputFile, format string) string {
+	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
+	return filepath.Join(outputDir, basename+"."+format)
+}
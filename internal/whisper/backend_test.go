@@ -0,0 +1,186 @@
+package whisper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/transcript"
+	"github.com/infoHiroki/KoeMoji-Go/internal/whisper/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTranscriber(t *testing.T) {
+	cfg := testdata.CreateTestConfig(t)
+
+	for backend, name := range map[string]string{
+		"":                               config.BackendWhisperCTranslate2,
+		config.BackendWhisperCpp:         config.BackendWhisperCpp,
+		config.BackendOpenAI:             config.BackendOpenAI,
+		config.BackendWhisperCTranslate2: config.BackendWhisperCTranslate2,
+		config.BackendMock:               config.BackendMock,
+	} {
+		cfg.TranscriptionBackend = backend
+		transcriber, err := NewTranscriber(cfg)
+		require.NoError(t, err, backend)
+		assert.Equal(t, name, transcriber.Name())
+	}
+
+	cfg.TranscriptionBackend = "unknown-engine"
+	_, err := NewTranscriber(cfg)
+	require.Error(t, err)
+	assert.Equal(t, ErrorKindSetup, ClassifyError(err))
+}
+
+func TestMockBackend_Transcribe(t *testing.T) {
+	cfg := testdata.CreateTestConfig(t)
+	cfg.TranscriptionBackend = config.BackendMock
+	cfg.OutputFormat = "srt"
+	logger, logBuffer, logMutex := testdata.CreateTestLogger()
+
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	input := filepath.Join(cfg.InputDir, "meeting.wav")
+	require.NoError(t, os.WriteFile(input, wavBytes(12*32000, 12*32000), 0644)) // 12 seconds
+
+	transcriber, err := NewTranscriber(cfg)
+	require.NoError(t, err)
+	require.NoError(t, transcriber.Available())
+
+	result, err := transcriber.Transcribe(context.Background(), logger, logBuffer, logMutex, false, input)
+	require.NoError(t, err)
+	assert.Equal(t, []Segment{
+		{Start: 0, End: 5 * time.Second, Text: "テスト用の文字起こし 1"},
+		{Start: 5 * time.Second, End: 10 * time.Second, Text: "テスト用の文字起こし 2"},
+		{Start: 10 * time.Second, End: 12 * time.Second, Text: "テスト用の文字起こし 3"},
+	}, result.Segments)
+	require.Equal(t, []string{filepath.Join(cfg.OutputDir, "meeting.srt")}, result.OutputFiles)
+
+	data, err := os.ReadFile(result.OutputFiles[0])
+	require.NoError(t, err)
+	assert.Equal(t, "1\n00:00:00,000 --> 00:00:05,000\nテスト用の文字起こし 1\n\n"+
+		"2\n00:00:05,000 --> 00:00:10,000\nテスト用の文字起こし 2\n\n"+
+		"3\n00:00:10,000 --> 00:00:12,000\nテスト用の文字起こし 3\n\n", string(data))
+
+	_, running := CurrentProgress(input)
+	assert.False(t, running, "progress is removed when the backend finishes")
+}
+
+func TestMockBackend_CancelledContext(t *testing.T) {
+	cfg := testdata.CreateTestConfig(t)
+	cfg.TranscriptionBackend = config.BackendMock
+	logger, logBuffer, logMutex := testdata.CreateTestLogger()
+
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	input := filepath.Join(cfg.InputDir, "meeting.wav")
+	require.NoError(t, os.WriteFile(input, wavBytes(32000, 32000), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	transcriber, err := NewTranscriber(cfg)
+	require.NoError(t, err)
+	_, err = transcriber.Transcribe(ctx, logger, logBuffer, logMutex, false, input)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NoFileExists(t, filepath.Join(cfg.OutputDir, "meeting.txt"))
+}
+
+func TestCTranslate2Args(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.WhisperModel = "medium"
+	cfg.MaxCpuPercent = 100
+
+	assert.Equal(t, []string{
+		"--model", "medium",
+		"--language", "ja",
+		"--output_dir", "/tmp/out",
+		"--output_format", "json",
+		"--compute_type", "int8",
+		"--device", "cpu",
+		"--verbose", "True", "/tmp/in.wav",
+	}, ctranslate2Args(cfg, "/tmp/out", "/tmp/in.wav"))
+
+	cfg.LowConfidenceThreshold = 0.5
+	assert.Contains(t, strings.Join(ctranslate2Args(cfg, "/tmp/out", "/tmp/in.wav"), " "), "--word_timestamps True",
+		"marking uncertain words needs the word probabilities")
+
+	cfg.Vocabulary = []string{"KoeMoji", "山田"}
+	cfg.Language = "ja"
+	assert.Contains(t, strings.Join(ctranslate2Args(cfg, "/tmp/out", "/tmp/in.wav"), " "), "--initial_prompt KoeMoji、山田")
+	cfg.VocabularyMode = config.VocabularyModeHotwords
+	args := strings.Join(ctranslate2Args(cfg, "/tmp/out", "/tmp/in.wav"), " ")
+	assert.Contains(t, args, "--hotwords KoeMoji、山田")
+	assert.NotContains(t, args, "--initial_prompt")
+}
+
+func TestMockBackend_SeveralFormats(t *testing.T) {
+	cfg := testdata.CreateTestConfig(t)
+	cfg.TranscriptionBackend = config.BackendMock
+	cfg.OutputFormats = []string{"vtt", "txt"}
+	logger, logBuffer, logMutex := testdata.CreateTestLogger()
+
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	input := filepath.Join(cfg.InputDir, "clip.wav")
+	require.NoError(t, os.WriteFile(input, wavBytes(32000, 32000), 0644))
+
+	transcriber, err := NewTranscriber(cfg)
+	require.NoError(t, err)
+	result, err := transcriber.Transcribe(context.Background(), logger, logBuffer, logMutex, false, input)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(cfg.OutputDir, "clip.vtt"), filepath.Join(cfg.OutputDir, "clip.txt")}, result.OutputFiles)
+
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "clip.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "テスト用の文字起こし 1\n", string(data))
+}
+
+func TestMockBackend_UnwritableOutputIsSetupError(t *testing.T) {
+	cfg := testdata.CreateTestConfig(t)
+	cfg.TranscriptionBackend = config.BackendMock
+	cfg.OutputFormat = "txt"
+	logger, logBuffer, logMutex := testdata.CreateTestLogger()
+
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	input := filepath.Join(cfg.InputDir, "clip.wav")
+	require.NoError(t, os.WriteFile(input, wavBytes(32000, 32000), 0644))
+	// A directory in place of the output file cannot be written
+	require.NoError(t, os.MkdirAll(filepath.Join(cfg.OutputDir, "clip.txt", "blocked"), 0755))
+
+	transcriber, err := NewTranscriber(cfg)
+	require.NoError(t, err)
+	_, err = transcriber.Transcribe(context.Background(), logger, logBuffer, logMutex, false, input)
+	require.Error(t, err)
+	assert.Equal(t, ErrorKindSetup, ClassifyError(err), "the recording is not blamed for the output folder")
+}
+
+func TestReadCLIOutput(t *testing.T) {
+	cfg := testdata.CreateTestConfig(t)
+	dir := t.TempDir()
+	input := filepath.Join(cfg.InputDir, "talk.wav")
+
+	_, err := readCLIOutput(cfg, dir, input)
+	assert.Error(t, err, "whisper wrote no JSON")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "talk.json"), []byte(`{
+		"text": " Hello there.",
+		"segments": [{"id": 0, "seek": 0, "start": 0.0, "end": 1.84, "text": " Hello there.",
+			"tokens": [50364, 2425], "temperature": 0.0, "avg_logprob": -0.25, "compression_ratio": 0.9, "no_speech_prob": 0.03,
+			"words": [{"start": 0.0, "end": 0.62, "word": " Hello", "probability": 0.91},
+				{"start": 0.62, "end": 1.84, "word": " there.", "probability": 0.76}]},
+			{"id": 1, "seek": 0, "start": 1.84, "end": 2.0, "text": " ", "avg_logprob": -1.2, "no_speech_prob": 0.9}],
+		"language": "en"}`), 0644))
+
+	segments, err := readCLIOutput(cfg, dir, input)
+	require.NoError(t, err)
+	assert.Equal(t, []Segment{{
+		Start: 0, End: 1840 * time.Millisecond, Text: "Hello there.",
+		Words: []transcript.Word{
+			{Start: 0, End: 620 * time.Millisecond, Text: " Hello", Probability: 0.91},
+			{Start: 620 * time.Millisecond, End: 1840 * time.Millisecond, Text: " there.", Probability: 0.76},
+		},
+		AvgLogprob: -0.25, NoSpeechProb: 0.03,
+	}}, segments, "segments without text are dropped")
+}
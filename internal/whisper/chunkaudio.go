@@ -0,0 +1,221 @@
+package whisper
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// silenceFrame is the length over which the loudness is measured
+	silenceFrame = 20 * time.Millisecond
+	// silenceWindow is how long a pause has to be to cut the audio in it
+	silenceWindow = 500 * time.Millisecond
+	// silenceSearch is how far before the planned end of a chunk a pause is
+	// searched for
+	silenceSearch = 60 * time.Second
+)
+
+// chunkSpan is the part of the input one chunk covers
+type chunkSpan struct {
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+}
+
+// planChunks splits audio into chunks of about length. Each cut is placed at
+// the quietest point of the stretch before the planned end, so that words
+// are not cut in half; the last chunk takes a remainder of up to a quarter
+// of length instead of leaving a short chunk.
+func planChunks(audio, length time.Duration, quietest func(from, to time.Duration) (time.Duration, error)) ([]chunkSpan, error) {
+	search := length / 4
+	if search > silenceSearch {
+		search = silenceSearch
+	}
+
+	var chunks []chunkSpan
+	start := time.Duration(0)
+	for audio-start > length+length/4 {
+		target := start + length
+		cut, err := quietest(target-search, target)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunkSpan{Start: start, End: cut})
+		start = cut
+	}
+	return append(chunks, chunkSpan{Start: start, End: audio}), nil
+}
+
+// pcmSource is a 16-bit PCM WAV file that chunks are cut from
+type pcmSource struct {
+	path string
+	info wavInfo
+}
+
+// openPCMSource reads the header of a 16-bit PCM WAV file. Other files
+// return an error and have to be converted first (convertToPCM).
+func openPCMSource(path string) (*pcmSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := readWAVInfo(file)
+	if err != nil {
+		return nil, err
+	}
+	if (info.format != 1 && info.format != 0xFFFE) || info.bitDepth != 16 || info.channels == 0 || info.sampleRate == 0 {
+		return nil, fmt.Errorf("not 16-bit PCM: %s", filepath.Base(path))
+	}
+	if info.blockAlign == 0 {
+		info.blockAlign = info.channels * 2
+	}
+	return &pcmSource{path: path, info: info}, nil
+}
+
+// duration returns the playing time of the samples
+func (s *pcmSource) duration() time.Duration {
+	return s.position(s.info.dataSize / int64(s.info.blockAlign))
+}
+
+// position returns the time of sample frame n
+func (s *pcmSource) position(n int64) time.Duration {
+	return time.Duration(n * int64(time.Second) / int64(s.info.sampleRate))
+}
+
+// frame returns the sample frame at d, limited to the data chunk
+func (s *pcmSource) frame(d time.Duration) int64 {
+	n := int64(d) * int64(s.info.sampleRate) / int64(time.Second)
+	if last := s.info.dataSize / int64(s.info.blockAlign); n > last {
+		return last
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// quietest returns the centre of the quietest silenceWindow in [from, to),
+// measured as the energy of all channels
+func (s *pcmSource) quietest(from, to time.Duration) (time.Duration, error) {
+	first, last := s.frame(from), s.frame(to)
+	file, err := os.Open(s.path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	align := int64(s.info.blockAlign)
+	data := make([]byte, (last-first)*align)
+	if _, err := file.ReadAt(data, s.info.dataOffset+first*align); err != nil && err != io.EOF {
+		return 0, err
+	}
+
+	// Energy of each silenceFrame
+	frameSamples := int(s.frame(silenceFrame))
+	if frameSamples == 0 {
+		frameSamples = 1
+	}
+	var energy []float64
+	for offset := 0; offset+frameSamples*int(align) <= len(data); offset += frameSamples * int(align) {
+		var sum float64
+		for i := offset; i < offset+frameSamples*int(align); i += 2 {
+			sample := float64(int16(binary.LittleEndian.Uint16(data[i:])))
+			sum += sample * sample
+		}
+		energy = append(energy, sum)
+	}
+
+	window := int(silenceWindow / silenceFrame)
+	if len(energy) < window {
+		return to, nil
+	}
+	best, bestSum := 0, math.Inf(1)
+	var sum float64
+	for i, e := range energy {
+		sum += e
+		if i >= window {
+			sum -= energy[i-window]
+		}
+		// <= so that of equally quiet stretches the latest one is used
+		if i >= window-1 && sum <= bestSum {
+			best, bestSum = i-window+1, sum
+		}
+	}
+	centre := int64(best*frameSamples) + int64(window*frameSamples)/2
+	return s.position(first + centre), nil
+}
+
+// writeChunk writes the samples of span to path as a WAV file
+func (s *pcmSource) writeChunk(span chunkSpan, path string) error {
+	in, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	align := int64(s.info.blockAlign)
+	first, last := s.frame(span.Start), s.frame(span.End)
+	size := (last - first) * align
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := writeWAVHeader(out, s.info, size); err != nil {
+		out.Close()
+		return err
+	}
+	if _, err := io.Copy(out, io.NewSectionReader(in, s.info.dataOffset+first*align, size)); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return out.Close()
+}
+
+// writeWAVHeader writes a 44 byte PCM header for dataSize bytes of samples
+func writeWAVHeader(w io.Writer, info wavInfo, dataSize int64) error {
+	header := make([]byte, 44)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(36+dataSize))
+	copy(header[8:], "WAVE")
+	copy(header[12:], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	binary.LittleEndian.PutUint16(header[20:], 1) // PCM
+	binary.LittleEndian.PutUint16(header[22:], info.channels)
+	binary.LittleEndian.PutUint32(header[24:], info.sampleRate)
+	binary.LittleEndian.PutUint32(header[28:], info.sampleRate*uint32(info.blockAlign))
+	binary.LittleEndian.PutUint16(header[32:], info.blockAlign)
+	binary.LittleEndian.PutUint16(header[34:], 16)
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[40:], uint32(dataSize))
+	_, err := w.Write(header)
+	return err
+}
+
+// convertToPCM decodes inputFile (FLAC or a WAV that is not 16-bit PCM) to
+// 16 kHz mono 16-bit WAV with ffmpeg. whisper resamples to 16 kHz mono
+// anyway, so nothing is lost for the transcription.
+func convertToPCM(ctx context.Context, inputFile, wavFile string) error {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return newTranscribeError(ErrorKindSetup,
+			fmt.Errorf("ffmpeg is needed to split %s into chunks: %w", filepath.Base(inputFile), err))
+	}
+	cmd := createCommandContext(ctx, "ffmpeg", "-nostdin", "-y", "-loglevel", "error",
+		"-i", inputFile, "-ar", "16000", "-ac", "1", "-c:a", "pcm_s16le", wavFile)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("transcription cancelled: %w", ctxErr)
+		}
+		return fmt.Errorf("ffmpeg conversion failed: %w, output: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package whisper
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/transcript"
+)
+
+// checkpointRoot is where the finished chunks of long inputs are kept until
+// the whole file is done. A variable so that tests can use a temp directory.
+var checkpointRoot = func() string {
+	return filepath.Join(config.GetAppBaseDir(), "chunks")
+}
+
+// checkpointMaxAge is how long the checkpoints of inputs that never finished
+// (deleted or moved away) are kept
+const checkpointMaxAge = 7 * 24 * time.Hour
+
+// chunkedTranscriber transcribes WAV and FLAC inputs longer than
+// chunk_minutes in chunks with the wrapped backend. Every finished chunk is
+// saved to disk, so a crash or restart only repeats the chunk that was
+// running; the outputs are written from the stitched segments at the end.
+type chunkedTranscriber struct {
+	Transcriber
+	config *config.Config
+}
+
+// chunkable reports whether inputFile can be split in Go
+func chunkable(inputFile string) bool {
+	ext := strings.ToLower(filepath.Ext(inputFile))
+	return ext == ".wav" || ext == ".flac"
+}
+
+func (t *chunkedTranscriber) Transcribe(ctx context.Context, log *log.Logger, logBuffer *[]logger.LogEntry,
+	logMutex *sync.RWMutex, debugMode bool, inputFile string) (*Result, error) {
+
+	length := time.Duration(t.config.ChunkMinutes) * time.Minute
+	if !chunkable(inputFile) {
+		return t.Transcriber.Transcribe(ctx, log, logBuffer, logMutex, debugMode, inputFile)
+	}
+	audio, err := AudioDuration(inputFile)
+	if err != nil || audio <= length+length/4 {
+		return t.Transcriber.Transcribe(ctx, log, logBuffer, logMutex, debugMode, inputFile)
+	}
+
+	outputDir, err := prepareTranscription(t.config, inputFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFormats(t.Capabilities(), t.config); err != nil {
+		return nil, err
+	}
+	if err := t.Available(); err != nil {
+		return nil, newTranscribeError(ErrorKindSetup, err)
+	}
+
+	pruneCheckpoints(checkpointRoot(), checkpointMaxAge)
+	cp, err := openCheckpoint(ctx, t.config, inputFile, length)
+	if err != nil {
+		return nil, err
+	}
+
+	stopProgress := startProgress(inputFile, audio)
+	defer stopProgress()
+
+	// The chunks are transcribed inside the checkpoint directory, as JSON so
+	// that a chunk without speech still has an output
+	chunkConfig := *t.config
+	chunkConfig.InputDir = cp.dir
+	chunkConfig.OutputDir = filepath.Join(cp.dir, "out")
+	chunkConfig.OutputFormat = "json"
+	chunkConfig.OutputFormats = nil
+	chunkConfig.ChunkMinutes = 0
+	backend, err := newBackend(&chunkConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if done := cp.finished(); done > 0 {
+		logger.LogInfo(log, logBuffer, logMutex, "Resuming %s from part %d/%d", filepath.Base(inputFile), done+1, len(cp.manifest.Chunks))
+	}
+
+	startTime := time.Now()
+	var segments []Segment
+	for i, span := range cp.manifest.Chunks {
+		if saved, ok := cp.load(i); ok {
+			segments = append(segments, saved...)
+			advanceProgress(inputFile, span.End, "")
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("transcription cancelled: %w", err)
+		}
+
+		logger.LogInfo(log, logBuffer, logMutex, "Transcribing part %d/%d of %s (%s - %s)", i+1, len(cp.manifest.Chunks),
+			filepath.Base(inputFile), formatDuration(span.Start), formatDuration(span.End))
+		chunkFile := filepath.Join(cp.dir, fmt.Sprintf("chunk-%03d.wav", i+1))
+		if err := cp.source.writeChunk(span, chunkFile); err != nil {
+			return nil, fmt.Errorf("failed to cut part %d of %s: %w", i+1, filepath.Base(inputFile), err)
+		}
+
+		stopForward := forwardProgress(chunkFile, inputFile, span.Start)
+		result, err := backend.Transcribe(ctx, log, logBuffer, logMutex, debugMode, chunkFile)
+		stopForward()
+		os.Remove(chunkFile)
+		if err != nil {
+			return nil, err
+		}
+
+		shifted := make([]Segment, 0, len(result.Segments))
+		for _, s := range result.Segments {
+			s.Start += span.Start
+			s.End += span.Start
+			if len(s.Words) > 0 {
+				words := make([]transcript.Word, len(s.Words))
+				for k, w := range s.Words {
+					w.Start += span.Start
+					w.End += span.Start
+					words[k] = w
+				}
+				s.Words = words
+			}
+			shifted = append(shifted, s)
+		}
+		if err := cp.save(i, shifted); err != nil {
+			return nil, err
+		}
+		segments = append(segments, shifted...)
+		advanceProgress(inputFile, span.End, "")
+	}
+	logger.LogInfo(log, logBuffer, logMutex, "Transcription completed in %s", time.Since(startTime).Round(time.Second))
+
+	outputFiles, err := writeOutputs(outputDir, inputFile, t.config, segments)
+	if err != nil {
+		return nil, err
+	}
+	os.RemoveAll(cp.dir)
+	return &Result{Segments: segments, OutputFiles: outputFiles}, nil
+}
+
+// forwardProgress copies the progress of the chunk being transcribed to the
+// input file until the returned function is called
+func forwardProgress(chunkFile, inputFile string, offset time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(500 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if p, ok := CurrentProgress(chunkFile); ok {
+					advanceProgress(inputFile, offset+p.Position, p.LastText)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// chunkManifest describes the chunks of one input. A checkpoint whose
+// manifest no longer matches the input or the settings is started over.
+type chunkManifest struct {
+	Input    string      `json:"input"`
+	Size     int64       `json:"size"`
+	ModTime  time.Time   `json:"mod_time"`
+	Settings string      `json:"settings"`
+	Chunks   []chunkSpan `json:"chunks"`
+}
+
+// checkpoint is the directory with the finished chunks of one input:
+// manifest.json, chunk-NNN.json for every finished chunk and source.wav when
+// the input had to be converted before it could be cut
+type checkpoint struct {
+	dir      string
+	manifest chunkManifest
+	source   *pcmSource
+}
+
+// chunkSettings are the settings that change the transcript of a chunk
+func chunkSettings(c *config.Config, length time.Duration) string {
+	return strings.Join([]string{c.TranscriptionBackend, c.WhisperModel, c.Language, c.ComputeType,
+		c.WhisperCppModelDir, c.RemoteBaseURL, c.RemoteModel, length.String(),
+		strconv.FormatBool(c.WordTimestampsNeeded()), c.VocabularyMode, c.VocabularyText()}, "|")
+}
+
+// openCheckpoint returns the checkpoint of inputFile, creating it and
+// planning the chunks when there is none or it is out of date
+func openCheckpoint(ctx context.Context, c *config.Config, inputFile string, length time.Duration) (*checkpoint, error) {
+	absPath, err := filepath.Abs(inputFile)
+	if err != nil {
+		return nil, newTranscribeError(ErrorKindSetup, err)
+	}
+	stat, err := os.Stat(inputFile)
+	if err != nil {
+		return nil, newTranscribeError(ErrorKindSetup, err)
+	}
+	sum := sha1.Sum([]byte(absPath))
+	cp := &checkpoint{dir: filepath.Join(checkpointRoot(), hex.EncodeToString(sum[:8]))}
+	current := chunkManifest{
+		Input:    absPath,
+		Size:     stat.Size(),
+		ModTime:  stat.ModTime().UTC().Truncate(time.Second),
+		Settings: chunkSettings(c, length),
+	}
+
+	if data, err := os.ReadFile(filepath.Join(cp.dir, "manifest.json")); err == nil {
+		var saved chunkManifest
+		if json.Unmarshal(data, &saved) == nil && saved.Input == current.Input && saved.Size == current.Size &&
+			saved.ModTime.Equal(current.ModTime) && saved.Settings == current.Settings && len(saved.Chunks) > 0 {
+			if cp.source, err = openChunkSource(ctx, inputFile, cp.dir); err == nil {
+				cp.manifest = saved
+				return cp, nil
+			}
+		}
+	}
+
+	// 新しく分割する（古いチェックポイントは破棄）
+	os.RemoveAll(cp.dir)
+	if err := os.MkdirAll(cp.dir, 0755); err != nil {
+		return nil, newTranscribeError(ErrorKindSetup, fmt.Errorf("failed to create checkpoint directory: %w", err))
+	}
+	if cp.source, err = openChunkSource(ctx, inputFile, cp.dir); err != nil {
+		return nil, err
+	}
+	current.Chunks, err = planChunks(cp.source.duration(), length, cp.source.quietest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split %s: %w", filepath.Base(inputFile), err)
+	}
+	cp.manifest = current
+	if err := writeJSONFile(filepath.Join(cp.dir, "manifest.json"), current); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// openChunkSource returns the PCM samples to cut the chunks from: the input
+// itself when it is 16-bit PCM WAV, otherwise source.wav in dir decoded with
+// ffmpeg (once; a resumed job reuses it)
+func openChunkSource(ctx context.Context, inputFile, dir string) (*pcmSource, error) {
+	if strings.EqualFold(filepath.Ext(inputFile), ".wav") {
+		if source, err := openPCMSource(inputFile); err == nil {
+			return source, nil
+		}
+	}
+	wavFile := filepath.Join(dir, "source.wav")
+	if source, err := openPCMSource(wavFile); err == nil {
+		return source, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	if err := convertToPCM(ctx, inputFile, wavFile); err != nil {
+		return nil, err
+	}
+	return openPCMSource(wavFile)
+}
+
+// chunkResultPath returns where the segments of chunk i are saved
+func (cp *checkpoint) chunkResultPath(i int) string {
+	return filepath.Join(cp.dir, fmt.Sprintf("chunk-%03d.json", i+1))
+}
+
+// finished returns how many chunks are saved
+func (cp *checkpoint) finished() int {
+	count := 0
+	for i := range cp.manifest.Chunks {
+		if _, ok := cp.load(i); ok {
+			count++
+		}
+	}
+	return count
+}
+
+// load returns the saved segments of chunk i
+func (cp *checkpoint) load(i int) ([]Segment, bool) {
+	data, err := os.ReadFile(cp.chunkResultPath(i))
+	if err != nil {
+		return nil, false
+	}
+	var segments []Segment
+	if err := json.Unmarshal(data, &segments); err != nil {
+		return nil, false
+	}
+	return segments, true
+}
+
+// save stores the segments of chunk i
+func (cp *checkpoint) save(i int, segments []Segment) error {
+	if segments == nil {
+		segments = []Segment{}
+	}
+	return writeJSONFile(cp.chunkResultPath(i), segments)
+}
+
+// writeJSONFile writes v to path through a temporary file, so that a crash
+// never leaves a half written file behind
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
+
+// pruneCheckpoints removes the checkpoints under root that were not touched
+// for maxAge
+func pruneCheckpoints(root string, maxAge time.Duration) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err == nil && entry.IsDir() && time.Since(info.ModTime()) > maxAge {
+			os.RemoveAll(filepath.Join(root, entry.Name()))
+		}
+	}
+}
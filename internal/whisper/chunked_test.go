@@ -0,0 +1,155 @@
+package whisper
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/whisper/testdata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useCheckpointDir keeps the checkpoints of the test in a temp directory
+func useCheckpointDir(t *testing.T) string {
+	dir := t.TempDir()
+	root := checkpointRoot
+	checkpointRoot = func() string { return dir }
+	t.Cleanup(func() { checkpointRoot = root })
+	return dir
+}
+
+// noisyWAV returns a 16 kHz mono WAV of length seconds with loud noise
+// except for the given silent stretches
+func noisyWAV(length time.Duration, silences ...chunkSpan) []byte {
+	samples := int(length.Seconds() * 16000)
+	data := wavBytes(samples*2, uint32(samples*2))
+	pcm := data[len(data)-samples*2:]
+	seed := uint32(1)
+	for i := 0; i < samples; i++ {
+		at := time.Duration(i) * time.Second / 16000
+		silent := false
+		for _, s := range silences {
+			if at >= s.Start && at < s.End {
+				silent = true
+			}
+		}
+		if !silent {
+			seed = seed*1664525 + 1013904223
+			binary.LittleEndian.PutUint16(pcm[i*2:], uint16(int16(seed>>16)/8))
+		}
+	}
+	return data
+}
+
+func TestPlanChunks_CutsAtSilence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "talk.wav")
+	require.NoError(t, os.WriteFile(path, noisyWAV(25*time.Second,
+		chunkSpan{Start: 8 * time.Second, End: 8600 * time.Millisecond},
+		chunkSpan{Start: 17 * time.Second, End: 17600 * time.Millisecond},
+	), 0644))
+
+	source, err := openPCMSource(path)
+	require.NoError(t, err)
+	assert.Equal(t, 25*time.Second, source.duration())
+
+	chunks, err := planChunks(source.duration(), 10*time.Second, source.quietest)
+	require.NoError(t, err)
+	require.Len(t, chunks, 3)
+	assert.Equal(t, time.Duration(0), chunks[0].Start)
+	assert.True(t, chunks[0].End >= 8250*time.Millisecond && chunks[0].End <= 8350*time.Millisecond, "cut inside the first pause: %s", chunks[0].End)
+	assert.Equal(t, chunks[0].End, chunks[1].Start)
+	assert.True(t, chunks[1].End >= 17250*time.Millisecond && chunks[1].End <= 17350*time.Millisecond, "cut inside the second pause: %s", chunks[1].End)
+	assert.Equal(t, 25*time.Second, chunks[2].End)
+
+	// A chunk is a valid WAV file with the samples of its span
+	chunkFile := filepath.Join(t.TempDir(), "chunk.wav")
+	require.NoError(t, source.writeChunk(chunks[1], chunkFile))
+	d, err := AudioDuration(chunkFile)
+	require.NoError(t, err)
+	assert.InDelta(t, (chunks[1].End - chunks[1].Start).Seconds(), d.Seconds(), 0.001)
+}
+
+func TestPlanChunks_ShortRemainder(t *testing.T) {
+	quietest := func(from, to time.Duration) (time.Duration, error) { return to, nil }
+
+	chunks, err := planChunks(65*time.Minute, 30*time.Minute, quietest)
+	require.NoError(t, err)
+	assert.Equal(t, []chunkSpan{{0, 30 * time.Minute}, {30 * time.Minute, 65 * time.Minute}}, chunks,
+		"a remainder of up to a quarter of the length is added to the last chunk")
+
+	chunks, err = planChunks(20*time.Minute, 30*time.Minute, quietest)
+	require.NoError(t, err)
+	assert.Equal(t, []chunkSpan{{0, 20 * time.Minute}}, chunks)
+}
+
+func TestChunkedTranscriber_Resume(t *testing.T) {
+	root := useCheckpointDir(t)
+	cfg := testdata.CreateTestConfig(t)
+	cfg.TranscriptionBackend = config.BackendMock
+	cfg.ChunkMinutes = 1
+	cfg.OutputFormat = "srt"
+	logger, logBuffer, logMutex := testdata.CreateTestLogger()
+
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	input := filepath.Join(cfg.InputDir, "all-hands.wav")
+	require.NoError(t, os.WriteFile(input, wavBytes(150*32000, 150*32000), 0644)) // 150 seconds of silence
+
+	// An earlier run finished the first chunk before it was interrupted
+	cp, err := openCheckpoint(context.Background(), cfg, input, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, cp.manifest.Chunks, 3)
+	assert.Equal(t, 59750*time.Millisecond, cp.manifest.Chunks[0].End)
+	require.NoError(t, cp.save(0, []Segment{{Start: time.Second, End: 3 * time.Second, Text: "保存済み"}}))
+
+	transcriber, err := NewTranscriber(cfg)
+	require.NoError(t, err)
+	result, err := transcriber.Transcribe(context.Background(), logger, logBuffer, logMutex, false, input)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, result.Segments)
+	assert.Equal(t, Segment{Start: time.Second, End: 3 * time.Second, Text: "保存済み"}, result.Segments[0],
+		"the finished chunk is not transcribed again")
+	assert.Equal(t, Segment{Start: 59750 * time.Millisecond, End: 64750 * time.Millisecond, Text: "テスト用の文字起こし 1"}, result.Segments[1],
+		"segments of later chunks are shifted by the chunk offset")
+	last := result.Segments[len(result.Segments)-1]
+	assert.Equal(t, 150*time.Second, last.End)
+
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "all-hands.srt"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "1\n00:00:01,000 --> 00:00:03,000\n保存済み\n\n2\n00:00:59,750 --> 00:01:04,750\n"))
+
+	var resumed bool
+	for _, entry := range *logBuffer {
+		resumed = resumed || strings.Contains(entry.Message, "Resuming all-hands.wav from part 2/3")
+	}
+	assert.True(t, resumed)
+	assert.NoDirExists(t, cp.dir, "the checkpoint is removed when the file is done")
+	entries, _ := os.ReadDir(root)
+	assert.Empty(t, entries)
+}
+
+func TestChunkedTranscriber_ShortInputNotSplit(t *testing.T) {
+	root := useCheckpointDir(t)
+	cfg := testdata.CreateTestConfig(t)
+	cfg.TranscriptionBackend = config.BackendMock
+	cfg.ChunkMinutes = 1
+	logger, logBuffer, logMutex := testdata.CreateTestLogger()
+
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	input := filepath.Join(cfg.InputDir, "memo.wav")
+	require.NoError(t, os.WriteFile(input, wavBytes(70*32000, 70*32000), 0644))
+
+	transcriber, err := NewTranscriber(cfg)
+	require.NoError(t, err)
+	result, err := transcriber.Transcribe(context.Background(), logger, logBuffer, logMutex, false, input)
+	require.NoError(t, err)
+	assert.Len(t, result.Segments, 14)
+	entries, _ := os.ReadDir(root)
+	assert.Empty(t, entries, "no checkpoint for files within 1.25 times chunk_minutes")
+}
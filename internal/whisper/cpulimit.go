@@ -0,0 +1,65 @@
+package whisper
+
+import (
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+)
+
+const (
+	// cpuSampleInterval is how often the CPU time of a running whisper process is checked
+	cpuSampleInterval = time.Second
+	// maxThrottlePause caps a single pause so the process never stalls for long
+	maxThrottlePause = 5 * time.Second
+	// lowPriorityNice is the nice value used for whisper on Unix-like systems
+	lowPriorityNice = 10
+)
+
+// cpuLimitActive reports whether max_cpu_percent restricts whisper.
+// 100 (or an out of range value) leaves whisper unrestricted.
+func cpuLimitActive(c *config.Config) bool {
+	return c.MaxCpuPercent > 0 && c.MaxCpuPercent < 100
+}
+
+// jobCPUPercent returns the share of max_cpu_percent available to one
+// transcription. Parallel jobs split the budget evenly.
+func jobCPUPercent(c *config.Config) int {
+	jobs := c.MaxConcurrentJobs
+	if jobs < 1 {
+		jobs = 1
+	}
+	percent := c.MaxCpuPercent / jobs
+	if percent < 1 {
+		return 1
+	}
+	return percent
+}
+
+// threadCount returns the --threads value for one transcription so that all
+// parallel jobs together use about max_cpu_percent of numCPU cores
+func threadCount(c *config.Config, numCPU int) int {
+	threads := numCPU * jobCPUPercent(c) / 100
+	if threads < 1 {
+		return 1
+	}
+	return threads
+}
+
+// throttlePause returns how long to pause a process that used cpuUsed of CPU
+// time during wall, so that its average usage including the pause stays at
+// maxPercent of numCPU cores. Zero means the process is within its budget.
+func throttlePause(cpuUsed, wall time.Duration, numCPU, maxPercent int) time.Duration {
+	if numCPU < 1 || maxPercent <= 0 {
+		return 0
+	}
+	// Time the whole machine would need at maxPercent to do the same work
+	budget := cpuUsed * 100 / time.Duration(numCPU*maxPercent)
+	pause := budget - wall
+	if pause <= 0 {
+		return 0
+	}
+	if pause > maxThrottlePause {
+		return maxThrottlePause
+	}
+	return pause
+}
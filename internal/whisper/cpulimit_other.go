@@ -0,0 +1,146 @@
+//go:build !windows
+// +build !windows
+
+package whisper
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// lowerPriority raises the nice value of the process group led by cmd so
+// recording and the UI stay responsive while whisper runs
+func lowerPriority(cmd *exec.Cmd) error {
+	return syscall.Setpriority(syscall.PRIO_PGRP, cmd.Process.Pid, lowPriorityNice)
+}
+
+// startSuspended does nothing: signals reach the whole process group,
+// including children started before limitCPU
+func startSuspended(cmd *exec.Cmd) {}
+
+// resumeStarted does nothing (see startSuspended)
+func resumeStarted(cmd *exec.Cmd) error {
+	return nil
+}
+
+// limitCPU keeps the process group led by cmd at about maxPercent of all
+// cores by stopping it (SIGSTOP) whenever it used more than its share.
+// The returned function ends the limiter and makes sure the group runs again.
+// The time the group is stopped is added to clock.
+func limitCPU(cmd *exec.Cmd, maxPercent, numCPU int, clock *SuspendClock) (func(), error) {
+	pgid := cmd.Process.Pid
+	if _, err := processGroupCPUTime(pgid); err != nil {
+		return nil, fmt.Errorf("cannot sample CPU usage: %w", err)
+	}
+
+	stop := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		throttleLoop(pgid, maxPercent, numCPU, cpuSampleInterval, stop, clock)
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-finished
+		})
+	}, nil
+}
+
+// throttleLoop samples the CPU time of the process group every interval and
+// pauses it for throttlePause when it is over budget, adding the pauses to clock. It returns when stop
+// is closed or the group is gone, leaving the group running unless SetPaused
+// suspended it in the meantime.
+func throttleLoop(pgid, maxPercent, numCPU int, interval time.Duration, stop <-chan struct{}, clock *SuspendClock) {
+	lastCPU, err := processGroupCPUTime(pgid)
+	if err != nil {
+		return
+	}
+	lastTime := time.Now()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		cpu, err := processGroupCPUTime(pgid)
+		if err != nil {
+			return
+		}
+		now := time.Now()
+		pause := throttlePause(cpu-lastCPU, now.Sub(lastTime), numCPU, maxPercent)
+		// The pause counts towards the next sample, so the average includes it
+		lastCPU, lastTime = cpu, now
+		if pause == 0 {
+			continue
+		}
+
+		if err := syscall.Kill(-pgid, syscall.SIGSTOP); err != nil {
+			return
+		}
+		stoppedAt := time.Now()
+		// While paused, SetPaused(false) resumes the group and the time
+		// counts as paused instead
+		resume := func() error {
+			clock.add(time.Since(stoppedAt))
+			return syscall.Kill(-pgid, syscall.SIGCONT)
+		}
+		select {
+		case <-stop:
+			resumeUnlessPaused(resume)
+			return
+		case <-time.After(pause):
+		}
+		if err := resumeUnlessPaused(resume); err != nil {
+			return
+		}
+	}
+}
+
+// parsePSTime parses the cumulative CPU time printed by ps -o time
+// ([[dd-]hh:]mm:ss[.ss])
+func parsePSTime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	var days int
+	if i := strings.Index(s, "-"); i >= 0 {
+		d, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid cpu time %q", s)
+		}
+		days = d
+		s = s[i+1:]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid cpu time %q", s)
+	}
+	seconds, err := strconv.ParseFloat(parts[len(parts)-1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cpu time %q", s)
+	}
+	total := seconds
+	unit := 60.0
+	for i := len(parts) - 2; i >= 0; i-- {
+		v, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid cpu time %q", s)
+		}
+		total += float64(v) * unit
+		unit *= 60
+	}
+	total += float64(days) * 24 * 3600
+	return time.Duration(total * float64(time.Second)), nil
+}
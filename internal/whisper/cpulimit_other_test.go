@@ -0,0 +1,84 @@
+//go:build !windows
+// +build !windows
+
+package whisper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePSTime(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"0:01.50", 1500 * time.Millisecond},
+		{"12:34.00", 12*time.Minute + 34*time.Second},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{"2-01:00:00", 49 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parsePSTime(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	_, err := parsePSTime("soon")
+	assert.Error(t, err)
+}
+
+func TestThrottleLoop_KeepsBusyProcessNearCap(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := createCommandContext(ctx, "sh", "-c", "while :; do :; done")
+	require.NoError(t, cmd.Start())
+	defer func() {
+		cancel()
+		cmd.Wait()
+	}()
+	pgid := cmd.Process.Pid
+
+	// Treat the machine as a single core so the busy loop alone is at 100%
+	stop := make(chan struct{})
+	finished := make(chan struct{})
+	clock := &SuspendClock{}
+	go func() {
+		defer close(finished)
+		throttleLoop(pgid, 25, 1, 100*time.Millisecond, stop, clock)
+	}()
+
+	time.Sleep(500 * time.Millisecond) // let the loop settle
+	start, err := processGroupCPUTime(pgid)
+	require.NoError(t, err)
+	time.Sleep(2 * time.Second)
+	end, err := processGroupCPUTime(pgid)
+	require.NoError(t, err)
+
+	close(stop)
+	<-finished
+
+	usage := float64(end-start) / float64(2*time.Second) * 100
+	assert.Less(t, usage, 60.0, "the busy loop must be paused most of the time")
+	assert.Greater(t, clock.Total(), time.Second, "the pauses are recorded as suspended time")
+}
+
+func TestLowerPriority(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cmd := createCommandContext(ctx, "sleep", "30")
+	require.NoError(t, cmd.Start())
+	defer func() {
+		cancel()
+		cmd.Wait()
+	}()
+
+	assert.NoError(t, lowerPriority(cmd))
+}
@@ -0,0 +1,59 @@
+package whisper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCPULimitActive(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+
+	cfg.MaxCpuPercent = 95
+	assert.True(t, cpuLimitActive(cfg))
+	cfg.MaxCpuPercent = 100
+	assert.False(t, cpuLimitActive(cfg))
+	cfg.MaxCpuPercent = 0
+	assert.False(t, cpuLimitActive(cfg))
+}
+
+func TestThreadCount(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxPercent int
+		jobs       int
+		numCPU     int
+		expected   int
+	}{
+		{"Most cores", 95, 1, 8, 7},
+		{"Half", 50, 1, 8, 4},
+		{"Split between jobs", 50, 2, 8, 2},
+		{"At least one thread", 10, 1, 4, 1},
+		{"At least one thread per job", 50, 4, 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.GetDefaultConfig()
+			cfg.MaxCpuPercent = tt.maxPercent
+			cfg.MaxConcurrentJobs = tt.jobs
+			assert.Equal(t, tt.expected, threadCount(cfg, tt.numCPU))
+		})
+	}
+}
+
+func TestThrottlePause(t *testing.T) {
+	// Within budget: 1 of 4 cores for a second is 25%
+	assert.Equal(t, time.Duration(0), throttlePause(time.Second, time.Second, 4, 50))
+
+	// 4 cores busy for a second at a 50% cap needs another second of rest
+	assert.Equal(t, time.Second, throttlePause(4*time.Second, time.Second, 4, 50))
+
+	// Long pauses are capped
+	assert.Equal(t, maxThrottlePause, throttlePause(8*time.Second, time.Second, 1, 10))
+
+	// Invalid input never pauses
+	assert.Equal(t, time.Duration(0), throttlePause(time.Second, time.Second, 0, 50))
+}
@@ -0,0 +1,116 @@
+//go:build windows
+// +build windows
+
+package whisper
+
+import (
+	"fmt"
+	"os/exec"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modkernel32                  = syscall.NewLazyDLL("kernel32.dll")
+	procSetPriorityClass         = modkernel32.NewProc("SetPriorityClass")
+	procCreateJobObjectW         = modkernel32.NewProc("CreateJobObjectW")
+	procSetInformationJobObject  = modkernel32.NewProc("SetInformationJobObject")
+	procAssignProcessToJobObject = modkernel32.NewProc("AssignProcessToJobObject")
+)
+
+const (
+	belowNormalPriorityClass = 0x00004000
+	createSuspended          = 0x00000004
+
+	processTerminate      = 0x0001
+	processSetQuota       = 0x0100
+	processSetInformation = 0x0200
+
+	jobObjectCPURateControlInformationClass = 15
+	jobObjectCPURateControlEnable           = 0x1
+	jobObjectCPURateControlHardCap          = 0x4
+)
+
+// jobObjectCPURateControlInformation is JOBOBJECT_CPU_RATE_CONTROL_INFORMATION
+type jobObjectCPURateControlInformation struct {
+	ControlFlags uint32
+	CPURate      uint32 // 1/100 percent of all processors
+}
+
+// lowerPriority puts the process started by cmd into the below normal
+// priority class. Python processes it starts afterwards inherit the class.
+func lowerPriority(cmd *exec.Cmd) error {
+	handle, err := syscall.OpenProcess(processSetInformation, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		return err
+	}
+	defer syscall.CloseHandle(handle)
+
+	if r, _, err := procSetPriorityClass.Call(uintptr(handle), belowNormalPriorityClass); r == 0 {
+		return fmt.Errorf("SetPriorityClass failed: %w", err)
+	}
+	return nil
+}
+
+// startSuspended makes cmd start with its main thread suspended. Only
+// processes started after the assignment inherit a job object, so the
+// whisper launcher must not run before limitCPU; resumeStarted lets it go.
+func startSuspended(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= createSuspended
+}
+
+// resumeStarted resumes a process started by startSuspended
+func resumeStarted(cmd *exec.Cmd) error {
+	handle, err := syscall.OpenProcess(processSuspendResume, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		return err
+	}
+	defer syscall.CloseHandle(handle)
+
+	if r, _, _ := procNtResumeProcess.Call(uintptr(handle)); r != 0 {
+		return fmt.Errorf("NtResumeProcess failed: 0x%x", r)
+	}
+	return nil
+}
+
+// limitCPU puts the process started by cmd into a job object with a hard CPU
+// rate cap of maxPercent, so Windows throttles it and the processes it starts.
+// cmd is started with startSuspended so that no child escapes the job.
+// The returned function releases the job object. The cap only slows the
+// process down, so no suspended time is recorded.
+func limitCPU(cmd *exec.Cmd, maxPercent, _ int, _ *SuspendClock) (func(), error) {
+	job, _, err := procCreateJobObjectW.Call(0, 0)
+	if job == 0 {
+		return nil, fmt.Errorf("CreateJobObject failed: %w", err)
+	}
+	release := func() { syscall.CloseHandle(syscall.Handle(job)) }
+
+	info := jobObjectCPURateControlInformation{
+		ControlFlags: jobObjectCPURateControlEnable | jobObjectCPURateControlHardCap,
+		CPURate:      uint32(maxPercent * 100),
+	}
+	if r, _, err := procSetInformationJobObject.Call(job, jobObjectCPURateControlInformationClass,
+		uintptr(unsafe.Pointer(&info)), unsafe.Sizeof(info)); r == 0 {
+		release()
+		return nil, fmt.Errorf("SetInformationJobObject failed: %w", err)
+	}
+
+	handle, err := syscall.OpenProcess(processSetQuota|processTerminate, false, uint32(cmd.Process.Pid))
+	if err != nil {
+		release()
+		return nil, err
+	}
+	defer syscall.CloseHandle(handle)
+
+	if r, _, err := procAssignProcessToJobObject.Call(job, uintptr(handle)); r == 0 {
+		release()
+		return nil, fmt.Errorf("AssignProcessToJobObject failed: %w", err)
+	}
+
+	var once sync.Once
+	return func() { once.Do(release) }, nil
+}
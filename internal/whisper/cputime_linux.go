@@ -0,0 +1,56 @@
+//go:build linux
+// +build linux
+
+package whisper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is USER_HZ, the unit of the times in /proc/<pid>/stat
+const clockTicks = 100
+
+// processGroupCPUTime returns the CPU time (user + system) used so far by
+// all processes in the process group pgid
+func processGroupCPUTime(pgid int) (time.Duration, error) {
+	statFiles, err := filepath.Glob("/proc/[0-9]*/stat")
+	if err != nil {
+		return 0, err
+	}
+
+	var ticks uint64
+	found := false
+	for _, statFile := range statFiles {
+		data, err := os.ReadFile(statFile)
+		if err != nil {
+			continue // the process exited in the meantime
+		}
+		// The command name may contain spaces; the fields start after the last ')'
+		end := strings.LastIndexByte(string(data), ')')
+		if end < 0 {
+			continue
+		}
+		fields := strings.Fields(string(data[end+1:]))
+		// fields[0] is the state (field 3), so pgrp is fields[2], utime fields[11], stime fields[12]
+		if len(fields) < 13 || fields[2] != strconv.Itoa(pgid) {
+			continue
+		}
+		utime, err1 := strconv.ParseUint(fields[11], 10, 64)
+		stime, err2 := strconv.ParseUint(fields[12], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		ticks += utime + stime
+		found = true
+	}
+
+	if !found {
+		return 0, fmt.Errorf("process group %d not found", pgid)
+	}
+	return time.Duration(ticks) * time.Second / clockTicks, nil
+}
@@ -0,0 +1,41 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package whisper
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// processGroupCPUTime returns the CPU time (user + system) used so far by
+// all processes in the process group pgid. macOS has no /proc, so ps is used.
+func processGroupCPUTime(pgid int) (time.Duration, error) {
+	out, err := exec.Command("ps", "-A", "-o", "pgid=,time=").Output()
+	if err != nil {
+		return 0, err
+	}
+
+	var total time.Duration
+	found := false
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != strconv.Itoa(pgid) {
+			continue
+		}
+		cpu, err := parsePSTime(fields[1])
+		if err != nil {
+			continue
+		}
+		total += cpu
+		found = true
+	}
+
+	if !found {
+		return 0, fmt.Errorf("process group %d not found", pgid)
+	}
+	return total, nil
+}
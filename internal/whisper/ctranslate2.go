@@ -0,0 +1,49 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+)
+
+// ctranslate2Backend runs faster-whisper through the whisper-ctranslate2
+// command line tool (transcription_backend "whisper-ctranslate2")
+type ctranslate2Backend struct {
+	config *config.Config
+}
+
+func (b *ctranslate2Backend) Name() string {
+	return config.BackendWhisperCTranslate2
+}
+
+func (b *ctranslate2Backend) Capabilities() Capabilities {
+	return Capabilities{OutputFormats: whisperFormats, Progress: true, Local: true}
+}
+
+func (b *ctranslate2Backend) Available() error {
+	if !isFasterWhisperAvailable() {
+		return errors.New("whisper-ctranslate2 not found")
+	}
+	return nil
+}
+
+func (b *ctranslate2Backend) Transcribe(ctx context.Context, log *log.Logger, logBuffer *[]logger.LogEntry,
+	logMutex *sync.RWMutex, debugMode bool, inputFile string) (*Result, error) {
+	return transcribeCLI(ctx, b.config, log, logBuffer, logMutex, debugMode, inputFile)
+}
+
+// Install installs faster-whisper and whisper-ctranslate2 with pip
+func (b *ctranslate2Backend) Install(log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex) error {
+	logger.LogInfo(log, logBuffer, logMutex, "FasterWhisper not found. Attempting to install...")
+	if err := installFasterWhisper(log, logBuffer, logMutex); err != nil {
+		logger.LogError(log, logBuffer, logMutex, "FasterWhisper automatic installation failed: %v", err)
+		logger.LogError(log, logBuffer, logMutex, "Please install Python 3.12 and restart, or manually run: pip install faster-whisper whisper-ctranslate2")
+		return fmt.Errorf("FasterWhisper installation failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package whisper
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ffprobeTimeout bounds reading the duration of one file with ffprobe
+const ffprobeTimeout = 10 * time.Second
+
+// AudioDuration returns the playing time of an audio file. WAV files (the
+// format of our own recordings) are read directly, other formats need
+// ffprobe on the PATH.
+func AudioDuration(path string) (time.Duration, error) {
+	if strings.EqualFold(filepath.Ext(path), ".wav") {
+		file, err := os.Open(path)
+		if err != nil {
+			return 0, err
+		}
+		defer file.Close()
+		if d, err := wavDuration(file); err == nil {
+			return d, nil
+		}
+		// Unusual WAV variants are left to ffprobe
+	}
+	return ffprobeDuration(path)
+}
+
+// wavDuration reads the fmt and data chunks of a RIFF/WAVE file
+func wavDuration(r io.ReadSeeker) (time.Duration, error) {
+	info, err := readWAVInfo(r)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(float64(info.dataSize) / float64(info.byteRate) * float64(time.Second)), nil
+}
+
+// wavInfo is what we need from the header of a RIFF/WAVE file
+type wavInfo struct {
+	format     uint16 // 1 = PCM, 0xFFFE = WAVE_FORMAT_EXTENSIBLE
+	channels   uint16
+	sampleRate uint32
+	byteRate   uint32
+	blockAlign uint16
+	bitDepth   uint16
+	dataOffset int64 // Position of the first sample
+	dataSize   int64
+}
+
+// readWAVInfo reads the fmt chunk and the size of the data chunk
+func readWAVInfo(r io.ReadSeeker) (wavInfo, error) {
+	var info wavInfo
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return info, err
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return info, errors.New("not a RIFF/WAVE file")
+	}
+
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return info, fmt.Errorf("no data chunk: %w", err)
+		}
+		id := string(chunk[0:4])
+		size := binary.LittleEndian.Uint32(chunk[4:8])
+
+		switch id {
+		case "fmt ":
+			var format [16]byte
+			if size < 16 {
+				return info, errors.New("short fmt chunk")
+			}
+			if _, err := io.ReadFull(r, format[:]); err != nil {
+				return info, err
+			}
+			info.format = binary.LittleEndian.Uint16(format[0:2])
+			info.channels = binary.LittleEndian.Uint16(format[2:4])
+			info.sampleRate = binary.LittleEndian.Uint32(format[4:8])
+			info.byteRate = binary.LittleEndian.Uint32(format[8:12])
+			info.blockAlign = binary.LittleEndian.Uint16(format[12:14])
+			info.bitDepth = binary.LittleEndian.Uint16(format[14:16])
+			if _, err := r.Seek(int64(size-16+size%2), io.SeekCurrent); err != nil {
+				return info, err
+			}
+		case "data":
+			if info.byteRate == 0 {
+				return info, errors.New("data chunk before fmt chunk")
+			}
+			pos, err := r.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return info, err
+			}
+			info.dataOffset = pos
+			info.dataSize = int64(size)
+			if size == 0xFFFFFFFF || size == 0 {
+				// Written while streaming: the data runs to the end of the file
+				end, err := r.Seek(0, io.SeekEnd)
+				if err != nil {
+					return info, err
+				}
+				info.dataSize = end - pos
+			}
+			return info, nil
+		default:
+			if _, err := r.Seek(int64(size+size%2), io.SeekCurrent); err != nil {
+				return info, err
+			}
+		}
+	}
+}
+
+// ffprobeDuration asks ffprobe for the container duration
+func ffprobeDuration(path string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ffprobeTimeout)
+	defer cancel()
+
+	cmd := createCommandContext(ctx, "ffprobe", "-v", "error",
+		"-show_entries", "format=duration", "-of", "default=noprint_wrappers=1:nokey=1", path)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("ffprobe failed: %w", err)
+	}
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected ffprobe output %q", strings.TrimSpace(string(output)))
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,61 @@
+package whisper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wavBytes builds a 16 kHz mono 16-bit WAV file with dataBytes of silence.
+// dataSize is written to the data chunk header as is.
+func wavBytes(dataBytes int, dataSize uint32) []byte {
+	var buf bytes.Buffer
+	le := func(v interface{}) { binary.Write(&buf, binary.LittleEndian, v) }
+
+	buf.WriteString("RIFF")
+	le(uint32(36 + dataBytes + 12))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	le(uint32(16))
+	le(uint16(1))     // PCM
+	le(uint16(1))     // Channels
+	le(uint32(16000)) // Sample rate
+	le(uint32(32000)) // Byte rate
+	le(uint16(2))     // Block align
+	le(uint16(16))    // Bits per sample
+	buf.WriteString("LIST")
+	le(uint32(3)) // Odd sized chunks are padded
+	buf.Write([]byte{'a', 'b', 'c', 0})
+	buf.WriteString("data")
+	le(dataSize)
+	buf.Write(make([]byte, dataBytes))
+	return buf.Bytes()
+}
+
+func TestWavDuration(t *testing.T) {
+	d, err := wavDuration(bytes.NewReader(wavBytes(64000, 64000)))
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, d)
+
+	d, err = wavDuration(bytes.NewReader(wavBytes(48000, 0xFFFFFFFF)))
+	require.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, d, "streamed files run to the end")
+
+	_, err = wavDuration(bytes.NewReader([]byte("ID3 not a wave file")))
+	assert.Error(t, err)
+}
+
+func TestAudioDuration_WAV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.WAV")
+	require.NoError(t, os.WriteFile(path, wavBytes(32000, 32000), 0644))
+
+	d, err := AudioDuration(path)
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, d)
+}
@@ -0,0 +1,69 @@
+package whisper
+
+import "errors"
+
+// ErrorKind classifies a transcription failure so the processor can decide
+// whether retrying makes sense
+type ErrorKind int
+
+const (
+	// ErrorKindTransient covers crashes and other failures that may succeed on retry
+	ErrorKindTransient ErrorKind = iota
+	// ErrorKindGPU is a GPU/CUDA or memory failure (see isGPURelatedError).
+	// The same settings will fail again, so it is not retried.
+	ErrorKindGPU
+	// ErrorKindOutput means whisper finished but produced no usable output
+	// (see validateOutputFile), usually a corrupt or silent file
+	ErrorKindOutput
+	// ErrorKindSetup is a problem with the environment or the input path
+	// (whisper not installed, file outside input dir, output not writable).
+	// The file itself is fine.
+	ErrorKindSetup
+)
+
+// String returns a short identifier used in logs and the job store
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorKindGPU:
+		return "gpu"
+	case ErrorKindOutput:
+		return "output"
+	case ErrorKindSetup:
+		return "setup"
+	default:
+		return "transient"
+	}
+}
+
+// Retryable reports whether another attempt with the same settings may succeed
+func (k ErrorKind) Retryable() bool {
+	return k == ErrorKindTransient
+}
+
+// TranscribeError is returned by TranscribeAudio and carries the failure classification
+type TranscribeError struct {
+	Kind ErrorKind
+	Err  error
+}
+
+func (e *TranscribeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TranscribeError) Unwrap() error {
+	return e.Err
+}
+
+func newTranscribeError(kind ErrorKind, err error) error {
+	return &TranscribeError{Kind: kind, Err: err}
+}
+
+// ClassifyError returns the kind of a TranscribeAudio error.
+// Errors that were not classified are treated as transient.
+func ClassifyError(err error) ErrorKind {
+	var transcribeErr *TranscribeError
+	if errors.As(err, &transcribeErr) {
+		return transcribeErr.Kind
+	}
+	return ErrorKindTransient
+}
@@ -0,0 +1,66 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/whisper/testdata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		expected  ErrorKind
+		retryable bool
+	}{
+		{"Unclassified error", errors.New("exit status 1"), ErrorKindTransient, true},
+		{"GPU error", newTranscribeError(ErrorKindGPU, errors.New("CUDA failed")), ErrorKindGPU, false},
+		{"Output error", newTranscribeError(ErrorKindOutput, errors.New("empty output")), ErrorKindOutput, false},
+		{"Setup error", newTranscribeError(ErrorKindSetup, errors.New("not found")), ErrorKindSetup, false},
+		{"Wrapped error", fmt.Errorf("job: %w", newTranscribeError(ErrorKindGPU, errors.New("cudnn"))), ErrorKindGPU, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind := ClassifyError(tt.err)
+			assert.Equal(t, tt.expected, kind)
+			assert.Equal(t, tt.retryable, kind.Retryable())
+		})
+	}
+}
+
+func TestTranscribeError_KeepsMessage(t *testing.T) {
+	inner := errors.New("output file is empty")
+	err := newTranscribeError(ErrorKindOutput, inner)
+
+	assert.Equal(t, "output file is empty", err.Error())
+	assert.ErrorIs(t, err, inner)
+}
+
+func TestTranscribeAudio_SetupErrorsAreClassified(t *testing.T) {
+	config := testdata.CreateTestConfig(t)
+	logger, logBuffer, logMutex := testdata.CreateTestLogger()
+
+	// Outside the input directory: rejected before whisper is started
+	err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, false, "/tmp/test.wav")
+
+	assert.Error(t, err)
+	assert.Equal(t, ErrorKindSetup, ClassifyError(err))
+}
+
+func TestTranscribeAudio_CancelledContext(t *testing.T) {
+	config := testdata.CreateTestConfig(t)
+	logger, logBuffer, logMutex := testdata.CreateTestLogger()
+	testdata.CreateDirectories(t, config.InputDir, config.OutputDir)
+	audioFile := testdata.CreateTestAudioFile(t, config.InputDir, "test.wav")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := TranscribeAudio(ctx, config, logger, logBuffer, logMutex, false, audioFile)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -3,9 +3,45 @@
 
 package whisper
 
-import "os/exec"
+import (
+	"context"
+	"os/exec"
+	"syscall"
+)
 
 // createCommand creates a command (no special handling needed on non-Windows platforms)
 func createCommand(name string, args ...string) *exec.Cmd {
 	return exec.Command(name, args...)
 }
+
+// createCommandContext creates a command that is killed together with its
+// children when ctx is cancelled. The process runs in its own process group
+// so python workers started by whisper-ctranslate2 are killed as well.
+func createCommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return killProcessTree(cmd)
+	}
+	cmd.WaitDelay = processWaitDelay
+	return cmd
+}
+
+// suspendProcessTree stops the process group led by cmd
+func suspendProcessTree(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGSTOP)
+}
+
+// resumeProcessTree continues the process group stopped by suspendProcessTree
+func resumeProcessTree(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGCONT)
+}
+
+// killProcessTree kills the process group led by cmd
+func killProcessTree(cmd *exec.Cmd) error {
+	// 負のPIDでプロセスグループ全体にシグナルを送る
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+//go:build !windows
+// +build !windows
+
+package whisper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCommandContext_KillsProcessTree(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// The shell starts a child and waits for it, like whisper-ctranslate2 running python
+	cmd := createCommandContext(ctx, "sh", "-c", "sleep 30 & echo $! > "+pidFile+"; wait")
+	require.NoError(t, cmd.Start())
+
+	var childPid int
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(pidFile)
+		if err != nil {
+			return false
+		}
+		childPid, err = strconv.Atoi(strings.TrimSpace(string(data)))
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	waitErr := make(chan error, 1)
+	go func() { waitErr <- cmd.Wait() }()
+	select {
+	case err := <-waitErr:
+		assert.Error(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("command was not killed")
+	}
+
+	// The orphaned child is reaped by init shortly after being killed
+	assert.Eventually(t, func() bool {
+		return syscall.Kill(childPid, 0) == syscall.ESRCH
+	}, 5*time.Second, 20*time.Millisecond, "child process must be killed too")
+}
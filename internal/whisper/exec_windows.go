@@ -4,10 +4,21 @@
 package whisper
 
 import (
+	"context"
 	"os/exec"
+	"strconv"
 	"syscall"
+	"unsafe"
 )
 
+var (
+	modntdll             = syscall.NewLazyDLL("ntdll.dll")
+	procNtSuspendProcess = modntdll.NewProc("NtSuspendProcess")
+	procNtResumeProcess  = modntdll.NewProc("NtResumeProcess")
+)
+
+const processSuspendResume = 0x0800
+
 // createCommand creates a command that runs without showing a console window on Windows
 func createCommand(name string, args ...string) *exec.Cmd {
 	cmd := exec.Command(name, args...)
@@ -17,3 +28,84 @@ func createCommand(name string, args ...string) *exec.Cmd {
 	}
 	return cmd
 }
+
+// createCommandContext creates a hidden command that is killed together with
+// its children when ctx is cancelled
+func createCommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		HideWindow: true,
+		CreationFlags: 0x08000000, // CREATE_NO_WINDOW
+	}
+	cmd.Cancel = func() error {
+		return killProcessTree(cmd)
+	}
+	cmd.WaitDelay = processWaitDelay
+	return cmd
+}
+
+// suspendProcessTree suspends cmd and all of its child processes
+func suspendProcessTree(cmd *exec.Cmd) error {
+	return callProcessTree(cmd, procNtSuspendProcess)
+}
+
+// resumeProcessTree resumes the processes suspended by suspendProcessTree
+func resumeProcessTree(cmd *exec.Cmd) error {
+	return callProcessTree(cmd, procNtResumeProcess)
+}
+
+// callProcessTree calls an ntdll process function for cmd and every descendant
+func callProcessTree(cmd *exec.Cmd, proc *syscall.LazyProc) error {
+	pids, err := processTree(uint32(cmd.Process.Pid))
+	if err != nil {
+		return err
+	}
+	for _, pid := range pids {
+		handle, err := syscall.OpenProcess(processSuspendResume, false, pid)
+		if err != nil {
+			continue // the process exited in the meantime
+		}
+		proc.Call(uintptr(handle))
+		syscall.CloseHandle(handle)
+	}
+	return nil
+}
+
+// processTree returns pid followed by the ids of all of its descendants
+func processTree(pid uint32) ([]uint32, error) {
+	snapshot, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.CloseHandle(snapshot)
+
+	children := make(map[uint32][]uint32)
+	var entry syscall.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	for err = syscall.Process32First(snapshot, &entry); err == nil; err = syscall.Process32Next(snapshot, &entry) {
+		children[entry.ParentProcessID] = append(children[entry.ParentProcessID], entry.ProcessID)
+	}
+
+	tree := []uint32{pid}
+	seen := map[uint32]bool{pid: true}
+	for i := 0; i < len(tree); i++ {
+		for _, child := range children[tree[i]] {
+			if !seen[child] {
+				seen[child] = true
+				tree = append(tree, child)
+			}
+		}
+	}
+	return tree, nil
+}
+
+// killProcessTree kills cmd and all of its child processes.
+// Process.Kill only terminates the launcher (e.g. the whisper-ctranslate2.exe
+// shim), leaving the python process running, so taskkill /T is used.
+func killProcessTree(cmd *exec.Cmd) error {
+	kill := createCommand("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid))
+	if err := kill.Run(); err != nil {
+		return cmd.Process.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package whisper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+)
+
+// mockSegmentLength is the length of each segment the mock backend produces
+const mockSegmentLength = 5 * time.Second
+
+// mockBackend is a deterministic in-process backend for tests and demos
+// (transcription_backend "mock"). It needs no model: the transcript is one
+// numbered segment per mockSegmentLength of audio.
+type mockBackend struct {
+	config *config.Config
+}
+
+func (b *mockBackend) Name() string {
+	return config.BackendMock
+}
+
+func (b *mockBackend) Capabilities() Capabilities {
+	return Capabilities{OutputFormats: whisperFormats, Progress: true, Local: true}
+}
+
+func (b *mockBackend) Available() error {
+	return nil
+}
+
+func (b *mockBackend) Transcribe(ctx context.Context, log *log.Logger, logBuffer *[]logger.LogEntry,
+	logMutex *sync.RWMutex, debugMode bool, inputFile string) (*Result, error) {
+
+	outputDir, err := prepareTranscription(b.config, inputFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFormats(b.Capabilities(), b.config); err != nil {
+		return nil, err
+	}
+
+	audio, err := AudioDuration(inputFile)
+	if err != nil || audio <= 0 {
+		logger.LogDebug(log, logBuffer, logMutex, debugMode, "Mock backend: audio duration unknown, writing one segment")
+		audio = mockSegmentLength
+	}
+	stopProgress := startProgress(inputFile, audio)
+	defer stopProgress()
+
+	segments := mockSegments(audio, b.config.Language)
+	for _, s := range segments {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("transcription cancelled: %w", err)
+		}
+		advanceProgress(inputFile, s.End, s.Text)
+	}
+
+	outputFiles, err := writeOutputs(outputDir, inputFile, b.config, segments)
+	if err != nil {
+		return nil, err
+	}
+	logger.LogDebug(log, logBuffer, logMutex, debugMode, "Mock backend wrote %d segments to %s", len(segments), strings.Join(outputFiles, ", "))
+	return &Result{Segments: segments, OutputFiles: outputFiles}, nil
+}
+
+// mockSegments splits audio into numbered segments of mockSegmentLength
+func mockSegments(audio time.Duration, language string) []Segment {
+	format := "Mock transcription %d"
+	if language == "ja" {
+		format = "テスト用の文字起こし %d"
+	}
+
+	var segments []Segment
+	for start := time.Duration(0); start < audio; start += mockSegmentLength {
+		end := start + mockSegmentLength
+		if end > audio {
+			end = audio
+		}
+		segments = append(segments, Segment{Start: start, End: end, Text: fmt.Sprintf(format, len(segments)+1)})
+	}
+	return segments
+}
@@ -0,0 +1,138 @@
+package whisper
+
+import (
+	"context"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Running transcriptions can be paused as a whole, e.g. while the user is
+// recording, so whisper does not compete with audio capture for CPU time.
+var pause = struct {
+	sync.Mutex
+	paused  bool
+	running map[*exec.Cmd]*trackedProcess
+}{running: make(map[*exec.Cmd]*trackedProcess)}
+
+// trackedProcess is a running whisper process registered by trackProcess
+type trackedProcess struct {
+	clock       *SuspendClock
+	pausedSince time.Time // Set while suspended by SetPaused
+}
+
+// SuspendClock adds up how long the processes of one transcription were
+// suspended by SetPaused or the CPU throttle, so the time can be left out
+// of the real-time factor. A nil clock ignores everything.
+type SuspendClock struct {
+	mu    sync.Mutex
+	total time.Duration
+}
+
+type suspendClockKey struct{}
+
+// WithSuspendClock returns a context whose whisper processes report their
+// suspended time to the returned clock
+func WithSuspendClock(ctx context.Context) (context.Context, *SuspendClock) {
+	clock := &SuspendClock{}
+	return context.WithValue(ctx, suspendClockKey{}, clock), clock
+}
+
+// suspendClockFrom returns the clock attached by WithSuspendClock (nil when none)
+func suspendClockFrom(ctx context.Context) *SuspendClock {
+	clock, _ := ctx.Value(suspendClockKey{}).(*SuspendClock)
+	return clock
+}
+
+// Total returns the suspended time recorded so far
+func (c *SuspendClock) Total() time.Duration {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
+
+func (c *SuspendClock) add(d time.Duration) {
+	if c == nil || d <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total += d
+}
+
+// SetPaused suspends (true) or resumes (false) all running whisper processes.
+// Processes started while paused are suspended right away.
+// It reports whether the state changed.
+func SetPaused(paused bool) bool {
+	pause.Lock()
+	defer pause.Unlock()
+
+	if pause.paused == paused {
+		return false
+	}
+	pause.paused = paused
+	now := time.Now()
+	for cmd, process := range pause.running {
+		setSuspended(cmd, paused)
+		if paused {
+			process.pausedSince = now
+		} else {
+			process.clock.add(now.Sub(process.pausedSince))
+			process.pausedSince = time.Time{}
+		}
+	}
+	return true
+}
+
+// Paused reports whether transcriptions are paused by SetPaused
+func Paused() bool {
+	pause.Lock()
+	defer pause.Unlock()
+	return pause.paused
+}
+
+// resumeUnlessPaused calls resume unless transcriptions are paused by
+// SetPaused. The pause lock is held throughout, so a concurrent SetPaused(true)
+// cannot be undone by resume.
+func resumeUnlessPaused(resume func() error) error {
+	pause.Lock()
+	defer pause.Unlock()
+
+	if pause.paused {
+		return nil
+	}
+	return resume()
+}
+
+// trackProcess registers a started whisper process for SetPaused and
+// returns a function that removes it again. Time spent paused is added
+// to clock.
+func trackProcess(cmd *exec.Cmd, clock *SuspendClock) func() {
+	pause.Lock()
+	defer pause.Unlock()
+
+	process := &trackedProcess{clock: clock}
+	pause.running[cmd] = process
+	if pause.paused {
+		setSuspended(cmd, true)
+		process.pausedSince = time.Now()
+	}
+	return func() {
+		pause.Lock()
+		defer pause.Unlock()
+		if !process.pausedSince.IsZero() {
+			process.clock.add(time.Since(process.pausedSince))
+		}
+		delete(pause.running, cmd)
+	}
+}
+
+func setSuspended(cmd *exec.Cmd, suspended bool) error {
+	if suspended {
+		return suspendProcessTree(cmd)
+	}
+	return resumeProcessTree(cmd)
+}
@@ -0,0 +1,109 @@
+//go:build !windows
+// +build !windows
+
+package whisper
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startBusyProcess starts a busy loop in its own process group like whisper
+func startBusyProcess(t *testing.T) *exec.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := createCommandContext(ctx, "sh", "-c", "while :; do :; done")
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		cancel()
+		cmd.Wait()
+	})
+	return cmd
+}
+
+// cpuTimeDuring returns how much CPU time the process group used during d
+func cpuTimeDuring(t *testing.T, cmd *exec.Cmd, d time.Duration) time.Duration {
+	start, err := processGroupCPUTime(cmd.Process.Pid)
+	require.NoError(t, err)
+	time.Sleep(d)
+	end, err := processGroupCPUTime(cmd.Process.Pid)
+	require.NoError(t, err)
+	return end - start
+}
+
+func TestSetPaused_SuspendsAndResumesRunningProcess(t *testing.T) {
+	t.Cleanup(func() { SetPaused(false) })
+	cmd := startBusyProcess(t)
+	untrack := trackProcess(cmd, nil)
+	defer untrack()
+
+	assert.True(t, SetPaused(true))
+	assert.False(t, SetPaused(true), "pausing twice does not change the state")
+	assert.True(t, Paused())
+	time.Sleep(200 * time.Millisecond)
+	assert.Less(t, cpuTimeDuring(t, cmd, time.Second), 100*time.Millisecond)
+
+	assert.True(t, SetPaused(false))
+	assert.False(t, Paused())
+	assert.Greater(t, cpuTimeDuring(t, cmd, time.Second), 300*time.Millisecond)
+}
+
+func TestTrackProcess_SuspendsProcessStartedWhilePaused(t *testing.T) {
+	t.Cleanup(func() { SetPaused(false) })
+	SetPaused(true)
+
+	cmd := startBusyProcess(t)
+	untrack := trackProcess(cmd, nil)
+	time.Sleep(200 * time.Millisecond)
+	assert.Less(t, cpuTimeDuring(t, cmd, time.Second), 100*time.Millisecond)
+
+	// An untracked process is no longer resumed
+	untrack()
+	SetPaused(false)
+	assert.Less(t, cpuTimeDuring(t, cmd, 500*time.Millisecond), 100*time.Millisecond)
+	resumeProcessTree(cmd)
+}
+
+func TestResumeUnlessPaused_SkipsResumeWhilePaused(t *testing.T) {
+	t.Cleanup(func() { SetPaused(false) })
+	resumed := 0
+	resume := func() error {
+		resumed++
+		return nil
+	}
+
+	SetPaused(true)
+	assert.NoError(t, resumeUnlessPaused(resume))
+	assert.Equal(t, 0, resumed)
+
+	SetPaused(false)
+	assert.NoError(t, resumeUnlessPaused(resume))
+	assert.Equal(t, 1, resumed)
+}
+
+func TestSuspendClock_CountsPausedTime(t *testing.T) {
+	t.Cleanup(func() { SetPaused(false) })
+	ctx, clock := WithSuspendClock(context.Background())
+	require.Same(t, clock, suspendClockFrom(ctx))
+
+	cmd := startBusyProcess(t)
+	untrack := trackProcess(cmd, clock)
+	SetPaused(true)
+	time.Sleep(300 * time.Millisecond)
+	SetPaused(false)
+	assert.GreaterOrEqual(t, clock.Total(), 300*time.Millisecond)
+
+	// A process that ends while paused counts until it is untracked
+	before := clock.Total()
+	SetPaused(true)
+	time.Sleep(200 * time.Millisecond)
+	untrack()
+	assert.GreaterOrEqual(t, clock.Total()-before, 200*time.Millisecond)
+
+	var none *SuspendClock
+	assert.Zero(t, none.Total())
+}
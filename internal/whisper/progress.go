@@ -0,0 +1,154 @@
+package whisper
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress is how far whisper has got with one input file, taken from the
+// segment lines it prints with --verbose True
+type Progress struct {
+	Position time.Duration // End of the latest recognized segment
+	Duration time.Duration // Playing time of the input, 0 when unknown
+	LastText string        // Text of the latest recognized segment
+}
+
+// Percent returns the completed share of the audio (0-100). It reports false
+// while the playing time of the input is unknown.
+func (p Progress) Percent() (float64, bool) {
+	if p.Duration <= 0 {
+		return 0, false
+	}
+	percent := float64(p.Position) / float64(p.Duration) * 100
+	if percent > 100 {
+		percent = 100
+	}
+	return percent, true
+}
+
+// segmentLinePattern matches verbose segment lines such as
+// "[00:12.340 --> 00:15.880]  こんにちは" and "[01:02:03.000 --> 01:02:05.500] hello"
+var segmentLinePattern = regexp.MustCompile(`^\[((?:\d+:)?\d+:\d+(?:\.\d+)?) --> ((?:\d+:)?\d+:\d+(?:\.\d+)?)\]\s*(.*)$`)
+
+// parseSegmentLine returns the time range and text of a verbose segment line
+func parseSegmentLine(line string) (start, end time.Duration, text string, ok bool) {
+	m := segmentLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return 0, 0, "", false
+	}
+	start, errStart := parseTimestamp(m[1])
+	end, errEnd := parseTimestamp(m[2])
+	if errStart != nil || errEnd != nil {
+		return 0, 0, "", false
+	}
+	return start, end, strings.TrimSpace(m[3]), true
+}
+
+// parseTimestamp parses [HH:]MM:SS.mmm
+func parseTimestamp(s string) (time.Duration, error) {
+	fields := strings.Split(s, ":")
+	seconds, err := strconv.ParseFloat(fields[len(fields)-1], 64)
+	if err != nil {
+		return 0, err
+	}
+	total := seconds
+	unit := 60.0
+	for i := len(fields) - 2; i >= 0; i-- {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return 0, err
+		}
+		total += float64(n) * unit
+		unit *= 60
+	}
+	return time.Duration(total * float64(time.Second)), nil
+}
+
+// progress holds the Progress of the files being transcribed, keyed by the
+// input path passed to TranscribeAudio
+var progress = struct {
+	sync.Mutex
+	files map[string]Progress
+}{files: make(map[string]Progress)}
+
+// CurrentProgress returns the progress of an input file that is being
+// transcribed. It reports false before the file starts and after it finishes.
+func CurrentProgress(inputFile string) (Progress, bool) {
+	progress.Lock()
+	defer progress.Unlock()
+
+	p, ok := progress.files[inputFile]
+	return p, ok
+}
+
+// startProgress registers inputFile and returns the function that removes it
+// again when whisper has finished
+func startProgress(inputFile string, duration time.Duration) func() {
+	progress.Lock()
+	progress.files[inputFile] = Progress{Duration: duration}
+	progress.Unlock()
+
+	return func() {
+		progress.Lock()
+		delete(progress.files, inputFile)
+		progress.Unlock()
+	}
+}
+
+// updateProgress applies one line of whisper output to the progress of
+// inputFile. Lines other than segments are ignored.
+func updateProgress(inputFile, line string) {
+	if _, end, text, ok := parseSegmentLine(line); ok {
+		advanceProgress(inputFile, end, text)
+	}
+}
+
+// advanceProgress records that inputFile has been recognized up to position
+func advanceProgress(inputFile string, position time.Duration, text string) {
+	progress.Lock()
+	defer progress.Unlock()
+
+	p, running := progress.files[inputFile]
+	if !running {
+		return
+	}
+	if position > p.Position {
+		p.Position = position
+	}
+	if text != "" {
+		p.LastText = text
+	}
+	progress.files[inputFile] = p
+}
+
+// segmentRecorder collects the segment lines whisper prints on stdout and
+// stderr for the Result
+type segmentRecorder struct {
+	mu       sync.Mutex
+	segments []Segment
+}
+
+// add records line when it is a segment line
+func (r *segmentRecorder) add(line string) {
+	start, end, text, ok := parseSegmentLine(line)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	r.segments = append(r.segments, Segment{Start: start, End: end, Text: text})
+	r.mu.Unlock()
+}
+
+// list returns the recorded segments in order of their start time
+func (r *segmentRecorder) list() []Segment {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	segments := append([]Segment(nil), r.segments...)
+	sort.SliceStable(segments, func(i, j int) bool { return segments[i].Start < segments[j].Start })
+	return segments
+}
@@ -0,0 +1,119 @@
+//go:build !windows
+// +build !windows
+
+package whisper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockWhisperScript prints verbose segment lines like whisper-ctranslate2,
+// waits for $MOCK_WHISPER_CONTINUE to exist and then writes the JSON output
+const mockWhisperScript = `#!/bin/sh
+while [ $# -gt 1 ]; do
+	case "$1" in
+	--output_dir) outdir="$2"; shift ;;
+	esac
+	shift
+done
+name=$(basename "$1")
+echo "Detected language 'Japanese' with probability 0.99"
+echo "[00:00.000 --> 00:01.500]  こんにちは"
+echo "[00:01.500 --> 00:03.000]  今日は晴れです"
+i=0
+while [ ! -e "$MOCK_WHISPER_CONTINUE" ] && [ $i -lt 200 ]; do
+	sleep 0.05
+	i=$((i + 1))
+done
+cat > "$outdir/${name%.*}.json" <<EOF
+{"text": " こんにちは 今日は晴れです", "segments": [
+{"id": 0, "seek": 0, "start": 0.0, "end": 1.5, "text": " こんにちは", "avg_logprob": -0.21, "no_speech_prob": 0.01},
+{"id": 1, "seek": 0, "start": 1.5, "end": 3.0, "text": " 今日は晴れです", "avg_logprob": -0.35, "no_speech_prob": 0.02}
+], "language": "ja"}
+EOF
+`
+
+func TestTranscribeAudio_ReportsProgress(t *testing.T) {
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	require.NoError(t, os.MkdirAll(binDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "whisper-ctranslate2"), []byte(mockWhisperScript), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	continueFile := filepath.Join(dir, "continue")
+	t.Setenv("MOCK_WHISPER_CONTINUE", continueFile)
+
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(dir, "input")
+	cfg.OutputDir = filepath.Join(dir, "output")
+	cfg.MaxCpuPercent = 100
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	input := filepath.Join(cfg.InputDir, "memo.wav")
+	require.NoError(t, os.WriteFile(input, wavBytes(4*32000, 4*32000), 0644))
+
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	result := make(chan error, 1)
+	go func() {
+		result <- TranscribeAudio(context.Background(), cfg, nil, &logBuffer, &logMutex, false, input)
+	}()
+
+	require.Eventually(t, func() bool {
+		p, ok := CurrentProgress(input)
+		return ok && p.LastText == "今日は晴れです"
+	}, 5*time.Second, 20*time.Millisecond)
+	p, _ := CurrentProgress(input)
+	percent, known := p.Percent()
+	assert.True(t, known)
+	assert.Equal(t, 75.0, percent, "3s of 4s audio")
+
+	require.NoError(t, os.WriteFile(continueFile, nil, 0644))
+	require.NoError(t, <-result)
+	_, ok := CurrentProgress(input)
+	assert.False(t, ok)
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "memo.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "こんにちは\n今日は晴れです\n", string(data), "rendered from the JSON output")
+}
+
+// mockWhisperGPUFailureScript fails like whisper-ctranslate2 when the GPU runs out of memory
+const mockWhisperGPUFailureScript = `#!/bin/sh
+echo "Detected language 'Japanese' with probability 0.99"
+echo "Traceback (most recent call last):" >&2
+echo "RuntimeError: CUDA failed with error out of memory" >&2
+echo "CUDA out of memory" >&2
+exit 1
+`
+
+func TestTranscribeAudio_GPUFailureFromStderr(t *testing.T) {
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	require.NoError(t, os.MkdirAll(binDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "whisper-ctranslate2"), []byte(mockWhisperGPUFailureScript), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := config.GetDefaultConfig()
+	cfg.InputDir = filepath.Join(dir, "input")
+	cfg.OutputDir = filepath.Join(dir, "output")
+	cfg.MaxCpuPercent = 100
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	input := filepath.Join(cfg.InputDir, "memo.wav")
+	require.NoError(t, os.WriteFile(input, wavBytes(4*32000, 4*32000), 0644))
+
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	err := TranscribeAudio(context.Background(), cfg, nil, &logBuffer, &logMutex, false, input)
+
+	require.Error(t, err)
+	assert.Equal(t, ErrorKindGPU, ClassifyError(err))
+	assert.False(t, ClassifyError(err).Retryable())
+}
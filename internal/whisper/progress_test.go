@@ -0,0 +1,63 @@
+package whisper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSegmentLine(t *testing.T) {
+	start, end, text, ok := parseSegmentLine("[00:12.340 --> 00:15.880]  今日の会議を始めます")
+	assert.True(t, ok)
+	assert.Equal(t, 12340*time.Millisecond, start)
+	assert.Equal(t, 15880*time.Millisecond, end)
+	assert.Equal(t, "今日の会議を始めます", text)
+
+	start, end, _, ok = parseSegmentLine("[01:02:03.500 --> 01:02:05.000] hello")
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour+2*time.Minute+3500*time.Millisecond, start)
+	assert.Equal(t, time.Hour+2*time.Minute+5*time.Second, end)
+
+	for _, line := range []string{
+		"Detected language 'Japanese' with probability 0.99",
+		"[00:12.340 -> 00:15.880] missing arrow",
+		"Transcription results written to '/output'",
+	} {
+		_, _, _, ok := parseSegmentLine(line)
+		assert.False(t, ok, line)
+	}
+}
+
+func TestUpdateProgress(t *testing.T) {
+	const file = "/input/meeting.wav"
+	stop := startProgress(file, 20*time.Second)
+
+	updateProgress(file, "[00:00.000 --> 00:05.000]  はじめに")
+	updateProgress(file, "Some other output")
+	updateProgress(file, "[00:05.000 --> 00:10.000]  本題です")
+
+	p, ok := CurrentProgress(file)
+	assert.True(t, ok)
+	percent, known := p.Percent()
+	assert.True(t, known)
+	assert.Equal(t, 50.0, percent)
+	assert.Equal(t, "本題です", p.LastText)
+
+	stop()
+	_, ok = CurrentProgress(file)
+	assert.False(t, ok, "finished files are removed")
+
+	updateProgress(file, "[00:10.000 --> 00:15.000]  late output")
+	_, ok = CurrentProgress(file)
+	assert.False(t, ok, "output after the end is ignored")
+}
+
+func TestProgress_PercentUnknownWithoutDuration(t *testing.T) {
+	_, known := Progress{Position: time.Minute}.Percent()
+	assert.False(t, known)
+
+	percent, known := Progress{Position: 2 * time.Minute, Duration: time.Minute}.Percent()
+	assert.True(t, known)
+	assert.Equal(t, 100.0, percent, "capped at 100")
+}
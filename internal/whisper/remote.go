@@ -0,0 +1,175 @@
+package whisper
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+)
+
+// wavBytesPerSecond is the size of one second of the 16 kHz mono 16-bit WAV
+// that large files are split into
+const wavBytesPerSecond = 16000 * 2
+
+// remoteBackend sends the audio to an OpenAI-compatible
+// /v1/audio/transcriptions endpoint (transcription_backend "openai"): OpenAI
+// itself or a faster-whisper-server / LocalAI on the LAN. Files larger than
+// remote_max_upload_mb are split with ffmpeg and uploaded part by part.
+type remoteBackend struct {
+	config *config.Config
+}
+
+func (b *remoteBackend) Name() string {
+	return config.BackendOpenAI
+}
+
+func (b *remoteBackend) Capabilities() Capabilities {
+	return Capabilities{OutputFormats: whisperFormats, Progress: true, Local: false}
+}
+
+func (b *remoteBackend) Available() error {
+	u, err := url.Parse(b.config.RemoteBaseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("remote_base_url must be an http(s) URL such as https://api.openai.com/v1: %q", b.config.RemoteBaseURL)
+	}
+	if b.config.RemoteModel == "" {
+		return fmt.Errorf("remote_model is not configured")
+	}
+	return nil
+}
+
+func (b *remoteBackend) Transcribe(ctx context.Context, log *log.Logger, logBuffer *[]logger.LogEntry,
+	logMutex *sync.RWMutex, debugMode bool, inputFile string) (*Result, error) {
+
+	outputDir, err := prepareTranscription(b.config, inputFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFormats(b.Capabilities(), b.config); err != nil {
+		return nil, err
+	}
+	if err := b.Available(); err != nil {
+		return nil, newTranscribeError(ErrorKindSetup, err)
+	}
+
+	audio, err := AudioDuration(inputFile)
+	if err != nil {
+		logger.LogDebug(log, logBuffer, logMutex, debugMode, "Audio duration unknown, progress shows text only: %v", err)
+	}
+	stopProgress := startProgress(inputFile, audio)
+	defer stopProgress()
+
+	chunks, err := uploadChunks(b.config, inputFile, audio)
+	if err != nil {
+		return nil, err
+	}
+	tempDir, err := os.MkdirTemp("", "koemoji-remote-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	startTime := time.Now()
+	var segments []Segment
+	for i, chunk := range chunks {
+		file := inputFile
+		if len(chunks) > 1 {
+			logger.LogInfo(log, logBuffer, logMutex, "Uploading part %d/%d of %s", i+1, len(chunks), filepath.Base(inputFile))
+			if file, err = extractChunk(ctx, inputFile, tempDir, i, chunk); err != nil {
+				return nil, err
+			}
+		}
+
+		chunkSegments, err := callTranscriptionAPI(ctx, b.config, log, logBuffer, logMutex, debugMode, file)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range chunkSegments {
+			s.Start += chunk.offset
+			s.End += chunk.offset
+			segments = append(segments, s)
+			advanceProgress(inputFile, s.End, s.Text)
+		}
+		if len(chunks) > 1 {
+			os.Remove(file)
+		}
+	}
+	logger.LogInfo(log, logBuffer, logMutex, "Transcription completed in %s", time.Since(startTime).Round(time.Second))
+
+	outputFiles, err := writeOutputs(outputDir, inputFile, b.config, segments)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Segments: segments, OutputFiles: outputFiles}, nil
+}
+
+// uploadChunk is one part of a file that is uploaded on its own
+type uploadChunk struct {
+	offset time.Duration // Start of the part in the input file
+	length time.Duration // 0 = to the end of the file
+}
+
+// uploadChunks returns the parts inputFile is uploaded in: the whole file
+// when it fits remote_max_upload_mb, otherwise parts of audio that fit as
+// 16 kHz mono WAV
+func uploadChunks(c *config.Config, inputFile string, audio time.Duration) ([]uploadChunk, error) {
+	info, err := os.Stat(inputFile)
+	if err != nil {
+		return nil, newTranscribeError(ErrorKindSetup, err)
+	}
+	limit := int64(c.RemoteMaxUploadMB) * 1024 * 1024
+	if limit <= 0 || info.Size() <= limit {
+		return []uploadChunk{{}}, nil
+	}
+	if audio <= 0 {
+		return nil, newTranscribeError(ErrorKindSetup,
+			fmt.Errorf("%s is larger than remote_max_upload_mb (%d MB) and cannot be split without ffprobe", filepath.Base(inputFile), c.RemoteMaxUploadMB))
+	}
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return nil, newTranscribeError(ErrorKindSetup,
+			fmt.Errorf("ffmpeg is needed to split %s for uploading: %w", filepath.Base(inputFile), err))
+	}
+
+	length := chunkLength(limit)
+	var chunks []uploadChunk
+	for offset := time.Duration(0); offset < audio; offset += length {
+		chunks = append(chunks, uploadChunk{offset: offset, length: length})
+	}
+	return chunks, nil
+}
+
+// chunkLength returns how much audio fits into limit bytes of 16 kHz mono
+// 16-bit WAV, in whole seconds
+func chunkLength(limit int64) time.Duration {
+	seconds := (limit - 1024) / wavBytesPerSecond // Room for the WAV header
+	if seconds < 1 {
+		seconds = 1
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// extractChunk writes part i of inputFile to tempDir as 16 kHz mono WAV
+func extractChunk(ctx context.Context, inputFile, tempDir string, i int, chunk uploadChunk) (string, error) {
+	path := filepath.Join(tempDir, fmt.Sprintf("part%03d.wav", i+1))
+	seconds := func(d time.Duration) string { return strconv.FormatFloat(d.Seconds(), 'f', 3, 64) }
+	cmd := createCommandContext(ctx, "ffmpeg", "-nostdin", "-y", "-loglevel", "error",
+		"-ss", seconds(chunk.offset), "-t", seconds(chunk.length), "-i", inputFile,
+		"-ac", "1", "-ar", "16000", "-c:a", "pcm_s16le", path)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", fmt.Errorf("transcription cancelled: %w", ctxErr)
+		}
+		return "", fmt.Errorf("ffmpeg split failed: %w, output: %s", err, strings.TrimSpace(string(output)))
+	}
+	return path, nil
+}
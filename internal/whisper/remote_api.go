@@ -0,0 +1,230 @@
+package whisper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+)
+
+// Retry settings of the transcription API, the same as llm.callOpenAI.
+// Variables so that tests do not have to wait.
+var (
+	remoteMaxRetries     = 3
+	remoteRetryDelay     = 10 * time.Second // Multiplied by the attempt number
+	remoteRateLimitDelay = 60 * time.Second
+	remoteRequestTimeout = 30 * time.Minute // An hour of audio can take minutes on a LAN server
+)
+
+// transcriptionResponse is the verbose_json response of /audio/transcriptions
+type transcriptionResponse struct {
+	Text     string  `json:"text"`
+	Language string  `json:"language"`
+	Duration float64 `json:"duration"`
+	Segments []struct {
+		Start        float64 `json:"start"`
+		End          float64 `json:"end"`
+		Text         string  `json:"text"`
+		AvgLogprob   float64 `json:"avg_logprob"`
+		NoSpeechProb float64 `json:"no_speech_prob"`
+	} `json:"segments"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// callTranscriptionAPI uploads file to {remote_base_url}/audio/transcriptions
+// and returns its segments. Network errors, rate limits (429) and server
+// errors (5xx) are retried like llm.callOpenAI.
+func callTranscriptionAPI(ctx context.Context, c *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry,
+	logMutex *sync.RWMutex, debugMode bool, file string) ([]Segment, error) {
+
+	body, contentType, err := transcriptionRequestBody(c, file)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := strings.TrimRight(c.RemoteBaseURL, "/") + "/audio/transcriptions"
+	logger.LogDebug(log, logBuffer, logMutex, debugMode, "Transcription API request: %s (%s, %d bytes)", endpoint, c.RemoteModel, len(body))
+
+	// Make request with retry logic
+	client := &http.Client{Timeout: remoteRequestTimeout}
+	var response *http.Response
+	for attempt := 1; attempt <= remoteMaxRetries; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, newTranscribeError(ErrorKindSetup, fmt.Errorf("failed to create request: %w", err))
+		}
+		req.Header.Set("Content-Type", contentType)
+		if c.RemoteAPIKey != "" {
+			req.Header.Set("Authorization", "Bearer "+c.RemoteAPIKey)
+		}
+
+		response, err = client.Do(req)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, fmt.Errorf("transcription cancelled: %w", ctxErr)
+			}
+			logger.LogError(log, logBuffer, logMutex, "Transcription API request failed (attempt %d/%d): %v", attempt, remoteMaxRetries, err)
+			if attempt < remoteMaxRetries {
+				if err := sleepContext(ctx, time.Duration(attempt)*remoteRetryDelay); err != nil {
+					return nil, fmt.Errorf("transcription cancelled: %w", err)
+				}
+				continue
+			}
+			return nil, fmt.Errorf("failed to call transcription API after %d attempts: %w", remoteMaxRetries, err)
+		}
+
+		if response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500 {
+			if attempt < remoteMaxRetries {
+				delay := time.Duration(attempt) * remoteRetryDelay
+				if response.StatusCode == http.StatusTooManyRequests {
+					// Rate limit hit
+					delay = remoteRateLimitDelay
+				}
+				logger.LogInfo(log, logBuffer, logMutex, "Transcription API busy (status %d), waiting %s...", response.StatusCode, delay)
+				response.Body.Close()
+				if err := sleepContext(ctx, delay); err != nil {
+					return nil, fmt.Errorf("transcription cancelled: %w", err)
+				}
+				continue
+			}
+		}
+
+		break
+	}
+	defer response.Body.Close()
+
+	// Read response
+	respBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	logger.LogDebug(log, logBuffer, logMutex, debugMode, "Transcription API response received (status: %d)", response.StatusCode)
+
+	// Check HTTP status code before parsing
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		truncatedBody := string(respBody)
+		if len(truncatedBody) > 1000 {
+			truncatedBody = truncatedBody[:1000] + "... (truncated)"
+		}
+		logger.LogError(log, logBuffer, logMutex, "Transcription API error (status %d). Response: %s", response.StatusCode, truncatedBody)
+		return nil, newTranscribeError(statusErrorKind(response.StatusCode),
+			fmt.Errorf("transcription API request failed with status %d", response.StatusCode))
+	}
+
+	var apiResponse transcriptionResponse
+	if err := json.Unmarshal(respBody, &apiResponse); err != nil {
+		logger.LogError(log, logBuffer, logMutex, "Failed to parse transcription API response. Body (first 500 chars): %s",
+			string(respBody[:minInt(500, len(respBody))]))
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	if apiResponse.Error != nil {
+		return nil, fmt.Errorf("transcription API error: %s", apiResponse.Error.Message)
+	}
+	return apiResponse.segments(), nil
+}
+
+// segments converts the response. Servers that return no segments get one
+// segment with the whole text.
+func (r *transcriptionResponse) segments() []Segment {
+	seconds := func(s float64) time.Duration { return time.Duration(s * float64(time.Second)) }
+
+	var segments []Segment
+	for _, s := range r.Segments {
+		if text := strings.TrimSpace(s.Text); text != "" {
+			segments = append(segments, Segment{Start: seconds(s.Start), End: seconds(s.End), Text: text,
+				AvgLogprob: s.AvgLogprob, NoSpeechProb: s.NoSpeechProb})
+		}
+	}
+	if len(r.Segments) == 0 {
+		if text := strings.TrimSpace(r.Text); text != "" {
+			segments = append(segments, Segment{End: seconds(r.Duration), Text: text})
+		}
+	}
+	return segments
+}
+
+// transcriptionRequestBody builds the multipart form with the audio file
+func transcriptionRequestBody(c *config.Config, file string) ([]byte, string, error) {
+	audio, err := os.Open(file)
+	if err != nil {
+		return nil, "", newTranscribeError(ErrorKindSetup, err)
+	}
+	defer audio.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	fields := [][2]string{
+		{"model", c.RemoteModel},
+		{"response_format", "verbose_json"},
+	}
+	// Without a language the server detects it
+	if c.Language != "" && c.Language != "auto" {
+		fields = append(fields, [2]string{"language", c.Language})
+	}
+	// The API has no hotwords; the vocabulary is always the prompt
+	if len(c.Vocabulary) > 0 {
+		fields = append(fields, [2]string{"prompt", c.VocabularyText()})
+	}
+	for _, field := range fields {
+		if err := form.WriteField(field[0], field[1]); err != nil {
+			return nil, "", err
+		}
+	}
+	part, err := form.CreateFormFile("file", filepath.Base(file))
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := io.Copy(part, audio); err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", filepath.Base(file), err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, "", err
+	}
+	return body.Bytes(), form.FormDataContentType(), nil
+}
+
+// statusErrorKind classifies a failed response: a wrong URL, model or API
+// key is a setup problem, other client errors are about the file itself
+func statusErrorKind(status int) ErrorKind {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusNotFound:
+		return ErrorKindSetup
+	case status >= 400 && status < 500 && status != http.StatusTooManyRequests:
+		return ErrorKindOutput
+	}
+	return ErrorKindTransient
+}
+
+// sleepContext waits for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// minInt returns the minimum of two integers
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
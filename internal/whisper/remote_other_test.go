@@ -0,0 +1,72 @@
+//go:build !windows
+// +build !windows
+
+package whisper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockFFmpegScript records its arguments in $MOCK_FFMPEG_ARGS and writes a
+// small WAV file (copied from $MOCK_FFMPEG_OUTPUT) to its last argument
+const mockFFmpegScript = `#!/bin/sh
+echo "$@" >> "$MOCK_FFMPEG_ARGS"
+for last; do :; done
+cp "$MOCK_FFMPEG_OUTPUT" "$last"
+`
+
+func TestRemoteBackend_SplitsLargeFiles(t *testing.T) {
+	dir := t.TempDir()
+	binDir := filepath.Join(dir, "bin")
+	require.NoError(t, os.MkdirAll(binDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "ffmpeg"), []byte(mockFFmpegScript), 0755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	argsFile := filepath.Join(dir, "ffmpeg-args")
+	t.Setenv("MOCK_FFMPEG_ARGS", argsFile)
+	partFile := filepath.Join(dir, "part.wav")
+	require.NoError(t, os.WriteFile(partFile, wavBytes(32000, 32000), 0644))
+	t.Setenv("MOCK_FFMPEG_OUTPUT", partFile)
+
+	var mu sync.Mutex
+	var uploads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		mu.Lock()
+		uploads = append(uploads, header.Filename)
+		mu.Unlock()
+		w.Write([]byte(verboseJSON([3]interface{}{1.0, 2.0, header.Filename})))
+	}))
+	defer server.Close()
+
+	cfg, input := remoteTestConfig(t, server.URL)
+	cfg.RemoteMaxUploadMB = 1
+	// 40 seconds of audio do not fit into 1 MB: parts of 32 seconds
+	require.NoError(t, os.WriteFile(input, wavBytes(40*32000, 40*32000), 0644))
+
+	result, err := transcribeRemote(t, cfg, input)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"part001.wav", "part002.wav"}, uploads)
+	assert.Equal(t, []Segment{
+		{Start: time.Second, End: 2 * time.Second, Text: "part001.wav"},
+		{Start: 33 * time.Second, End: 34 * time.Second, Text: "part002.wav"},
+	}, result.Segments, "segments of later parts are shifted by their offset")
+
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(args)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "-ss 0.000 -t 32.000 -i "+input)
+	assert.Contains(t, lines[1], "-ss 32.000 -t 32.000 -i "+input)
+}
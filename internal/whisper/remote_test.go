@@ -0,0 +1,187 @@
+package whisper
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verboseJSON is a /audio/transcriptions response with the given segments
+func verboseJSON(segments ...[3]interface{}) string {
+	type segment struct {
+		Start float64 `json:"start"`
+		End   float64 `json:"end"`
+		Text  string  `json:"text"`
+	}
+	response := struct {
+		Text     string    `json:"text"`
+		Segments []segment `json:"segments"`
+	}{}
+	for _, s := range segments {
+		response.Segments = append(response.Segments, segment{s[0].(float64), s[1].(float64), s[2].(string)})
+		response.Text += s[2].(string)
+	}
+	data, _ := json.Marshal(response)
+	return string(data)
+}
+
+// fastRemoteRetries removes the waits between retries for the test
+func fastRemoteRetries(t *testing.T) {
+	delay, rateLimit := remoteRetryDelay, remoteRateLimitDelay
+	remoteRetryDelay, remoteRateLimitDelay = 0, 0
+	t.Cleanup(func() { remoteRetryDelay, remoteRateLimitDelay = delay, rateLimit })
+}
+
+// remoteTestConfig returns a config for the remote backend against serverURL
+// with input written as a one second WAV file
+func remoteTestConfig(t *testing.T, serverURL string) (*config.Config, string) {
+	dir := t.TempDir()
+	cfg := config.GetDefaultConfig()
+	cfg.TranscriptionBackend = config.BackendOpenAI
+	cfg.RemoteBaseURL = serverURL + "/v1"
+	cfg.RemoteModel = "whisper-1"
+	cfg.RemoteAPIKey = "sk-test"
+	cfg.InputDir = filepath.Join(dir, "input")
+	cfg.OutputDir = filepath.Join(dir, "output")
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	input := filepath.Join(cfg.InputDir, "call.wav")
+	require.NoError(t, os.WriteFile(input, wavBytes(32000, 32000), 0644))
+	return cfg, input
+}
+
+func transcribeRemote(t *testing.T, cfg *config.Config, input string) (*Result, error) {
+	transcriber, err := NewTranscriber(cfg)
+	require.NoError(t, err)
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	return transcriber.Transcribe(context.Background(), nil, &logBuffer, &logMutex, false, input)
+}
+
+func TestRemoteBackend_Transcribe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/audio/transcriptions", r.URL.Path)
+		assert.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		assert.Equal(t, "whisper-1", r.FormValue("model"))
+		assert.Equal(t, "verbose_json", r.FormValue("response_format"))
+		assert.Equal(t, "ja", r.FormValue("language"))
+		assert.Equal(t, "KoeMoji、山田", r.FormValue("prompt"), "the vocabulary is the prompt")
+		_, header, err := r.FormFile("file")
+		require.NoError(t, err)
+		assert.Equal(t, "call.wav", header.Filename)
+
+		w.Write([]byte(verboseJSON(
+			[3]interface{}{0.0, 2.5, " こんにちは"},
+			[3]interface{}{2.5, 4.0, " 本日の議題です"},
+		)))
+	}))
+	defer server.Close()
+
+	cfg, input := remoteTestConfig(t, server.URL)
+	cfg.OutputFormat = "vtt"
+	cfg.Vocabulary = []string{"KoeMoji", "山田"}
+	cfg.VocabularyMode = config.VocabularyModeHotwords
+	result, err := transcribeRemote(t, cfg, input)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Segment{
+		{Start: 0, End: 2500 * time.Millisecond, Text: "こんにちは"},
+		{Start: 2500 * time.Millisecond, End: 4 * time.Second, Text: "本日の議題です"},
+	}, result.Segments)
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "call.vtt"))
+	require.NoError(t, err)
+	assert.Equal(t, "WEBVTT\n\n00:00.000 --> 00:02.500\nこんにちは\n\n00:02.500 --> 00:04.000\n本日の議題です\n\n", string(data))
+}
+
+func TestRemoteBackend_RetriesServerErrors(t *testing.T) {
+	fastRemoteRetries(t)
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			http.Error(w, "model loading", http.StatusServiceUnavailable)
+			return
+		}
+		if requests == 2 {
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"text": " hello", "duration": 1.0}`))
+	}))
+	defer server.Close()
+
+	cfg, input := remoteTestConfig(t, server.URL)
+	result, err := transcribeRemote(t, cfg, input)
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, []Segment{{End: time.Second, Text: "hello"}}, result.Segments, "text without segments")
+}
+
+func TestRemoteBackend_StatusErrors(t *testing.T) {
+	fastRemoteRetries(t)
+	tests := []struct {
+		status   int
+		kind     ErrorKind
+		requests int
+	}{
+		{http.StatusUnauthorized, ErrorKindSetup, 1},
+		{http.StatusBadRequest, ErrorKindOutput, 1},
+		{http.StatusBadGateway, ErrorKindTransient, remoteMaxRetries},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				http.Error(w, `{"error": {"message": "nope"}}`, tt.status)
+			}))
+			defer server.Close()
+
+			cfg, input := remoteTestConfig(t, server.URL)
+			_, err := transcribeRemote(t, cfg, input)
+			require.Error(t, err)
+			assert.Equal(t, tt.kind, ClassifyError(err))
+			assert.Equal(t, tt.requests, requests)
+		})
+	}
+}
+
+func TestRemoteBackend_Available(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	backend := &remoteBackend{config: cfg}
+	assert.NoError(t, backend.Available())
+	assert.False(t, backend.Capabilities().Local)
+
+	cfg.RemoteBaseURL = "api.openai.com/v1"
+	assert.Error(t, backend.Available())
+
+	cfg.RemoteBaseURL = "http://192.168.1.10:8000/v1"
+	cfg.RemoteModel = ""
+	assert.Error(t, backend.Available())
+}
+
+func TestUploadChunks(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.RemoteMaxUploadMB = 1
+	input := filepath.Join(t.TempDir(), "small.wav")
+	require.NoError(t, os.WriteFile(input, wavBytes(32000, 32000), 0644))
+
+	chunks, err := uploadChunks(cfg, input, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, []uploadChunk{{}}, chunks, "files under the limit are uploaded whole")
+
+	assert.Equal(t, 32*time.Second, chunkLength(1024*1024))
+	assert.Equal(t, time.Second, chunkLength(100))
+}
@@ -2,6 +2,8 @@ package whisper
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -10,15 +12,21 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/infoHiroki/KoeMoji-Go/internal/config"
 	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/transcript"
 	"github.com/infoHiroki/KoeMoji-Go/internal/ui"
 )
 
+// processWaitDelay is how long Wait keeps waiting for the output pipes after
+// a cancelled whisper process was killed
+const processWaitDelay = 5 * time.Second
+
 func getWhisperCommand() string {
 	return getWhisperCommandWithDebug(nil, nil, nil, false)
 }
@@ -156,131 +164,315 @@ func installFasterWhisper(log *log.Logger, logBuffer *[]logger.LogEntry, logMute
 	return nil
 }
 
-func TranscribeAudio(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry,
+// TranscribeAudio runs whisper-ctranslate2 on inputFile. Cancelling ctx kills
+// the whisper process tree and returns an error that wraps ctx.Err().
+// The processor goes through NewTranscriber instead.
+func TranscribeAudio(ctx context.Context, config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry,
 	logMutex *sync.RWMutex, debugMode bool, inputFile string) error {
 
-	// セキュリティチェック: inputディレクトリ内のファイルのみ許可
-	absPath, err := filepath.Abs(inputFile)
+	_, err := transcribeCLI(ctx, config, log, logBuffer, logMutex, debugMode, inputFile)
+	return err
+}
+
+// transcribeCLI is the whisper-ctranslate2 backend: it runs the command line
+// tool and collects the segments printed with --verbose True
+func transcribeCLI(ctx context.Context, config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry,
+	logMutex *sync.RWMutex, debugMode bool, inputFile string) (*Result, error) {
+
+	outputDir, err := prepareTranscription(config, inputFile)
 	if err != nil {
-		msg := ui.GetMessages(config)
-		return fmt.Errorf(msg.InvalidPath, err)
+		return nil, err
+	}
+	if err := checkFormats(Capabilities{OutputFormats: whisperFormats}, config); err != nil {
+		return nil, err
 	}
-	inputDir, err := filepath.Abs(config.InputDir)
+
+	// whisper writes its JSON into a temporary directory; the configured
+	// formats are rendered from the segments in it
+	whisperDir, err := os.MkdirTemp("", "koemoji-whisper-")
 	if err != nil {
-		msg := ui.GetMessages(config)
-		return fmt.Errorf(msg.InvalidPath, err)
+		return nil, newTranscribeError(ErrorKindSetup, fmt.Errorf("failed to create temporary directory: %w", err))
 	}
-	if !strings.HasPrefix(absPath, inputDir+string(os.PathSeparator)) {
+	defer os.RemoveAll(whisperDir)
+
+	whisperCmd := getWhisperCommandWithDebug(log, logBuffer, logMutex, debugMode)
+	cmd := createCommandContext(ctx, whisperCmd, ctranslate2Args(config, whisperDir, inputFile)...)
+
+	// 進捗をリアルタイムに読むため、Pythonの出力バッファリングを無効にする
+	cmd.Env = append(os.Environ(), "PYTHONUNBUFFERED=1")
+
+	logger.LogDebug(log, logBuffer, logMutex, debugMode, "Whisper command: %s", strings.Join(cmd.Args, " "))
+
+	// The printed segments only drive the progress; the result is read from the JSON
+	_, err = runWhisperProcess(ctx, config, log, logBuffer, logMutex, debugMode, cmd, inputFile)
+	if err != nil {
+		var startErr *processStartError
+		if errors.As(err, &startErr) {
+			// Check if whisper-ctranslate2 is not found
+			if errors.Is(err, exec.ErrNotFound) || strings.Contains(err.Error(), "executable file not found") {
+				msg := ui.GetMessages(config)
+				return nil, newTranscribeError(ErrorKindSetup, fmt.Errorf("%s\n%s", msg.WhisperNotFound, msg.WhisperLocation))
+			}
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
 		msg := ui.GetMessages(config)
-		return fmt.Errorf(msg.InvalidPath, inputFile)
-	}
 
-	// 入力ファイルの存在チェック
-	if _, err := os.Stat(inputFile); os.IsNotExist(err) {
-		return fmt.Errorf("input file does not exist: %s", inputFile)
-	}
+		// Check for GPU-related errors and provide detailed guidance.
+		// The exit status says nothing, so the end of stderr is checked.
+		errorStr := err.Error()
+		var exitErr *processExitError
+		if errors.As(err, &exitErr) {
+			errorStr = exitErr.stderr
+		}
+		if isGPURelatedError(errorStr) {
+			return nil, newTranscribeError(ErrorKindGPU, createGPUErrorMessage(config, err))
+		}
 
-	// 入力ディレクトリの存在チェック
-	if _, err := os.Stat(config.InputDir); os.IsNotExist(err) {
-		return fmt.Errorf("input directory does not exist: %s", config.InputDir)
+		return nil, fmt.Errorf(msg.TranscribeFail, err)
 	}
 
-	// 出力ディレクトリの作成確認
-	if err := os.MkdirAll(config.OutputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	// Verify the output was created and is not empty
+	segments, err := readCLIOutput(config, whisperDir, inputFile)
+	if err != nil {
+		return nil, newTranscribeError(ErrorKindOutput, err)
 	}
 
-	whisperCmd := getWhisperCommandWithDebug(log, logBuffer, logMutex, debugMode)
+	outputFiles, err := writeOutputs(outputDir, inputFile, config, segments)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Segments: segments, OutputFiles: outputFiles}, nil
+}
 
-	// Build command arguments
+// ctranslate2Args maps our settings to whisper-ctranslate2 flags. whisper
+// writes JSON into outputDir; the configured formats are rendered from it.
+func ctranslate2Args(c *config.Config, outputDir, inputFile string) []string {
 	args := []string{
-		"--model", config.WhisperModel,
-		"--language", config.Language,
-		"--output_dir", config.OutputDir,
-		"--output_format", config.OutputFormat,
-		"--compute_type", config.ComputeType,
+		"--model", c.WhisperModel,
+		"--language", c.Language,
+		"--output_dir", outputDir,
+		"--output_format", "json",
+		"--compute_type", c.ComputeType,
 	}
-	
+
 	// Always use CPU to avoid GPU initialization issues (especially on Windows)
 	args = append(args, "--device", "cpu")
-	
-	// Add verbose and input file
-	args = append(args, "--verbose", "True", inputFile)
-	
-	cmd := createCommand(whisperCmd, args...)
 
-	logger.LogDebug(log, logBuffer, logMutex, debugMode, "Whisper command: %s", strings.Join(cmd.Args, " "))
+	// Word timing and probability (word_timestamps, low_confidence_threshold)
+	if c.WordTimestampsNeeded() {
+		args = append(args, "--word_timestamps", "True")
+	}
 
-	// Start progress monitoring
-	startTime := time.Now()
-	done := make(chan bool)
+	// Custom vocabulary (vocabulary, vocabulary_mode)
+	if c.VocabularyHotwords() {
+		args = append(args, "--hotwords", c.VocabularyText())
+	} else if len(c.Vocabulary) > 0 {
+		args = append(args, "--initial_prompt", c.VocabularyText())
+	}
 
-	// Monitor progress in background
-	go monitorProgress(log, logBuffer, logMutex, filepath.Base(inputFile), startTime, done)
+	// max_cpu_percent: スレッド数を上限に合わせる
+	if cpuLimitActive(c) {
+		args = append(args, "--threads", strconv.Itoa(threadCount(c, runtime.NumCPU())))
+	}
 
-	// Capture and display output
-	stdout, err := cmd.StdoutPipe()
+	// Segment lines for the progress, then the input file
+	return append(args, "--verbose", "True", inputFile)
+}
+
+// readCLIOutput reads the segments from the JSON that whisper wrote for
+// inputFile into dir. The JSON has the timings, words and confidence that
+// the verbose lines do not.
+func readCLIOutput(c *config.Config, dir, inputFile string) ([]Segment, error) {
+	jsonFile := outputPath(dir, inputFile, "json")
+	if err := validateOutputFile(jsonFile, c); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(jsonFile)
 	if err != nil {
-		done <- true
-		return fmt.Errorf("failed to create stdout pipe: %w", err)
+		return nil, err
+	}
+	var doc transcript.Transcript
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to read whisper output %s: %w", filepath.Base(jsonFile), err)
+	}
+	return doc.Segments, nil
+}
+
+// processStartError is returned by runWhisperProcess when the command could
+// not be started
+type processStartError struct {
+	err error
+}
+
+func (e *processStartError) Error() string {
+	return fmt.Sprintf("failed to start command: %v", e.err)
+}
+
+func (e *processStartError) Unwrap() error {
+	return e.err
+}
+
+// processExitError is returned by runWhisperProcess when the command failed.
+// It keeps the last lines the process wrote to stderr, because the exit
+// error itself only says "exit status N".
+type processExitError struct {
+	err    error
+	stderr string
+}
+
+func (e *processExitError) Error() string {
+	if e.stderr == "" {
+		return e.err.Error()
+	}
+	lines := strings.Split(e.stderr, "\n")
+	return fmt.Sprintf("%v: %s", e.err, lines[len(lines)-1])
+}
+
+func (e *processExitError) Unwrap() error {
+	return e.err
+}
+
+// stderrTailLines is how many stderr lines are kept for error classification
+const stderrTailLines = 20
+
+// outputTail keeps the last lines of a process output
+type outputTail struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (t *outputTail) add(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lines = append(t.lines, line)
+	if len(t.lines) > stderrTailLines {
+		t.lines = t.lines[len(t.lines)-stderrTailLines:]
 	}
+}
+
+func (t *outputTail) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return strings.Join(t.lines, "\n")
+}
 
-	stderr, err := cmd.StderrPipe()
+// runWhisperProcess runs a whisper command line tool for inputFile and
+// returns the segments it printed. It reports progress from the segment
+// lines, applies max_cpu_percent and lets SetPaused suspend the process.
+// Cancelling ctx kills the process tree and returns an error that wraps
+// ctx.Err(); otherwise the exit error is returned as a *processExitError
+// with the end of stderr.
+func runWhisperProcess(ctx context.Context, config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry,
+	logMutex *sync.RWMutex, debugMode bool, cmd *exec.Cmd, inputFile string) ([]Segment, error) {
+
+	// 既にキャンセルされている場合は起動しない
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("transcription cancelled: %w", err)
+	}
+
+	// 音声の長さが分かれば、セグメントの時刻から進捗率を出せる
+	audioDuration, err := AudioDuration(inputFile)
 	if err != nil {
-		done <- true
-		return fmt.Errorf("failed to create stderr pipe: %w", err)
+		logger.LogDebug(log, logBuffer, logMutex, debugMode, "Audio duration unknown, progress shows text only: %v", err)
+	}
+	stopProgress := startProgress(inputFile, audioDuration)
+	defer stopProgress()
+
+	// Start progress monitoring
+	startTime := time.Now()
+	done := make(chan bool)
+
+	// Monitor progress in background
+	go monitorProgress(log, logBuffer, logMutex, inputFile, startTime, done)
+
+	// Capture output through pipes we close ourselves, so that every segment
+	// line has been read when the result is returned
+	stdout, stdoutWriter := io.Pipe()
+	stderr, stderrWriter := io.Pipe()
+	cmd.Stdout = stdoutWriter
+	cmd.Stderr = stderrWriter
+
+	// With max_cpu_percent the process is limited before it runs
+	cpuLimited := cpuLimitActive(config)
+	if cpuLimited {
+		startSuspended(cmd)
 	}
 
 	// Start command
 	if err := cmd.Start(); err != nil {
 		done <- true
-		// Check if whisper-ctranslate2 is not found
-		if errors.Is(err, exec.ErrNotFound) || strings.Contains(err.Error(), "executable file not found") {
-			msg := ui.GetMessages(config)
-			return fmt.Errorf("%s\n%s", msg.WhisperNotFound, msg.WhisperLocation)
+		return nil, &processStartError{err: err}
+	}
+
+	// 録音中は SetPaused で一時停止できるよう登録する
+	clock := suspendClockFrom(ctx)
+	untrack := trackProcess(cmd, clock)
+
+	// max_cpu_percent: 優先度を下げ、上限を超えた分は一時停止させる
+	stopCPULimit := func() {}
+	if cpuLimited {
+		if err := lowerPriority(cmd); err != nil {
+			logger.LogDebug(log, logBuffer, logMutex, debugMode, "Could not lower whisper priority: %v", err)
+		}
+		if stop, err := limitCPU(cmd, jobCPUPercent(config), runtime.NumCPU(), clock); err != nil {
+			logger.LogDebug(log, logBuffer, logMutex, debugMode, "CPU limit unavailable: %v", err)
+		} else {
+			stopCPULimit = stop
+		}
+		if err := resumeStarted(cmd); err != nil {
+			// Never leave whisper suspended; Wait reports the failed run
+			logger.LogError(log, logBuffer, logMutex, "Could not resume whisper: %v", err)
+			cmd.Process.Kill()
 		}
-		return fmt.Errorf("failed to start command: %w", err)
 	}
 
 	// Read output in background
-	go readCommandOutput(log, logBuffer, logMutex, debugMode, stdout, "STDOUT")
-	go readCommandOutput(log, logBuffer, logMutex, debugMode, stderr, "STDERR")
+	segments := &segmentRecorder{}
+	stderrTail := &outputTail{}
+	var readers sync.WaitGroup
+	readers.Add(2)
+	go func() {
+		defer readers.Done()
+		readCommandOutput(log, logBuffer, logMutex, debugMode, stdout, "STDOUT", inputFile, segments, nil)
+	}()
+	go func() {
+		defer readers.Done()
+		readCommandOutput(log, logBuffer, logMutex, debugMode, stderr, "STDERR", inputFile, segments, stderrTail)
+	}()
 
 	// Wait for completion
 	err = cmd.Wait()
+	stopCPULimit()
+	untrack()
+	stdoutWriter.Close()
+	stderrWriter.Close()
+	readers.Wait()
 
 	// Stop progress monitoring
 	done <- true
 
-	if err != nil {
-		msg := ui.GetMessages(config)
-
-		// Check for GPU-related errors and provide detailed guidance
-		errorStr := err.Error()
-		if isGPURelatedError(errorStr) {
-			return createGPUErrorMessage(config, err)
-		}
-
-		return fmt.Errorf(msg.TranscribeFail, err)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("transcription cancelled: %w", ctxErr)
 	}
-
-	// Verify output file was created and is not empty
-	basename := strings.TrimSuffix(filepath.Base(inputFile), filepath.Ext(inputFile))
-	outputFile := filepath.Join(config.OutputDir, basename+"."+config.OutputFormat)
-
-	if err := validateOutputFile(outputFile, config); err != nil {
-		return err
+	if err != nil {
+		return nil, &processExitError{err: err, stderr: stderrTail.String()}
 	}
 
 	// Log processing time for performance analysis
 	duration := time.Since(startTime)
 	logger.LogInfo(log, logBuffer, logMutex, "Transcription completed in %s", duration.Round(time.Second))
 
-	return nil
+	return segments.list(), nil
 }
 
+// readCommandOutput reads the output of a whisper process line by line.
+// When tail is not nil the last lines are kept in it.
 func readCommandOutput(log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
-	debugMode bool, pipe io.ReadCloser, source string) {
+	debugMode bool, pipe io.ReadCloser, source, inputFile string, segments *segmentRecorder, tail *outputTail) {
 
 	defer pipe.Close()
 	scanner := bufio.NewScanner(pipe)
@@ -288,6 +480,12 @@ func readCommandOutput(log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line != "" {
+			// Segment lines drive the progress shown in the TUI/GUI
+			updateProgress(inputFile, line)
+			segments.add(line)
+			if tail != nil {
+				tail.add(line)
+			}
 			// Log other output for debugging
 			logger.LogDebug(log, logBuffer, logMutex, debugMode, "[%s] %s", source, line)
 		}
@@ -295,7 +493,7 @@ func readCommandOutput(log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *
 }
 
 func monitorProgress(log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sync.RWMutex,
-	filename string, startTime time.Time, done chan bool) {
+	inputFile string, startTime time.Time, done chan bool) {
 
 	ticker := time.NewTicker(30 * time.Second) // 30秒ごとに進行状況を報告
 	defer ticker.Stop()
@@ -306,25 +504,16 @@ func monitorProgress(log *log.Logger, logBuffer *[]logger.LogEntry, logMutex *sy
 			return
 		case <-ticker.C:
 			elapsed := time.Since(startTime)
-			logger.LogInfo(log, logBuffer, logMutex, "Still processing %s (elapsed: %s)", filename, formatDuration(elapsed))
-		}
-	}
-}
-
-func EnsureDependencies(config *config.Config, log *log.Logger, logBuffer *[]logger.LogEntry,
-	logMutex *sync.RWMutex, debugMode bool) error {
-
-	if !isFasterWhisperAvailable() {
-		logger.LogInfo(log, logBuffer, logMutex, "FasterWhisper not found. Attempting to install...")
-		if err := installFasterWhisper(log, logBuffer, logMutex); err != nil {
-			logger.LogError(log, logBuffer, logMutex, "FasterWhisper automatic installation failed: %v", err)
-			logger.LogError(log, logBuffer, logMutex, "Please install Python 3.12 and restart, or manually run: pip install faster-whisper whisper-ctranslate2")
-			return fmt.Errorf("FasterWhisper installation failed: %v", err)
+			if p, ok := CurrentProgress(inputFile); ok {
+				if percent, known := p.Percent(); known {
+					logger.LogInfo(log, logBuffer, logMutex, "Still processing %s (elapsed: %s, %.0f%%)",
+						filepath.Base(inputFile), formatDuration(elapsed), percent)
+					continue
+				}
+			}
+			logger.LogInfo(log, logBuffer, logMutex, "Still processing %s (elapsed: %s)", filepath.Base(inputFile), formatDuration(elapsed))
 		}
-	} else {
-		logger.LogDebug(log, logBuffer, logMutex, debugMode, "FasterWhisper is available")
 	}
-	return nil
 }
 
 func formatDuration(d time.Duration) string {
@@ -425,9 +614,9 @@ func validateOutputFile(outputPath string, config *config.Config) error {
 	fileInfo, err := os.Stat(outputPath)
 	if os.IsNotExist(err) {
 		if config.UILanguage == "ja" {
-			return fmt.Errorf("出力ファイルが生成されませんでした: %s\n\n考えられる原因:\n・音声/動画ファイルが破損している可能性があります\n・音声認識エンジンが処理できない形式です\n\n対処方法:\n・WAV形式に変換してから再度処理してください\n\n元ファイルはinputフォルダに保持されています。", outputPath)
+			return fmt.Errorf("出力ファイルが生成されませんでした: %s\n\n考えられる原因:\n・音声/動画ファイルが破損している可能性があります\n・音声認識エンジンが処理できない形式です\n\n対処方法:\n・WAV形式に変換してから再度処理してください\n\n元ファイルはfailedフォルダに移動されます。", outputPath)
 		}
-		return fmt.Errorf("output file was not created: %s\n\nPossible causes:\n・Audio/video file may be corrupted\n・Format not supported by recognition engine\n\nSolution:\n・Convert to WAV format and try again\n\nOriginal file has been kept in input folder.", outputPath)
+		return fmt.Errorf("output file was not created: %s\n\nPossible causes:\n・Audio/video file may be corrupted\n・Format not supported by recognition engine\n\nSolution:\n・Convert to WAV format and try again\n\nOriginal file will be moved to the failed folder.", outputPath)
 	}
 	if err != nil {
 		return fmt.Errorf("failed to check output file: %w", err)
@@ -435,9 +624,9 @@ func validateOutputFile(outputPath string, config *config.Config) error {
 
 	if fileInfo.Size() == 0 {
 		if config.UILanguage == "ja" {
-			return fmt.Errorf("出力ファイルが空です（0バイト）: %s\n\n考えられる原因:\n・音声/動画ファイルが破損している可能性があります\n・音声が検出されませんでした\n\n対処方法:\n・WAV形式に変換してから再度処理してください\n\n元ファイルはinputフォルダに保持されています。", outputPath)
+			return fmt.Errorf("出力ファイルが空です（0バイト）: %s\n\n考えられる原因:\n・音声/動画ファイルが破損している可能性があります\n・音声が検出されませんでした\n\n対処方法:\n・WAV形式に変換してから再度処理してください\n\n元ファイルはfailedフォルダに移動されます。", outputPath)
 		}
-		return fmt.Errorf("output file is empty (0 bytes): %s\n\nPossible causes:\n・Audio/video file may be corrupted\n・No audio detected\n\nSolution:\n・Convert to WAV format and try again\n\nOriginal file has been kept in input folder.", outputPath)
+		return fmt.Errorf("output file is empty (0 bytes): %s\n\nPossible causes:\n・Audio/video file may be corrupted\n・No audio detected\n\nSolution:\n・Convert to WAV format and try again\n\nOriginal file will be moved to the failed folder.", outputPath)
 	}
 
 	return nil
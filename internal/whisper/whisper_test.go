@@ -1,6 +1,7 @@
 package whisper
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -67,7 +68,7 @@ func TestTranscribeAudio_SecurityValidation(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := TranscribeAudio(config, logger, logBuffer, logMutex, false, tt.input)
+			err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, false, tt.input)
 
 			if tt.expectedError {
 				assert.Error(t, err)
@@ -97,7 +98,7 @@ func TestTranscribeAudio_FileValidation(t *testing.T) {
 	// Test with valid audio file
 	audioFile := testdata.CreateTestAudioFile(t, config.InputDir, "test.wav")
 
-	err := TranscribeAudio(config, logger, logBuffer, logMutex, false, audioFile)
+	err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, false, audioFile)
 
 	// In test environment, this will likely fail due to missing whisper command
 	// But the security validation should pass
@@ -226,7 +227,7 @@ func TestCommandConstruction(t *testing.T) {
 
 	// Test that TranscribeAudio constructs the command correctly
 	// This will fail due to missing whisper, but we can check the error
-	err := TranscribeAudio(config, logger, logBuffer, logMutex, true, audioFile)
+	err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, true, audioFile)
 
 	// Should fail due to missing whisper command, not due to invalid arguments
 	if err != nil {
@@ -251,7 +252,7 @@ func TestAudioFileFormats(t *testing.T) {
 		t.Run(fmt.Sprintf("Format_%s", format), func(t *testing.T) {
 			audioFile := testdata.CreateTestAudioFile(t, config.InputDir, fmt.Sprintf("test.%s", format))
 
-			err := TranscribeAudio(config, logger, logBuffer, logMutex, false, audioFile)
+			err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, false, audioFile)
 
 			// Should not fail due to unsupported format
 			if err != nil {
@@ -278,7 +279,7 @@ func TestConfigParameterValidation(t *testing.T) {
 	for _, model := range models {
 		t.Run(fmt.Sprintf("Model_%s", model), func(t *testing.T) {
 			config.WhisperModel = model
-			err := TranscribeAudio(config, logger, logBuffer, logMutex, false, audioFile)
+			err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, false, audioFile)
 
 			// Should not fail due to invalid model
 			if err != nil {
@@ -293,7 +294,7 @@ func TestConfigParameterValidation(t *testing.T) {
 	for _, lang := range languages {
 		t.Run(fmt.Sprintf("Language_%s", lang), func(t *testing.T) {
 			config.Language = lang
-			err := TranscribeAudio(config, logger, logBuffer, logMutex, false, audioFile)
+			err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, false, audioFile)
 
 			// Should not fail due to invalid language
 			if err != nil {
@@ -308,7 +309,7 @@ func TestConfigParameterValidation(t *testing.T) {
 	for _, format := range formats {
 		t.Run(fmt.Sprintf("Format_%s", format), func(t *testing.T) {
 			config.OutputFormat = format
-			err := TranscribeAudio(config, logger, logBuffer, logMutex, false, audioFile)
+			err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, false, audioFile)
 
 			// Should not fail due to invalid format
 			if err != nil {
@@ -323,7 +324,7 @@ func TestConfigParameterValidation(t *testing.T) {
 	for _, computeType := range computeTypes {
 		t.Run(fmt.Sprintf("ComputeType_%s", computeType), func(t *testing.T) {
 			config.ComputeType = computeType
-			err := TranscribeAudio(config, logger, logBuffer, logMutex, false, audioFile)
+			err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, false, audioFile)
 
 			// Should not fail due to invalid compute type
 			if err != nil {
@@ -343,7 +344,7 @@ func TestErrorHandling(t *testing.T) {
 		testdata.CreateDirectories(t, config.InputDir, config.OutputDir)
 		missingFile := filepath.Join(config.InputDir, "nonexistent.wav")
 
-		err := TranscribeAudio(config, logger, logBuffer, logMutex, false, missingFile)
+		err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, false, missingFile)
 		assert.Error(t, err)
 	})
 
@@ -351,7 +352,7 @@ func TestErrorHandling(t *testing.T) {
 		config.InputDir = "/nonexistent/directory"
 		testFile := filepath.Join(config.InputDir, "test.wav")
 
-		err := TranscribeAudio(config, logger, logBuffer, logMutex, false, testFile)
+		err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, false, testFile)
 		assert.Error(t, err)
 	})
 
@@ -361,7 +362,7 @@ func TestErrorHandling(t *testing.T) {
 		testdata.CreateDirectories(t, config.InputDir)
 		audioFile := testdata.CreateTestAudioFile(t, config.InputDir, "test.wav")
 
-		err := TranscribeAudio(config, logger, logBuffer, logMutex, false, audioFile)
+		err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, false, audioFile)
 		// Should fail due to invalid output directory
 		assert.Error(t, err)
 		
@@ -411,7 +412,7 @@ func TestTranscribeAudio_DeviceParameter(t *testing.T) {
 			config.ComputeType = tt.computeType
 			
 			// This will fail due to missing whisper, but we document expected behavior
-			err := TranscribeAudio(config, logger, logBuffer, logMutex, true, audioFile)
+			err := TranscribeAudio(context.Background(), config, logger, logBuffer, logMutex, true, audioFile)
 
 			// The test documents that all compute types should force CPU device selection (v1.8.3+)
 			if !tt.expectDevice {
@@ -0,0 +1,226 @@
+package whisper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/infoHiroki/KoeMoji-Go/internal/ui"
+)
+
+// whisperCppCommands are the names of the whisper.cpp binary looked up on the
+// PATH: whisper-cli since v1.7.4, whisper-cpp in Homebrew before that
+var whisperCppCommands = []string{"whisper-cli", "whisper-cpp"}
+
+// whisperCppBundled are the names looked up next to KoeMoji-Go, where "main"
+// (the binary of older releases) is not ambiguous
+var whisperCppBundled = []string{"whisper-cli", "main"}
+
+// whisperCppModelURL is where the GGML models can be downloaded
+const whisperCppModelURL = "https://huggingface.co/ggerganov/whisper.cpp"
+
+// whisperCppBlankAudio is the text whisper.cpp prints for segments without speech
+const whisperCppBlankAudio = "[BLANK_AUDIO]"
+
+// whisperCppBackend runs a whisper.cpp binary with GGML models
+// (transcription_backend "whisper.cpp"). It needs no Python: only the binary,
+// a model file and, for inputs other than 16 kHz WAV, ffmpeg.
+type whisperCppBackend struct {
+	config *config.Config
+}
+
+func (b *whisperCppBackend) Name() string {
+	return config.BackendWhisperCpp
+}
+
+func (b *whisperCppBackend) Capabilities() Capabilities {
+	return Capabilities{OutputFormats: whisperFormats, Progress: true, Local: true}
+}
+
+func (b *whisperCppBackend) Available() error {
+	if _, err := findWhisperCpp(b.config); err != nil {
+		return err
+	}
+	model := whisperCppModelPath(b.config)
+	if _, err := os.Stat(model); err != nil {
+		return fmt.Errorf("GGML model not found: %s (download %s from %s)", model, filepath.Base(model), whisperCppModelURL)
+	}
+	return nil
+}
+
+func (b *whisperCppBackend) Transcribe(ctx context.Context, log *log.Logger, logBuffer *[]logger.LogEntry,
+	logMutex *sync.RWMutex, debugMode bool, inputFile string) (*Result, error) {
+
+	outputDir, err := prepareTranscription(b.config, inputFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFormats(b.Capabilities(), b.config); err != nil {
+		return nil, err
+	}
+	if err := b.Available(); err != nil {
+		return nil, newTranscribeError(ErrorKindSetup, err)
+	}
+	binary, _ := findWhisperCpp(b.config)
+
+	// whisper.cpp reads 16 kHz WAV only; everything else is converted first
+	tempDir, err := os.MkdirTemp("", "koemoji-whispercpp-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	wavFile, err := whisperCppInput(ctx, inputFile, tempDir)
+	if err != nil {
+		return nil, err
+	}
+	if wavFile != inputFile {
+		logger.LogDebug(log, logBuffer, logMutex, debugMode, "Converted %s to 16 kHz WAV for whisper.cpp", filepath.Base(inputFile))
+	}
+
+	cmd := createCommandContext(ctx, binary, whisperCppArgs(b.config, wavFile)...)
+	logger.LogDebug(log, logBuffer, logMutex, debugMode, "whisper.cpp command: %s", strings.Join(cmd.Args, " "))
+
+	segments, err := runWhisperProcess(ctx, b.config, log, logBuffer, logMutex, debugMode, cmd, inputFile)
+	if err != nil {
+		var startErr *processStartError
+		if errors.As(err, &startErr) {
+			if errors.Is(err, exec.ErrNotFound) {
+				return nil, newTranscribeError(ErrorKindSetup, fmt.Errorf("whisper.cpp not found: %w", err))
+			}
+			return nil, err
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		msg := ui.GetMessages(b.config)
+		return nil, fmt.Errorf(msg.TranscribeFail, err)
+	}
+	segments = dropBlankAudio(segments)
+
+	outputFiles, err := writeOutputs(outputDir, inputFile, b.config, segments)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Segments: segments, OutputFiles: outputFiles}, nil
+}
+
+// whisperCppArgs maps our settings to whisper.cpp flags. The outputs are
+// written from the segment lines on stdout, so no -o* flags are passed.
+func whisperCppArgs(c *config.Config, wavFile string) []string {
+	args := []string{
+		"-m", whisperCppModelPath(c),
+		"-l", c.Language,
+	}
+
+	// whisper.cpp has no hotwords; the vocabulary is always the prompt
+	if len(c.Vocabulary) > 0 {
+		args = append(args, "--prompt", c.VocabularyText())
+	}
+
+	// max_cpu_percent: スレッド数を上限に合わせる
+	if cpuLimitActive(c) {
+		args = append(args, "-t", strconv.Itoa(threadCount(c, runtime.NumCPU())))
+	}
+	return append(args, "-f", wavFile)
+}
+
+// findWhisperCpp returns the whisper.cpp binary: whisper_cpp_path when set,
+// otherwise the first of whisperCppCommands on the PATH or of
+// whisperCppBundled next to KoeMoji-Go
+func findWhisperCpp(c *config.Config) (string, error) {
+	if c.WhisperCppPath != "" {
+		path := config.ResolvePath(c.WhisperCppPath)
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("whisper_cpp_path not found: %s", path)
+		}
+		return path, nil
+	}
+
+	for _, name := range whisperCppCommands {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	if exeDir, err := config.GetExecutablePath(); err == nil {
+		for _, name := range whisperCppBundled {
+			if runtime.GOOS == "windows" {
+				name += ".exe"
+			}
+			path := filepath.Join(exeDir, name)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("whisper.cpp not found (install whisper-cli or set whisper_cpp_path)")
+}
+
+// whisperCppModelPath returns the GGML model file of whisper_model
+func whisperCppModelPath(c *config.Config) string {
+	dir := c.WhisperCppModelDir
+	if dir == "" {
+		dir = "./models"
+	}
+	return filepath.Join(config.ResolvePath(dir), "ggml-"+ggmlModelName(c.WhisperModel)+".bin")
+}
+
+// ggmlModelName returns the GGML model name of a whisper_model value.
+// faster-whisper treats "large" as large-v3; the GGML files have no alias.
+func ggmlModelName(model string) string {
+	if model == "large" {
+		return "large-v3"
+	}
+	return model
+}
+
+// whisperCppInput returns a WAV file whisper.cpp can read: inputFile itself
+// when it is 16-bit 16 kHz PCM, otherwise a mono copy in tempDir converted
+// with ffmpeg
+func whisperCppInput(ctx context.Context, inputFile, tempDir string) (string, error) {
+	if strings.EqualFold(filepath.Ext(inputFile), ".wav") {
+		if file, err := os.Open(inputFile); err == nil {
+			info, err := readWAVInfo(file)
+			file.Close()
+			if err == nil && info.sampleRate == 16000 && info.bitDepth == 16 && info.channels <= 2 {
+				return inputFile, nil
+			}
+		}
+	}
+
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return "", newTranscribeError(ErrorKindSetup,
+			fmt.Errorf("ffmpeg is needed to convert %s to 16 kHz WAV for whisper.cpp: %w", filepath.Base(inputFile), err))
+	}
+	wavFile := filepath.Join(tempDir, "input.wav")
+	cmd := createCommandContext(ctx, "ffmpeg", "-nostdin", "-y", "-loglevel", "error",
+		"-i", inputFile, "-ar", "16000", "-ac", "1", "-c:a", "pcm_s16le", wavFile)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", fmt.Errorf("transcription cancelled: %w", ctxErr)
+		}
+		return "", fmt.Errorf("ffmpeg conversion failed: %w, output: %s", err, strings.TrimSpace(string(output)))
+	}
+	return wavFile, nil
+}
+
+// dropBlankAudio removes the segments whisper.cpp prints for silence
+func dropBlankAudio(segments []Segment) []Segment {
+	kept := segments[:0]
+	for _, s := range segments {
+		if s.Text != whisperCppBlankAudio {
+			kept = append(kept, s)
+		}
+	}
+	return kept
+}
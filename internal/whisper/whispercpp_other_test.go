@@ -0,0 +1,71 @@
+//go:build !windows
+// +build !windows
+
+package whisper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/infoHiroki/KoeMoji-Go/internal/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockWhisperCppScript records its arguments in $MOCK_WHISPER_CPP_ARGS and
+// prints segment lines like whisper-cli
+const mockWhisperCppScript = `#!/bin/sh
+echo "$@" > "$MOCK_WHISPER_CPP_ARGS"
+echo "whisper_init_from_file_with_params_no_state: loading model" >&2
+echo ""
+echo "[00:00:00.000 --> 00:00:01.500]   こんにちは"
+echo "[00:00:01.500 --> 00:00:02.000]   [BLANK_AUDIO]"
+echo "[00:00:02.000 --> 00:00:04.000]   今日は晴れです"
+`
+
+func TestWhisperCppBackend_Transcribe(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "whisper-cli")
+	require.NoError(t, os.WriteFile(binary, []byte(mockWhisperCppScript), 0755))
+	argsFile := filepath.Join(dir, "args")
+	t.Setenv("MOCK_WHISPER_CPP_ARGS", argsFile)
+
+	cfg := config.GetDefaultConfig()
+	cfg.TranscriptionBackend = config.BackendWhisperCpp
+	cfg.WhisperCppPath = binary
+	cfg.WhisperCppModelDir = filepath.Join(dir, "models")
+	cfg.WhisperModel = "small"
+	cfg.OutputFormat = "srt"
+	cfg.InputDir = filepath.Join(dir, "input")
+	cfg.OutputDir = filepath.Join(dir, "output")
+	cfg.MaxCpuPercent = 100
+	require.NoError(t, os.MkdirAll(cfg.WhisperCppModelDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.WhisperCppModelDir, "ggml-small.bin"), []byte("model"), 0644))
+	require.NoError(t, os.MkdirAll(cfg.InputDir, 0755))
+	input := filepath.Join(cfg.InputDir, "memo.wav")
+	require.NoError(t, os.WriteFile(input, wavBytes(4*32000, 4*32000), 0644))
+
+	transcriber, err := NewTranscriber(cfg)
+	require.NoError(t, err)
+	var logBuffer []logger.LogEntry
+	var logMutex sync.RWMutex
+	result, err := transcriber.Transcribe(context.Background(), nil, &logBuffer, &logMutex, false, input)
+	require.NoError(t, err)
+
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.Equal(t, "-m "+filepath.Join(cfg.WhisperCppModelDir, "ggml-small.bin")+" -l ja -f "+input+"\n", string(args))
+
+	assert.Equal(t, []Segment{
+		{Start: 0, End: 1500 * time.Millisecond, Text: "こんにちは"},
+		{Start: 2 * time.Second, End: 4 * time.Second, Text: "今日は晴れです"},
+	}, result.Segments)
+	data, err := os.ReadFile(filepath.Join(cfg.OutputDir, "memo.srt"))
+	require.NoError(t, err)
+	assert.Equal(t, "1\n00:00:00,000 --> 00:00:01,500\nこんにちは\n\n2\n00:00:02,000 --> 00:00:04,000\n今日は晴れです\n\n", string(data))
+}
@@ -0,0 +1,84 @@
+package whisper
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infoHiroki/KoeMoji-Go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGGMLModelName(t *testing.T) {
+	assert.Equal(t, "large-v3", ggmlModelName("large"))
+	assert.Equal(t, "large-v2", ggmlModelName("large-v2"))
+	assert.Equal(t, "small.en", ggmlModelName("small.en"))
+}
+
+func TestWhisperCppArgs(t *testing.T) {
+	cfg := config.GetDefaultConfig()
+	cfg.WhisperModel = "medium"
+	cfg.Language = "auto"
+	cfg.WhisperCppModelDir = t.TempDir()
+	cfg.MaxCpuPercent = 100
+
+	assert.Equal(t, []string{
+		"-m", filepath.Join(cfg.WhisperCppModelDir, "ggml-medium.bin"),
+		"-l", "auto",
+		"-f", "/tmp/in.wav",
+	}, whisperCppArgs(cfg, "/tmp/in.wav"))
+
+	cfg.MaxCpuPercent = 50
+	assert.Contains(t, whisperCppArgs(cfg, "/tmp/in.wav"), "-t")
+
+	cfg.Vocabulary = []string{"KoeMoji", "Kubernetes"}
+	cfg.VocabularyMode = config.VocabularyModeHotwords
+	assert.Contains(t, strings.Join(whisperCppArgs(cfg, "/tmp/in.wav"), " "), "--prompt KoeMoji, Kubernetes",
+		"whisper.cpp has no hotwords")
+}
+
+func TestWhisperCppBackend_Available(t *testing.T) {
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "whisper-cli")
+	require.NoError(t, os.WriteFile(binary, []byte("#!/bin/sh\n"), 0755))
+
+	cfg := config.GetDefaultConfig()
+	cfg.TranscriptionBackend = config.BackendWhisperCpp
+	cfg.WhisperModel = "base"
+	cfg.WhisperCppPath = filepath.Join(dir, "missing")
+	cfg.WhisperCppModelDir = dir
+	backend := &whisperCppBackend{config: cfg}
+
+	err := backend.Available()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "whisper_cpp_path")
+
+	cfg.WhisperCppPath = binary
+	err = backend.Available()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ggml-base.bin")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ggml-base.bin"), []byte("model"), 0644))
+	assert.NoError(t, backend.Available())
+}
+
+func TestWhisperCppInput_16kHzWAVIsUsedAsIs(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "memo.wav")
+	require.NoError(t, os.WriteFile(input, wavBytes(32000, 32000), 0644))
+
+	wavFile, err := whisperCppInput(context.Background(), input, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, input, wavFile)
+}
+
+func TestDropBlankAudio(t *testing.T) {
+	segments := dropBlankAudio([]Segment{
+		{End: time.Second, Text: "[BLANK_AUDIO]"},
+		{Start: time.Second, End: 2 * time.Second, Text: "こんにちは"},
+	})
+	assert.Equal(t, []Segment{{Start: time.Second, End: 2 * time.Second, Text: "こんにちは"}}, segments)
+}
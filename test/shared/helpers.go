@@ -38,6 +38,7 @@ func NewTestContext(t *testing.T) *TestContext {
 	inputDir := filepath.Join(tempDir, "input")
 	outputDir := filepath.Join(tempDir, "output")
 	archiveDir := filepath.Join(tempDir, "archive")
+	failedDir := filepath.Join(tempDir, "failed")
 
 	require.NoError(t, os.MkdirAll(inputDir, 0755))
 	require.NoError(t, os.MkdirAll(outputDir, 0755))
@@ -48,6 +49,7 @@ func NewTestContext(t *testing.T) *TestContext {
 	cfg.InputDir = inputDir
 	cfg.OutputDir = outputDir
 	cfg.ArchiveDir = archiveDir
+	cfg.FailedDir = failedDir
 
 	// Create test logger
 	logger := log.New(io.Discard, "", log.LstdFlags)